@@ -20,7 +20,7 @@ func UsageForExtractParametersFromTextApi(service resolver.ISsmParameterService)
 	}
 
 	for ref, param := range resolvedParameters {
-		fmt.Printf("Parameter reference %s -> %s\n", ref, param)
+		fmt.Printf("Parameter reference %s -> %+v\n", ref, param)
 	}
 	fmt.Println()
 }
@@ -42,7 +42,7 @@ func UsageForResolveParameterReferenceList(service resolver.ISsmParameterService
 	}
 
 	for ref, param := range resolvedParameters {
-		fmt.Printf("Parameter reference %s -> %s\n\n", ref, param)
+		fmt.Printf("Parameter reference %s -> %+v\n\n", ref, param)
 	}
 }
 
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	v1 "github.com/parameterResolver/resolver"
+	"github.com/parameterResolver/resolver/plugins"
+	"github.com/parameterResolver/resolver/v2"
+)
+
+//
+// Resolves the parameter placeholders of a document for shell scripts, e.g.
+// resolver -in app.conf.tmpl -out app.conf, or resolver --allow-secure < secrets.tmpl > secrets.env.
+// The document is read from stdin and written to stdout unless -in and -out name files, secure placeholders
+// are left unresolved unless --allow-secure is set. The exit code is the resolver.Status of the failure.
+func main() {
+	inputFileName := flag.String("in", "", "template file to resolve, stdin when empty")
+	outputFileName := flag.String("out", "", "file to write the resolved document to, stdout when empty")
+	region := flag.String("region", "", "AWS region, e.g. eu-west-1")
+	profile := flag.String("profile", "", "profile of the shared AWS config and credentials files")
+	allowSecure := flag.Bool("allow-secure", false, "resolve SecureString parameters and secrets too")
+	format := flag.String("format", "", "format escaping the resolved values, e.g. json or properties")
+	statusJSON := flag.Bool("status-json", false, "write the status of the run as JSON to stderr")
+	pluginFiles := flag.String("plugins", "", "comma separated Go plugins (.so) registering sources, transformers and formats")
+	flag.Parse()
+
+	if flag.NArg() > 0 {
+		log.Fatal("unexpected arguments, the template is read from -in or stdin")
+	}
+
+	for _, plugin := range strings.Split(*pluginFiles, ",") {
+		if len(plugin) == 0 {
+			continue
+		}
+		if err := plugins.Load(plugin); err != nil {
+			exit(err, *statusJSON)
+		}
+	}
+
+	service, err := v1.NewServiceWithOptions(v1.ServiceOptions{Region: *region, Profile: *profile})
+	if err != nil {
+		exit(err, *statusJSON)
+	}
+
+	documentResolver := resolver.New(service, resolver.WithFormat(*format))
+	if !*allowSecure {
+		documentResolver = documentResolver.With(resolver.WithoutSecureParameters())
+	}
+
+	exit(resolve(context.Background(), documentResolver, *inputFileName, *outputFileName), *statusJSON)
+}
+
+// resolves the file inputFileName, or stdin when empty, into the file outputFileName, or stdout when empty
+func resolve(ctx context.Context, documentResolver *resolver.Resolver, inputFileName string, outputFileName string) error {
+	if len(inputFileName) > 0 && len(outputFileName) > 0 {
+		return documentResolver.ResolveFile(ctx, inputFileName, outputFileName)
+	}
+
+	var input io.Reader = os.Stdin
+	if len(inputFileName) > 0 {
+		inputFile, err := os.Open(inputFileName)
+		if err != nil {
+			return err
+		}
+		defer inputFile.Close()
+		input = inputFile
+	}
+
+	if len(outputFileName) == 0 {
+		return documentResolver.Resolve(ctx, input, os.Stdout)
+	}
+
+	// the output file is left alone when resolving fails
+	var output bytes.Buffer
+	if err := documentResolver.Resolve(ctx, input, &output); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(outputFileName, output.Bytes(), 0644)
+}
+
+// exits with the resolver.Status of err as the exit code
+func exit(err error, statusJSON bool) {
+	if statusJSON {
+		v1.WriteStatusJSON(os.Stderr, err)
+	} else if err != nil {
+		log.Println(err)
+	}
+
+	os.Exit(int(resolver.StatusOf(err)))
+}
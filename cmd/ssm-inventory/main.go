@@ -0,0 +1,34 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/parameterResolver/resolver"
+)
+
+//
+// Ansible dynamic inventory script and Salt cmd_json external pillar command serving the parameter tree
+// under the path in the SSM_INVENTORY_PATH environment variable,
+// e.g. SSM_INVENTORY_PATH=/inventory/prod ansible-playbook -i ssm-inventory site.yml.
+// Set SSM_INVENTORY_EXCLUDE_SECURE=true to leave SecureString parameters out.
+// The exit code is the resolver.Status of the failure.
+func main() {
+	path := os.Getenv("SSM_INVENTORY_PATH")
+	if len(path) == 0 {
+		log.Fatal("SSM_INVENTORY_PATH is not set")
+	}
+
+	service, err := resolver.NewService()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = resolver.RunInventory(service, path, os.Args[1:], os.Stdout, resolver.InventoryOptions{
+		ExcludeSecureParameters: os.Getenv("SSM_INVENTORY_EXCLUDE_SECURE") == "true",
+	})
+	if err != nil {
+		log.Println(err)
+		os.Exit(int(resolver.StatusOf(err)))
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/parameterResolver/resolver"
+)
+
+//
+// Renders the template files given as arguments over and over against Parameter Store and prints throughput,
+// error rate and GetParameters consumption, e.g. ssm-soak -concurrency 32 -duration 5m templates/*.conf.
+// The exit code is the resolver.Status of the run, StatusPartial when some renders failed.
+func main() {
+	options := resolver.SoakOptions{}
+	flag.IntVar(&options.Concurrency, "concurrency", 1, "number of concurrent renders")
+	flag.DurationVar(&options.Duration, "duration", 0, "how long to run, e.g. 5m")
+	flag.Int64Var(&options.MaxRenders, "renders", 0, "number of renders to run")
+	flag.BoolVar(&options.ResolveOptions.IgnoreSecureParameters, "ignore-secure", false, "leave SecureString placeholders unresolved")
+	statusJSON := flag.Bool("status-json", false, "write the status of the run as JSON to stderr")
+	flag.Parse()
+
+	corpus := []string{}
+	for _, fileName := range flag.Args() {
+		template, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		corpus = append(corpus, string(template))
+	}
+
+	service, err := resolver.NewService()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	report, err := resolver.SoakTest(context.Background(), service, corpus, options)
+	if err != nil {
+		exit(err, *statusJSON)
+	}
+
+	fmt.Printf("renders:              %d in %s (%.1f/s)\n", report.Renders, report.Elapsed, report.RendersPerSecond())
+	fmt.Printf("errors:               %d (%.2f%%)\n", report.Errors, 100*report.ErrorRate())
+	fmt.Printf("GetParameters calls:  %d (%.1f/s)\n", report.GetParametersCalls, report.GetParametersCallsPerSecond())
+	fmt.Printf("parameters fetched:   %d\n", report.ParametersFetched)
+	if report.FirstError != nil {
+		fmt.Printf("first error:          %s\n", report.FirstError)
+	}
+
+	switch {
+	case report.Errors == 0:
+		exit(nil, *statusJSON)
+	case report.Errors < report.Renders:
+		exit(&resolver.StatusError{Status: resolver.StatusPartial, Err: report.FirstError}, *statusJSON)
+	default:
+		exit(report.FirstError, *statusJSON)
+	}
+}
+
+// exits with the resolver.Status of err as the exit code
+func exit(err error, statusJSON bool) {
+	if statusJSON {
+		resolver.WriteStatusJSON(os.Stderr, err)
+	} else if err != nil {
+		log.Println(err)
+	}
+
+	os.Exit(int(resolver.StatusOf(err)))
+}
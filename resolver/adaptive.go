@@ -0,0 +1,226 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+//
+// Error codes of AWS responses telling that requests are throttled
+var throttlingErrorCodes = []string{
+	"ThrottlingException",
+	"ThrottledException",
+	"TooManyRequestsException",
+	"RequestLimitExceeded",
+}
+
+//
+// Options of the AIMD controller of a service created with NewAdaptiveService
+type AdaptiveConcurrencyOptions struct {
+	// Number of requests allowed in flight at first, 1 when not positive
+	InitialLimit int
+
+	// Upper bound of the limit, 64 when not positive
+	MaxLimit int
+
+	// Factor the limit is multiplied by when a request is throttled, 0.5 when not between 0 and 1
+	DecreaseFactor float64
+
+	// Number of times a throttled request is retried once the limit is decreased
+	ThrottleRetries int
+
+	// Delay before the first retry of a throttled request, doubled for every following retry (100ms when zero)
+	ThrottleBackoff time.Duration
+}
+
+//
+// AdaptiveService limits the number of concurrent requests to the wrapped service with an AIMD controller:
+// the limit grows by one request every time a full limit of requests succeeds and is cut by DecreaseFactor
+// when a request is throttled, at most once for the requests started before the previous cut, keeping concurrent renders of large fleets near the Parameter Store quota
+// without tuning static concurrency options. Share one AdaptiveService between all the renders of a process.
+type AdaptiveService struct {
+	service ISsmParameterService
+	options AdaptiveConcurrencyOptions
+
+	mutex    sync.Mutex
+	released chan struct{}
+	limit    float64
+	inFlight int
+	window   int
+}
+
+//
+// Wraps service into an AdaptiveService controlled according to AdaptiveConcurrencyOptions.
+func NewAdaptiveService(service ISsmParameterService, options AdaptiveConcurrencyOptions) *AdaptiveService {
+	if options.InitialLimit < 1 {
+		options.InitialLimit = 1
+	}
+	if options.MaxLimit < 1 {
+		options.MaxLimit = 64
+	}
+	if options.InitialLimit > options.MaxLimit {
+		options.InitialLimit = options.MaxLimit
+	}
+	if options.DecreaseFactor <= 0 || options.DecreaseFactor >= 1 {
+		options.DecreaseFactor = 0.5
+	}
+	if options.ThrottleBackoff <= 0 {
+		options.ThrottleBackoff = defaultWriteRetryBackoff
+	}
+
+	return &AdaptiveService{
+		service:  service,
+		options:  options,
+		released: make(chan struct{}),
+		limit:    float64(options.InitialLimit),
+	}
+}
+
+//
+// Returns the current number of requests allowed in flight.
+func (a *AdaptiveService) Limit() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return int(a.limit)
+}
+
+func (a *AdaptiveService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	var parameters map[string]SsmParameterInfo
+	err := a.do(ctx, func() error {
+		var err error
+		// the wrapped service may rewrite the slice
+		parameters, err = a.service.callGetParameters(ctx, append([]string{}, parameterReferences...))
+		return err
+	})
+
+	return parameters, err
+}
+
+func (a *AdaptiveService) callGetParametersByPath(ctx context.Context, path string, recursive bool) ([]SsmParameterInfo, error) {
+	var parameters []SsmParameterInfo
+	err := a.do(ctx, func() error {
+		var err error
+		parameters, err = a.service.callGetParametersByPath(ctx, path, recursive)
+		return err
+	})
+
+	return parameters, err
+}
+
+func (a *AdaptiveService) callGetParameterMetadata(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	var parameters map[string]SsmParameterInfo
+	err := a.do(ctx, func() error {
+		var err error
+		parameters, err = getParameterMetadata(ctx, a.service, append([]string{}, parameterReferences...))
+		return err
+	})
+
+	return parameters, err
+}
+
+func (a *AdaptiveService) callGetPublicParameter(ctx context.Context, parameterReference string) (SsmParameterInfo, error) {
+	var param SsmParameterInfo
+	err := a.do(ctx, func() error {
+		var err error
+		param, err = getPublicParameter(ctx, a.service, parameterReference)
+		return err
+	})
+
+	return param, err
+}
+
+func (a *AdaptiveService) callGetSecretValue(ctx context.Context, secretId string) (SsmParameterInfo, error) {
+	var secret SsmParameterInfo
+	err := a.do(ctx, func() error {
+		var err error
+		secret, err = getSecretValue(ctx, a.service, secretId)
+		return err
+	})
+
+	return secret, err
+}
+
+// runs call within the limit, retrying it with exponential backoff while it is throttled
+func (a *AdaptiveService) do(ctx context.Context, call func() error) error {
+	backoff := a.options.ThrottleBackoff
+
+	for retry := 0; ; retry++ {
+		window, err := a.acquire(ctx)
+		if err != nil {
+			return err
+		}
+		err = call()
+		throttled := isThrottlingError(err)
+		a.release(window, throttled)
+
+		if !throttled || retry >= a.options.ThrottleRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// waits until fewer requests than the limit are in flight or ctx is done, returns the window the request starts in
+func (a *AdaptiveService) acquire(ctx context.Context) (int, error) {
+	a.mutex.Lock()
+	for a.inFlight >= int(a.limit) {
+		released := a.released
+		a.mutex.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+
+		a.mutex.Lock()
+	}
+	defer a.mutex.Unlock()
+	a.inFlight++
+
+	return a.window, nil
+}
+
+// adjusts the limit to the outcome of a request started in window and wakes up the requests waiting for a slot;
+// the limit is cut once per window so that a burst of concurrent throttled requests does not collapse it
+func (a *AdaptiveService) release(window int, throttled bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.inFlight--
+	if throttled {
+		if window == a.window {
+			a.window++
+			a.limit *= a.options.DecreaseFactor
+			if a.limit < 1 {
+				a.limit = 1
+			}
+		}
+	} else {
+		a.limit += 1 / a.limit
+		if a.limit > float64(a.options.MaxLimit) {
+			a.limit = float64(a.options.MaxLimit)
+		}
+	}
+
+	close(a.released)
+	a.released = make(chan struct{})
+}
+
+func isThrottlingError(err error) bool {
+	var coded interface{ Code() string }
+	if !errors.As(err, &coded) {
+		return false
+	}
+
+	return containsString(throttlingErrorCodes, coded.Code())
+}
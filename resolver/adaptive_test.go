@@ -0,0 +1,146 @@
+package resolver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type throttlingError struct{}
+
+func (throttlingError) Error() string { return "ThrottlingException: Rate exceeded" }
+func (throttlingError) Code() string  { return "ThrottlingException" }
+
+//
+// Mocked service throttling its first requests and recording the highest number of requests in flight
+type throttlingService struct {
+	ServiceMockedObjectWithRecords
+	mutex       sync.Mutex
+	throttle    int
+	inFlight    int
+	maxInFlight int
+}
+
+func (m *throttlingService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	m.mutex.Lock()
+	m.inFlight++
+	if m.inFlight > m.maxInFlight {
+		m.maxInFlight = m.inFlight
+	}
+	throttled := m.throttle > 0
+	m.throttle--
+	m.mutex.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	m.mutex.Lock()
+	m.inFlight--
+	m.mutex.Unlock()
+
+	if throttled {
+		return nil, throttlingError{}
+	}
+	return m.ServiceMockedObjectWithRecords.callGetParameters(ctx, parameterReferences)
+}
+
+func TestAdaptiveServiceIncreasesLimitWhenHealthy(t *testing.T) {
+	serviceObject := &throttlingService{ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})}
+	adaptive := NewAdaptiveService(serviceObject, AdaptiveConcurrencyOptions{InitialLimit: 2, MaxLimit: 4})
+
+	for i := 0; i < 20; i++ {
+		output, err := ResolveParametersInText(adaptive, "{{ssm:/app/host}}", ResolveOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, "db.internal", output)
+	}
+
+	assert.Equal(t, 4, adaptive.Limit())
+}
+
+func TestAdaptiveServiceBacksOffWhenThrottled(t *testing.T) {
+	serviceObject := &throttlingService{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+			"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+		}),
+		throttle: 3,
+	}
+	adaptive := NewAdaptiveService(serviceObject, AdaptiveConcurrencyOptions{
+		InitialLimit:    8,
+		MaxLimit:        8,
+		ThrottleRetries: 3,
+		ThrottleBackoff: time.Millisecond,
+	})
+
+	output, err := ResolveParametersInText(adaptive, "{{ssm:/app/host}}", ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "db.internal", output)
+	// 8 halved three times, then increased by the successful request
+	assert.Equal(t, 2, adaptive.Limit())
+}
+
+func TestAdaptiveServiceGivesUpWhenThrottled(t *testing.T) {
+	serviceObject := &throttlingService{ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{}), throttle: 10}
+	adaptive := NewAdaptiveService(serviceObject, AdaptiveConcurrencyOptions{ThrottleRetries: 1, ThrottleBackoff: time.Millisecond})
+
+	_, err := ResolveParametersInText(adaptive, "{{ssm:/app/host}}", ResolveOptions{})
+
+	assert.Equal(t, throttlingError{}, err)
+	assert.Equal(t, 8, serviceObject.throttle)
+}
+
+func TestAdaptiveServiceLimitsConcurrency(t *testing.T) {
+	serviceObject := &throttlingService{ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})}
+	adaptive := NewAdaptiveService(serviceObject, AdaptiveConcurrencyOptions{InitialLimit: 3, MaxLimit: 3})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := ResolveParametersInText(adaptive, "{{ssm:/app/host}}", ResolveOptions{})
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.True(t, serviceObject.maxInFlight <= 3)
+}
+
+func TestAdaptiveServiceDecreasesOncePerWindow(t *testing.T) {
+	serviceObject := &throttlingService{ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{}), throttle: 8}
+	adaptive := NewAdaptiveService(serviceObject, AdaptiveConcurrencyOptions{InitialLimit: 8, MaxLimit: 8})
+
+	windows := make([]int, 8)
+	for i := range windows {
+		window, err := adaptive.acquire(context.Background())
+		assert.Nil(t, err)
+		windows[i] = window
+	}
+	for _, window := range windows {
+		adaptive.release(window, true)
+	}
+
+	assert.Equal(t, 4, adaptive.Limit())
+}
+
+func TestAdaptiveServiceStopsWaitingWhenContextIsDone(t *testing.T) {
+	adaptive := NewAdaptiveService(&throttlingService{}, AdaptiveConcurrencyOptions{InitialLimit: 1, MaxLimit: 1})
+	window, err := adaptive.acquire(context.Background())
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = adaptive.acquire(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	adaptive.release(window, false)
+	_, err = adaptive.acquire(context.Background())
+	assert.Nil(t, err)
+}
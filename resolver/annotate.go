@@ -0,0 +1,46 @@
+package resolver
+
+import "context"
+
+//
+// Region [Start, End) of a document rendered by RenderAnnotated, in bytes, holding the value of Reference
+type AnnotatedSpan struct {
+	Start     int
+	End       int
+	Reference string
+}
+
+//
+// Takes text document and resolves all parameters in it according to ResolveOptions like ResolveParametersInText.
+// Besides the resolved document it returns the spans of the document which came from parameter values, ordered by
+// position, so that review tools can highlight injected values.
+func RenderAnnotated(
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, []AnnotatedSpan, error) {
+
+	return RenderAnnotatedWithContext(context.Background(), service, input, options)
+}
+
+//
+// Same as RenderAnnotated, but the SSM requests are canceled when ctx is done.
+func RenderAnnotatedWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, []AnnotatedSpan, error) {
+
+	if err := checkDefaultPlaceholderSyntax(options, "RenderAnnotated"); err != nil {
+		return "", nil, err
+	}
+
+	spans := []AnnotatedSpan{}
+	resolvedText, err := resolveText(ctx, service, input, options, func(substituted substitution) {
+		spans = append(spans, AnnotatedSpan{Start: substituted.start, End: substituted.end, Reference: substituted.reference})
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return resolvedText, spans, nil
+}
@@ -0,0 +1,58 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderAnnotated(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host":     {Name: "/app/host", Type: stringType, Value: "example.com"},
+		"ssm-secure:/app/p": {Name: "/app/p", Type: secureStringType, Value: "it's"},
+	})
+
+	text := "p={{ssm-secure:/app/p | shellquote}} host={{ ssm:/app/host }}"
+	output, spans, err := RenderAnnotated(&serviceObject, text, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `p='it'\''s' host=example.com`, output)
+	assert.Equal(t, []AnnotatedSpan{
+		{Start: 2, End: 11, Reference: "ssm-secure:/app/p"},
+		{Start: 17, End: 28, Reference: "ssm:/app/host"},
+	}, spans)
+
+	for _, span := range spans {
+		assert.NotContains(t, output[span.Start:span.End], "{{")
+	}
+}
+
+func TestRenderAnnotatedIgnoreSecureParams(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+	})
+
+	output, spans, err := RenderAnnotated(&serviceObject, "{{ssm-secure:/app/p}} {{ssm:/app/host}}", ResolveOptions{
+		IgnoreSecureParameters: true,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "{{ssm-secure:/app/p}} example.com", output)
+	assert.Equal(t, []AnnotatedSpan{{Start: 22, End: 33, Reference: "ssm:/app/host"}}, spans)
+}
+
+func TestRenderAnnotatedWithFormatAndWatermark(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/jaas": {Name: "/app/jaas", Type: stringType, Value: "a=b", Version: 3},
+	})
+
+	output, spans, err := RenderAnnotated(&serviceObject, "# client\njaas={{ssm:/app/jaas}}", ResolveOptions{
+		Format:           propertiesFormat,
+		WatermarkFormats: []string{propertiesFormat},
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, spans, 1)
+	assert.Equal(t, `a\=b`, output[spans[0].Start:spans[0].End])
+	assert.Contains(t, output[:spans[0].Start], "/app/jaas")
+}
@@ -0,0 +1,282 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+//
+// Cache stores parameters fetched from Parameter Store by name, so that resolve calls sharing it do not request
+// them again, e.g. backed by Redis, memcached or files. Set ResolveOptions.Cache to use one.
+// Secrets Manager secrets are stored by their reference, e.g. secretsmanager:prod/app/db.
+// Values of secure parameters and secrets are stored decrypted: a backend keeping them out of process should encrypt them.
+// Implementations are called concurrently. A backend failing to read or store a parameter should report
+// a cache miss or drop the parameter, the resolver then fetches it from Parameter Store.
+type Cache interface {
+	// Returns the parameter named name when it was stored less than maxAge ago, maxAge is ignored when not positive.
+	Get(name string, maxAge time.Duration) (SsmParameterInfo, bool)
+
+	// Stores the parameter named name, just fetched.
+	Set(name string, param SsmParameterInfo)
+
+	// Drops the parameter named name, e.g. because it was deleted from Parameter Store.
+	Invalidate(name string)
+}
+
+//
+// MemoryCache is a Cache keeping parameters in memory for TTL, so that resolving the same templates again
+// does not request their parameters until they expire. Share one MemoryCache between the resolve calls
+// that may serve each other's values.
+type MemoryCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mutex     sync.Mutex
+	entries   map[string]memoryCacheEntry
+	purgeSize int
+}
+
+type memoryCacheEntry struct {
+	param     SsmParameterInfo
+	fetchedAt time.Time
+}
+
+//
+// Creates a MemoryCache serving parameters for ttl after they are fetched.
+func NewMemoryCache(ttl time.Duration) *MemoryCache {
+	return &MemoryCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]memoryCacheEntry{},
+	}
+}
+
+//
+// Returns the parameter named name when it was fetched less than the TTL and maxAge ago.
+// maxAge is ignored when not positive.
+func (c *MemoryCache) Get(name string, maxAge time.Duration) (SsmParameterInfo, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, found := c.entries[name]
+	if !found {
+		return SsmParameterInfo{}, false
+	}
+
+	age := c.now().Sub(entry.fetchedAt)
+	if age >= c.ttl || (maxAge > 0 && age >= maxAge) {
+		return SsmParameterInfo{}, false
+	}
+
+	return entry.param, true
+}
+
+//
+// Stores the parameter named name, just fetched.
+func (c *MemoryCache) Set(name string, param SsmParameterInfo) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	c.entries[name] = memoryCacheEntry{param: param, fetchedAt: now}
+
+	// expired entries are dropped whenever the cache doubles, so parameters nobody asks for again do not pile up
+	if len(c.entries) > 2*c.purgeSize {
+		c.purgeExpired(now)
+	}
+}
+
+//
+// Drops the expired parameters, e.g. periodically in a long-running process whose cache rarely grows.
+func (c *MemoryCache) PurgeExpired() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.purgeExpired(c.now())
+}
+
+// drops the entries fetched at least the TTL before now, the mutex is held by the caller
+func (c *MemoryCache) purgeExpired(now time.Time) {
+	for entryName, entry := range c.entries {
+		if now.Sub(entry.fetchedAt) >= c.ttl {
+			delete(c.entries, entryName)
+		}
+	}
+	c.purgeSize = len(c.entries)
+}
+
+//
+// Drops the parameter named name.
+func (c *MemoryCache) Invalidate(name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, name)
+}
+
+// returns the parameters of parameterReferences from options.Cache and fetches the others, storing them in the cache.
+// A reference with a max-age in maxAges is fetched when its cached value is older. Parameters found missing are dropped
+// from the cache, so that they are not served to placeholders without a max-age either. Environment variables are
+// never cached.
+func fetchParameters(
+	ctx context.Context,
+	service ISsmParameterService,
+	parameterReferences []string,
+	options ResolveOptions,
+	maxAges map[string]time.Duration) (map[string]SsmParameterInfo, error) {
+
+	if options.Cache == nil {
+		return getParametersFromServices(ctx, service, parameterReferences, options)
+	}
+
+	resolvedParametersMap := map[string]SsmParameterInfo{}
+	missingReferences := []string{}
+	for _, ref := range parameterReferences {
+		if isEnvironmentReference(ref) {
+			missingReferences = append(missingReferences, ref)
+		} else if param, cached := options.Cache.Get(cacheKey(ref), maxAges[ref]); cached {
+			resolvedParametersMap[ref] = param
+		} else {
+			missingReferences = append(missingReferences, ref)
+		}
+	}
+
+	if len(missingReferences) == 0 {
+		return resolvedParametersMap, nil
+	}
+
+	fetchedParameters, err := getParametersFromServices(ctx, service, missingReferences, options)
+	if err != nil {
+		var missingParametersError *MissingParametersError
+		if errors.As(err, &missingParametersError) {
+			for _, name := range missingParametersError.Names {
+				options.Cache.Invalidate(name)
+			}
+		}
+		return nil, err
+	}
+
+	for ref, param := range fetchedParameters {
+		if !isEnvironmentReference(ref) {
+			options.Cache.Set(cacheKey(ref), param)
+		}
+		resolvedParametersMap[ref] = param
+	}
+
+	return resolvedParametersMap, nil
+}
+
+// fetches parameterReferences from SSM Parameter Store, Secrets Manager, the environment or a registered source,
+// depending on their prefix. Secrets, public parameters and values of sources are requested one by one, after
+// the parameters, and the references missing from all the sources are reported together.
+func getParametersFromServices(
+	ctx context.Context,
+	service ISsmParameterService,
+	parameterReferences []string,
+	options ResolveOptions) (map[string]SsmParameterInfo, error) {
+
+	parameterReferencesToFetch := []string{}
+	secretReferences := []string{}
+	environmentReferences := []string{}
+	publicReferences := []string{}
+	sourceReferences := []string{}
+	for _, ref := range parameterReferences {
+		switch {
+		case isSecretReference(ref):
+			secretReferences = append(secretReferences, ref)
+		case isEnvironmentReference(ref):
+			environmentReferences = append(environmentReferences, ref)
+		case isPublicParameterReference(ref):
+			publicReferences = append(publicReferences, ref)
+		case isSourceReference(ref):
+			sourceReferences = append(sourceReferences, ref)
+		default:
+			parameterReferencesToFetch = append(parameterReferencesToFetch, ref)
+		}
+	}
+
+	if err := checkPublicParameterReferences(publicReferences, options); err != nil {
+		return nil, err
+	}
+
+	if len(secretReferences) == 0 && len(environmentReferences) == 0 && len(publicReferences) == 0 && len(sourceReferences) == 0 {
+		return getParametersFromSsmParameterStore(ctx, service, parameterReferencesToFetch, options.MaxConcurrency)
+	}
+
+	resolvedParametersMap := map[string]SsmParameterInfo{}
+	missingNames := []string{}
+	// adds the parameters of one source, the references missing from it are reported with those of the others
+	collect := func(parameters map[string]SsmParameterInfo, err error) error {
+		var missingParametersError *MissingParametersError
+		if errors.As(err, &missingParametersError) {
+			missingNames = append(missingNames, missingParametersError.Names...)
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		for ref, param := range parameters {
+			resolvedParametersMap[ref] = param
+		}
+		return nil
+	}
+
+	if err := collect(getEnvironmentVariables(environmentReferences, options)); err != nil {
+		return nil, err
+	}
+
+	if err := collect(getParametersFromSsmParameterStore(ctx, service, parameterReferencesToFetch, options.MaxConcurrency)); err != nil {
+		return nil, err
+	}
+
+	for _, ref := range secretReferences {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		secret, err := getSecretValue(ctx, service, extractParameterNameFromReference(ref))
+		if err := collect(map[string]SsmParameterInfo{ref: secret}, err); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ref := range publicReferences {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		param, err := getPublicParameter(ctx, service, ref)
+		if err := collect(map[string]SsmParameterInfo{ref: param}, err); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ref := range sourceReferences {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		param, err := getSourceValue(ctx, ref)
+		if err := collect(map[string]SsmParameterInfo{ref: param}, err); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(missingNames) > 0 {
+		return nil, newMissingParametersError(missingNames)
+	}
+
+	return resolvedParametersMap, nil
+}
+
+// returns the name parameterReference is cached by: the parameter name, or the whole reference for a secret
+// or a value of a registered source, which can have the name of a parameter
+func cacheKey(parameterReference string) string {
+	if isSecretReference(parameterReference) || isSourceReference(parameterReference) {
+		return parameterReference
+	}
+
+	return extractParameterNameFromReference(parameterReference)
+}
@@ -0,0 +1,157 @@
+package resolver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCacheServesRepeatLookups(t *testing.T) {
+	serviceObject := &countingService{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+			"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+		}),
+	}
+
+	now := time.Now()
+	cache := NewMemoryCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	options := ResolveOptions{Cache: cache}
+
+	for i := 0; i < 3; i++ {
+		resolved, err := ResolveParametersInTextWithContext(context.Background(), serviceObject, "{{ssm:/app/host}}", options)
+		assert.Nil(t, err)
+		assert.Equal(t, "example.com", resolved)
+	}
+	assert.Equal(t, 1, serviceObject.calls)
+
+	now = now.Add(time.Minute)
+	_, err := ResolveParametersInTextWithContext(context.Background(), serviceObject, "{{ssm:/app/host}}", options)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, serviceObject.calls)
+}
+
+func TestMemoryCacheHonorsMaxAge(t *testing.T) {
+	serviceObject := &countingService{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+			"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+		}),
+	}
+
+	now := time.Now()
+	cache := NewMemoryCache(time.Hour)
+	cache.now = func() time.Time { return now }
+	options := ResolveOptions{Cache: cache}
+
+	_, err := ResolveParametersInTextWithContext(context.Background(), serviceObject, "{{ssm:/app/host}}", options)
+	assert.Nil(t, err)
+
+	now = now.Add(time.Minute)
+	_, err = ResolveParametersInTextWithContext(context.Background(), serviceObject, "{{ssm:/app/host}}", options)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, serviceObject.calls)
+
+	_, err = ResolveParametersInTextWithContext(context.Background(), serviceObject, "{{ssm:/app/host | max-age=30s}}", options)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, serviceObject.calls)
+}
+
+func TestMemoryCachePurgesExpiredEntries(t *testing.T) {
+	now := time.Now()
+	cache := NewMemoryCache(time.Second)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("/a", SsmParameterInfo{Name: "/a"})
+	now = now.Add(time.Second)
+	cache.Set("/b", SsmParameterInfo{Name: "/b"})
+	cache.Set("/c", SsmParameterInfo{Name: "/c"})
+
+	_, found := cache.Get("/a", 0)
+	assert.False(t, found)
+	assert.Equal(t, 2, len(cache.entries))
+
+	now = now.Add(time.Second)
+	cache.PurgeExpired()
+	assert.Equal(t, 0, len(cache.entries))
+}
+
+//
+// Cache storing parameters in a map without expiry, recording invalidations
+type mapCache struct {
+	mutex       sync.Mutex
+	parameters  map[string]SsmParameterInfo
+	invalidated []string
+}
+
+func (c *mapCache) Get(name string, maxAge time.Duration) (SsmParameterInfo, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	param, found := c.parameters[name]
+	return param, found
+}
+
+func (c *mapCache) Set(name string, param SsmParameterInfo) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.parameters[name] = param
+}
+
+func (c *mapCache) Invalidate(name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.parameters, name)
+	c.invalidated = append(c.invalidated, name)
+}
+
+func TestCustomCacheBackend(t *testing.T) {
+	serviceObject := &countingService{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+			"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+		}),
+	}
+	cache := &mapCache{parameters: map[string]SsmParameterInfo{
+		"/app/port": {Name: "/app/port", Type: stringType, Value: "8080"},
+	}}
+
+	resolved, err := ResolveParametersInTextWithContext(context.Background(), serviceObject,
+		"{{ssm:/app/host}}:{{ssm:/app/port}}", ResolveOptions{Cache: cache})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com:8080", resolved)
+	assert.Equal(t, 1, serviceObject.calls)
+	assert.Equal(t, "example.com", cache.parameters["/app/host"].Value)
+}
+
+//
+// Mocked service reporting every parameter as missing from Parameter Store
+type deletedParametersService struct {
+	ServiceMockedObjectWithRecords
+}
+
+func (m *deletedParametersService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	names := []string{}
+	for _, ref := range parameterReferences {
+		names = append(names, extractParameterNameFromReference(ref))
+	}
+	return nil, newMissingParametersError(names)
+}
+
+func TestCacheInvalidatesMissingParameters(t *testing.T) {
+	serviceObject := &deletedParametersService{}
+
+	now := time.Now()
+	cache := NewMemoryCache(time.Hour)
+	cache.now = func() time.Time { return now }
+	cache.Set("/app/deleted", SsmParameterInfo{Name: "/app/deleted", Type: stringType, Value: "stale"})
+	now = now.Add(time.Minute)
+
+	_, err := ResolveParametersInTextWithContext(context.Background(), serviceObject,
+		"{{ssm:/app/deleted | max-age=30s}}", ResolveOptions{Cache: cache})
+	assert.NotNil(t, err)
+
+	_, found := cache.Get("/app/deleted", 0)
+	assert.False(t, found)
+}
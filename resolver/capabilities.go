@@ -0,0 +1,94 @@
+package resolver
+
+import (
+	"reflect"
+	"sort"
+)
+
+//
+// Version of ResolveOptions, incremented whenever options are added or change meaning
+const OptionsVersion = 4
+
+//
+// Capabilities of the resolver linked into a process, for orchestration layers to feature-detect across
+// mixed-version fleets rather than assume them from the version they were built against, e.g. to check whether
+// a template using {{ssm:name | each=...}} can be sent to a node. Every list is sorted.
+type ResolverCapabilities struct {
+	OptionsVersion int `json:"optionsVersion"`
+
+	// Prefixes of the parameter references in placeholders, e.g. ssm-secure:. The render: prefix is only resolved
+	// by RenderSet.
+	PlaceholderPrefixes []string `json:"placeholderPrefixes"`
+
+	// Transformers and constraints that can be listed among the modifiers of a placeholder, the ones taking
+	// an argument as name=argument
+	Transformers []string `json:"transformers"`
+	Constraints  []string `json:"constraints"`
+
+	// Modifiers of a placeholder that are neither transformers nor constraints, e.g. default or [index]
+	Modifiers []string `json:"modifiers"`
+
+	// Formats that can be selected with ResolveOptions.Format, including the registered ones
+	Formats []string `json:"formats"`
+
+	// Names of the fields of ResolveOptions
+	Options []string `json:"options"`
+}
+
+//
+// Returns the capabilities of this version of the resolver.
+func Capabilities() ResolverCapabilities {
+	capabilities := ResolverCapabilities{
+		OptionsVersion:      OptionsVersion,
+		PlaceholderPrefixes: []string{ssmNonSecurePrefix, ssmSecurePrefix, secretsManagerPrefix, envPrefix, renderPrefix},
+		Transformers:        []string{},
+		Constraints:         []string{},
+		Modifiers:           []string{defaultModifier, "[index]"},
+		Formats:             []string{},
+		Options:             []string{},
+	}
+
+	transformersMutex.RLock()
+	for name := range transformers {
+		capabilities.Transformers = append(capabilities.Transformers, name)
+	}
+	transformersMutex.RUnlock()
+	for name := range argumentTransformers {
+		capabilities.Transformers = append(capabilities.Transformers, name)
+	}
+	for name := range constraints {
+		capabilities.Constraints = append(capabilities.Constraints, name)
+	}
+
+	sourcesMutex.RLock()
+	for prefix := range sources {
+		capabilities.PlaceholderPrefixes = append(capabilities.PlaceholderPrefixes, prefix)
+	}
+	sourcesMutex.RUnlock()
+
+	formatsMutex.RLock()
+	for name := range formats {
+		capabilities.Formats = append(capabilities.Formats, name)
+	}
+	formatsMutex.RUnlock()
+
+	optionsType := reflect.TypeOf(ResolveOptions{})
+	for i := 0; i < optionsType.NumField(); i++ {
+		if field := optionsType.Field(i); field.PkgPath == "" {
+			capabilities.Options = append(capabilities.Options, field.Name)
+		}
+	}
+
+	for _, names := range [][]string{
+		capabilities.PlaceholderPrefixes,
+		capabilities.Transformers,
+		capabilities.Constraints,
+		capabilities.Modifiers,
+		capabilities.Formats,
+		capabilities.Options,
+	} {
+		sort.Strings(names)
+	}
+
+	return capabilities
+}
@@ -0,0 +1,34 @@
+package resolver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilities(t *testing.T) {
+	capabilities := Capabilities()
+
+	assert.Equal(t, OptionsVersion, capabilities.OptionsVersion)
+	assert.Equal(t, []string{"env:", "render:", "secretsmanager:", "ssm-secure:", "ssm:"}, capabilities.PlaceholderPrefixes)
+	assert.Contains(t, capabilities.Transformers, shellQuoteTransformer)
+	assert.Contains(t, capabilities.Transformers, eachTransformer)
+	assert.Contains(t, capabilities.Constraints, typeConstraint)
+	assert.Equal(t, []string{"[index]", defaultModifier}, capabilities.Modifiers)
+	assert.Contains(t, capabilities.Formats, jsonFormat)
+	assert.Contains(t, capabilities.Options, "IgnoreSecureParameters")
+	assert.NotContains(t, capabilities.Options, "skipMissingParameter")
+
+	RegisterFormat("capabilities-test", escapePropertiesValue)
+	defer func() {
+		formatsMutex.Lock()
+		delete(formats, "capabilities-test")
+		formatsMutex.Unlock()
+	}()
+	assert.Contains(t, Capabilities().Formats, "capabilities-test")
+
+	encoded, err := json.Marshal(capabilities)
+	assert.Nil(t, err)
+	assert.Contains(t, string(encoded), `"optionsVersion":4`)
+}
@@ -0,0 +1,99 @@
+package resolver
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
+)
+
+const pemLeafTransformer = "pem-leaf"
+const pemChainTransformer = "pem-chain"
+const oneLineTransformer = "oneline"
+const pkcs8Transformer = "pkcs8"
+
+// returns the PEM blocks of type CERTIFICATE found in value
+func decodeCertificateBlocks(value string) ([]*pem.Block, error) {
+	certificates := []*pem.Block{}
+
+	rest := []byte(value)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			certificates = append(certificates, block)
+		}
+	}
+
+	if len(certificates) == 0 {
+		return nil, errors.New("value does not contain a PEM encoded certificate")
+	}
+
+	return certificates, nil
+}
+
+// returns the first (leaf) certificate of a PEM certificate chain
+func pemLeaf(value string) (string, error) {
+	certificates, err := decodeCertificateBlocks(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(certificates[0])), nil
+}
+
+// returns the certificates following the leaf in a PEM certificate chain, i.e. the intermediates
+func pemChain(value string) (string, error) {
+	certificates, err := decodeCertificateBlocks(value)
+	if err != nil {
+		return "", err
+	}
+
+	chain := ""
+	for _, certificate := range certificates[1:] {
+		chain += string(pem.EncodeToMemory(certificate))
+	}
+
+	return chain, nil
+}
+
+// replaces line breaks with the \n escape sequence, the form expected by single-line env files and JSON strings
+func oneLine(value string) (string, error) {
+	value = strings.TrimRight(value, "\r\n")
+	value = strings.Replace(value, "\r\n", "\n", -1)
+	return strings.Replace(value, "\n", "\\n", -1), nil
+}
+
+// converts a PEM encoded PKCS#1 (RSA) or SEC 1 (EC) private key into a PEM encoded PKCS#8 private key
+func pkcs8(value string) (string, error) {
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		return "", errors.New("value does not contain a PEM encoded private key")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		return string(pem.EncodeToMemory(block)), nil
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return "", errors.New("unsupported PEM block type " + block.Type)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
@@ -0,0 +1,74 @@
+package resolver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCertificatePem(t *testing.T, commonName string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	assert.Nil(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestPemLeafAndChain(t *testing.T) {
+	leaf := newTestCertificatePem(t, "leaf")
+	intermediate := newTestCertificatePem(t, "intermediate")
+	root := newTestCertificatePem(t, "root")
+
+	output, err := applyTransformers(leaf+intermediate+root, []string{pemLeafTransformer})
+	assert.Nil(t, err)
+	assert.Equal(t, leaf, output)
+
+	output, err = applyTransformers(leaf+intermediate+root, []string{pemChainTransformer})
+	assert.Nil(t, err)
+	assert.Equal(t, intermediate+root, output)
+
+	_, err = applyTransformers("not a certificate", []string{pemLeafTransformer})
+	assert.NotNil(t, err)
+}
+
+func TestOneLine(t *testing.T) {
+	output, err := applyTransformers("line1\r\nline2\nline3\n", []string{oneLineTransformer})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `line1\nline2\nline3`, output)
+}
+
+func TestPkcs8(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+	ecPem := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+
+	output, err := applyTransformers(ecPem, []string{pkcs8Transformer})
+	assert.Nil(t, err)
+
+	block, _ := pem.Decode([]byte(output))
+	assert.NotNil(t, block)
+	assert.Equal(t, "PRIVATE KEY", block.Type)
+
+	parsedKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	assert.Nil(t, err)
+	assert.True(t, key.Equal(parsedKey))
+}
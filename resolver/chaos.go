@@ -0,0 +1,133 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+//
+// Failures injected by a service created with NewChaosService
+type ChaosOptions struct {
+	// Nothing is injected unless Enabled is set, so the decorator can stay wired in and be toggled by configuration
+	Enabled bool
+
+	// Probability (0 to 1) of a call failing as a whole, like a throttled or unreachable Parameter Store
+	ErrorRate float64
+
+	// Probability (0 to 1) of a GetParameters call failing for one reference of the batch only
+	PartialBatchFailureRate float64
+
+	// Every call is delayed by a latency drawn uniformly from [MinLatency, MaxLatency]
+	MinLatency time.Duration
+	MaxLatency time.Duration
+
+	// Seed of the random failures and latencies, making a chaos run reproducible
+	Seed int64
+}
+
+//
+// Error returned by the failures injected by a service created with NewChaosService
+var ErrChaosInjected = errors.New("chaos: injected failure")
+
+type chaosService struct {
+	service ISsmParameterService
+	options ChaosOptions
+
+	mutex  sync.Mutex
+	random *rand.Rand
+
+	// replaced in tests
+	sleep func(time.Duration)
+}
+
+//
+// Wraps service into a service injecting errors, partial batch failures and latency according to ChaosOptions,
+// to test the resilience of agents to Parameter Store degradation.
+func NewChaosService(service ISsmParameterService, options ChaosOptions) ISsmParameterService {
+	return &chaosService{
+		service: service,
+		options: options,
+		random:  rand.New(rand.NewSource(options.Seed)),
+		sleep:   time.Sleep,
+	}
+}
+
+func (c *chaosService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	if c.options.Enabled {
+		c.delay()
+
+		if c.chance(c.options.ErrorRate) {
+			return nil, ErrChaosInjected
+		}
+
+		if len(parameterReferences) > 0 && c.chance(c.options.PartialBatchFailureRate) {
+			return nil, fmt.Errorf("%w, the following parameter(s) cannot be resolved: %s",
+				ErrChaosInjected, parameterReferences[c.intn(len(parameterReferences))])
+		}
+	}
+
+	return c.service.callGetParameters(ctx, parameterReferences)
+}
+
+func (c *chaosService) callGetParametersByPath(ctx context.Context, path string, recursive bool) ([]SsmParameterInfo, error) {
+	if c.options.Enabled {
+		c.delay()
+
+		if c.chance(c.options.ErrorRate) {
+			return nil, ErrChaosInjected
+		}
+	}
+
+	return c.service.callGetParametersByPath(ctx, path, recursive)
+}
+
+func (c *chaosService) callGetSecretValue(ctx context.Context, secretId string) (SsmParameterInfo, error) {
+	if c.options.Enabled {
+		c.delay()
+
+		if c.chance(c.options.ErrorRate) {
+			return SsmParameterInfo{}, ErrChaosInjected
+		}
+	}
+
+	return getSecretValue(ctx, c.service, secretId)
+}
+
+// sleeps for a latency drawn from [MinLatency, MaxLatency]
+func (c *chaosService) delay() {
+	latency := c.options.MinLatency
+	if spread := c.options.MaxLatency - c.options.MinLatency; spread > 0 {
+		latency += time.Duration(c.int63n(int64(spread) + 1))
+	}
+
+	if latency > 0 {
+		c.sleep(latency)
+	}
+}
+
+// returns true with probability
+func (c *chaosService) chance(probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.random.Float64() < probability
+}
+
+func (c *chaosService) intn(n int) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.random.Intn(n)
+}
+
+func (c *chaosService) int63n(n int64) int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.random.Int63n(n)
+}
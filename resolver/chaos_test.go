@@ -0,0 +1,54 @@
+package resolver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaosService(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+		"ssm:/app/port": {Name: "/app/port", Type: stringType, Value: "443"},
+	})
+
+	disabled := NewChaosService(&serviceObject, ChaosOptions{ErrorRate: 1})
+	output, err := ResolveParametersInText(disabled, "{{ssm:/app/host}}", ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com", output)
+
+	failing := NewChaosService(&serviceObject, ChaosOptions{Enabled: true, ErrorRate: 1})
+	_, err = ResolveParametersInText(failing, "{{ssm:/app/host}}", ResolveOptions{})
+	assert.True(t, errors.Is(err, ErrChaosInjected))
+
+	err = ExportPath(failing, "/app", nil, ExportOptions{})
+	assert.True(t, errors.Is(err, ErrChaosInjected))
+
+	partiallyFailing := NewChaosService(&serviceObject, ChaosOptions{Enabled: true, PartialBatchFailureRate: 1})
+	_, err = ResolveParametersInText(partiallyFailing, "{{ssm:/app/host}}:{{ssm:/app/port}}", ResolveOptions{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cannot be resolved: ssm:/app/")
+	assert.True(t, errors.Is(err, ErrChaosInjected))
+}
+
+func TestChaosServiceLatency(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+	})
+
+	delays := []time.Duration{}
+	service := NewChaosService(&serviceObject, ChaosOptions{Enabled: true, MinLatency: time.Second, MaxLatency: 2 * time.Second, Seed: 1})
+	service.(*chaosService).sleep = func(latency time.Duration) { delays = append(delays, latency) }
+
+	for i := 0; i < 20; i++ {
+		_, err := ResolveParametersInText(service, "{{ssm:/app/host}}", ResolveOptions{})
+		assert.Nil(t, err)
+	}
+
+	assert.Len(t, delays, 20)
+	for _, latency := range delays {
+		assert.True(t, latency >= time.Second && latency <= 2*time.Second)
+	}
+}
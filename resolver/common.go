@@ -1,24 +1,202 @@
 package resolver
 
-import "regexp"
+import (
+	"regexp"
+	"sync/atomic"
+	"time"
+)
 
 const ssmNonSecurePrefix = "ssm:"
 const ssmSecurePrefix = "ssm-secure:"
+const secretsManagerPrefix = "secretsmanager:"
+const envPrefix = "env:"
 
 const secureStringType = "SecureString"
 const stringType = "String"
 
+//
+// Inline comment in a placeholder, e.g. {{ssm:name <!-- primary endpoint -->}}, ignored by the parser
+const inlineComment = "<!--.*?-->"
+
+const placeholderComment = "(?:" + inlineComment + "\\s*)?"
+
+//
+// Optional modifiers following the parameter reference in a placeholder, e.g. {{ssm:name | shellquote}}. The first
+// one can be an element modifier like [1] without the | separator. An inline comment can precede, follow
+// and separate the modifiers. Parts of a modifier between backquotes can hold any character but a backquote.
+const placeholderModifiers = placeholderComment + "((?:\\[[0-9]+\\]\\s*)?(?:\\|(?:[^|{}<`]|" + quotedModifierPart + "|" + inlineComment + ")*)*)" +
+	placeholderComment
+
+const quotedModifierPart = "`[^`]*`"
+
+//
+// Parameter name in a placeholder, optionally followed by the version or the label to resolve,
+// e.g. /app/key:3 or /app/key:prod. Names of public parameters can hold dots, e.g. /aws/service/bottlerocket/aws-k8s-1.29/...
+const parameterNameWithSelector = "(?:" + publicParameterPath + "[\\w./-]+|[\\w-/]+)(?::(?:[0-9]+|[a-zA-Z][\\w.-]*))?"
+
 //
 // SSM Parameter placeholder - relaxed regular expression
-var parameterPlaceholder = regexp.MustCompile("{{\\s*(" + ssmNonSecurePrefix + "[\\w-/]+)\\s*}}")
-var secureParameterPlaceholder = regexp.MustCompile("{{\\s*(" + ssmSecurePrefix + "[\\w-/]+)\\s*}}")
+var parameterPlaceholder = regexp.MustCompile("{{\\s*(" + ssmNonSecurePrefix + parameterNameWithSelector + ")\\s*" + placeholderModifiers + "}}")
+var secureParameterPlaceholder = regexp.MustCompile("{{\\s*(" + ssmSecurePrefix + parameterNameWithSelector + ")\\s*" + placeholderModifiers + "}}")
+
+//
+// Name or ARN of a Secrets Manager secret in a placeholder, e.g. prod/app/db or arn:aws:secretsmanager:...:secret:prod/app/db-AbCdEf
+const secretNameOrArn = "[\\w/+=.@:-]+"
+
+//
+// Secrets Manager secret placeholder, e.g. {{secretsmanager:prod/app/db}}
+var secretPlaceholder = regexp.MustCompile("{{\\s*(" + secretsManagerPrefix + secretNameOrArn + ")\\s*" + placeholderModifiers + "}}")
+
+//
+// Name of an environment variable in a placeholder, e.g. HOME
+const environmentVariableName = "[A-Za-z_][A-Za-z0-9_]*"
+
+//
+// Environment variable placeholder, e.g. {{env:HOME}}
+var environmentPlaceholder = regexp.MustCompile("{{\\s*(" + envPrefix + environmentVariableName + ")\\s*" + placeholderModifiers + "}}")
+
+//
+// Placeholders of every kind, the built-in ones and these of the registered sources, read with parameterPlaceholders.
+// The slice is replaced, never modified, when a source is registered, so that resolving can go on meanwhile.
+var allParameterPlaceholders = newPlaceholderList(parameterPlaceholder, secureParameterPlaceholder, secretPlaceholder, environmentPlaceholder)
+
+// returns an atomic.Value holding placeholders
+func newPlaceholderList(placeholders ...*regexp.Regexp) *atomic.Value {
+	list := &atomic.Value{}
+	list.Store(placeholders)
+	return list
+}
+
+// returns the placeholders of every kind, which must not be modified
+func parameterPlaceholders() []*regexp.Regexp {
+	return allParameterPlaceholders.Load().([]*regexp.Regexp)
+}
 
 type ResolveOptions struct {
 	IgnoreSecureParameters bool
+
+	// Fail when a placeholder without the shellquote transformer appears in a shell command context
+	StrictShellContexts bool
+
+	// Identifies the document in the documentID pprof label of the resolving goroutines
+	DocumentID string
+
+	// Resolve placeholders found in parameter values too, e.g. a value "jdbc:{{ssm:/db/host}}".
+	// Cyclic references, including a value referencing its own parameter, fail with an error.
+	Recursive bool
+
+	// Name of the Format escaping resolved values for the syntax of the document, e.g. properties or ini.
+	// The file APIs select the format by the extension of the output file when it is not set, except for json
+	// and yaml, which are only applied when set here (json also by ResolveParametersInJSON).
+	Format string
+
+	// Names of the formats, e.g. properties, whose resolved documents get a comment before every line with
+	// a substituted value naming the parameters and versions the values came from
+	WatermarkFormats []string
+
+	// Filters applied to resolved documents before they are written to files
+	PostRenderFilters []PostRenderFilter
+
+	// Number of times a file write failing with a transient error (EINTR, EAGAIN, ESTALE, EIO) is retried
+	WriteRetries int
+
+	// Delay before the first retry of a failed write, doubled for every following retry (100ms when zero)
+	WriteRetryBackoff time.Duration
+
+	// Maximum number of files written at the same time by ResolveParametersInFiles, 1 when not positive
+	MaxParallelWriters int
+
+	// When written files are flushed to disk with fsync, SyncNone (left to the OS) by default
+	SyncPolicy SyncPolicy
+
+	// Suffix of the copy of its previous content kept next to a file resolved in place, e.g. .bak, none when empty
+	BackupSuffix string
+
+	// Checks the files written by ResolveParametersInFiles. When it fails, or a file cannot be written,
+	// every output file is restored to its content before the call, files created by the call are removed.
+	Verify VerifyFunc
+
+	// Names of the environment variables {{env:NAME}} placeholders can reference, none when empty. Their values are
+	// secure: they are left unresolved with IgnoreSecureParameters, and the standard and strict security profiles
+	// do not allow them at all.
+	AllowedEnvironmentVariables []string
+
+	// Maximum number of GetParameters requests made at the same time, 1 when not positive
+	MaxConcurrency int
+
+	// Cache serving parameters fetched by previous resolve calls, parameters are always fetched when nil.
+	// A max-age constraint of a placeholder limits how old the cached value of its parameter can be.
+	Cache Cache
+
+	// Delimiters of the placeholders, {{ and }} when not set, for templates of engines using {{ }} themselves
+	PlaceholderSyntax PlaceholderSyntax
+
+	// Guardrails bounding the work of resolving templates that are not trusted, nothing is limited by default
+	Limits ResolveLimits
+
+	// Whether resolved values with byte order marks, control characters or line breaks where the document
+	// expects a single line are substituted as they are (by default), sanitized or rejected
+	SanitizeValues ValueSanitization
+
+	// Called for every placeholder whose value starts or ends with whitespace or holds characters that need quoting
+	// in the syntax of the document (YAML, JSON, env or shell files), nothing is checked when nil.
+	// Render sets call it from the goroutines rendering their documents.
+	Warn func(warning ValueWarning)
+
+	// Guardrails against hostile templates and values enforced on top of the other options, none by default
+	SecurityProfile SecurityProfile
+
+	// Sinks receiving secure values instead of the documents, by name. A placeholder selects one with the sink=name
+	// modifier, e.g. {{ssm-secure:/app/token | sink=keyring}}, otherwise the first route of SecretSinkRoutes
+	// matching the name of the parameter does.
+	SecretSinks map[string]SecretSink
+
+	// Routes of the secure parameters to SecretSinks by parameter name pattern
+	SecretSinkRoutes []SecretSinkRoute
+
+	// Leave the placeholders of missing parameters as they are instead of failing the resolution, placeholders with
+	// a default get their default. ResolveParametersInTextPartially reports the references skipped.
+	IgnoreMissingParameters bool
+
+	// Replace the placeholders of the parameters skipped by IgnoreMissingParameters with empty strings
+	EmptyMissingParameters bool
+
+	// Fail when placeholders are left in the resolved document, well-formed or not, e.g. {{ssm:/app/db host}}, instead of
+	// letting typos in references through, with the placeholders of registered sources too. Secure placeholders
+	// left by IgnoreSecureParameters are expected, as are these outside the resolved parts of Dockerfiles and the like.
+	// Streamed documents fail at the first segment with leftovers, the previous ones are written already.
+	FailOnUnresolvedPlaceholders bool
+
+	// Fail when a parameter changes version while a document is being resolved, e.g. because it is rotated
+	// between two GetParameters batches, instead of re-fetching all the references to it together
+	FailOnVersionChange bool
+
+	// Resolve references to the public parameters AWS publishes under /aws/service/, e.g.
+	// {{ssm:/aws/service/ami-amazon-linux-latest/al2023-ami-kernel-default-x86_64}}, with a GetParameter request each.
+	// Their values are controlled by AWS and change, e.g. with every new AMI, so they fail the resolution otherwise.
+	AllowPublicParameters bool
+
+	// How parameters with an empty value are resolved, they are substituted like the others by default
+	OnEmptyValue EmptyValuePolicy
+
+	// set by the APIs writing resolved documents to files, the only place binary values can go to
+	allowBinaryValues bool
+
+	// called with every reference skipped by IgnoreMissingParameters, set by ResolveParametersInTextPartially
+	skipMissingParameter func(parameterReference string)
 }
 
 type SsmParameterInfo struct {
 	Name  string
 	Type  string
 	Value string
+
+	// text or aws:ec2:image, empty when unknown
+	DataType string `json:",omitempty"`
+
+	// Version of the parameter the value belongs to, 0 when unknown
+	Version int64 `json:",omitempty"`
+
+	// Label the version was selected by, e.g. prod for {{ssm:/app/key:prod}}, empty for other references
+	Label string `json:",omitempty"`
 }
@@ -0,0 +1,112 @@
+package resolver
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+const gzipCompression = "gzip"
+
+//
+// Compression of parameter snapshots, selected by name with ExportOptions.Compression
+type Compression struct {
+	// Bytes every compressed stream starts with, detecting the compression of the snapshots being read
+	Magic []byte
+
+	NewWriter func(writer io.Writer) (io.WriteCloser, error)
+	NewReader func(reader io.Reader) (io.ReadCloser, error)
+}
+
+var compressionsMutex sync.RWMutex
+
+var compressions = map[string]Compression{
+	gzipCompression: {
+		Magic:     []byte{0x1f, 0x8b},
+		NewWriter: func(writer io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(writer), nil },
+		NewReader: func(reader io.Reader) (io.ReadCloser, error) { return gzip.NewReader(reader) },
+	},
+}
+
+//
+// Registers compression under name, replacing a compression registered before, e.g. zstd:
+// RegisterCompression("zstd", Compression{Magic: []byte{0x28, 0xb5, 0x2f, 0xfd}, ...}).
+// Snapshots compressed with it are then read transparently.
+func RegisterCompression(name string, compression Compression) {
+	compressionsMutex.Lock()
+	defer compressionsMutex.Unlock()
+
+	compressions[name] = compression
+}
+
+// compresses data with the compression named name, data is returned as is when name is empty
+func compress(name string, data []byte) ([]byte, error) {
+	if len(name) == 0 {
+		return data, nil
+	}
+
+	compressionsMutex.RLock()
+	compression, known := compressions[name]
+	compressionsMutex.RUnlock()
+	if !known {
+		return nil, errors.New("unknown compression " + name)
+	}
+
+	var buffer bytes.Buffer
+	writer, err := compression.NewWriter(&buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// returns a reader of the decompressed content of reader when it starts with the magic bytes of a registered
+// compression, and of the content of reader otherwise
+func decompressingReader(reader io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(reader)
+
+	compressionsMutex.RLock()
+	defer compressionsMutex.RUnlock()
+
+	for name, compression := range compressions {
+		magic, err := buffered.Peek(len(compression.Magic))
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if len(compression.Magic) > 0 && bytes.Equal(magic, compression.Magic) {
+			decompressed, err := compression.NewReader(buffered)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s compressed snapshot: %w", name, err)
+			}
+			return decompressed, nil
+		}
+	}
+
+	return buffered, nil
+}
+
+// decompresses data when it starts with the magic bytes of a registered compression
+func decompress(data []byte) ([]byte, error) {
+	reader, err := decompressingReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(reader)
+}
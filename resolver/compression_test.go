@@ -0,0 +1,84 @@
+package resolver
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportPathCompressedRoundTrip(t *testing.T) {
+	serviceObject := newExportTestService()
+
+	for _, kmsKeyId := range []string{"", "alias/snapshots"} {
+		var buffer bytes.Buffer
+		err := ExportPath(&serviceObject, "/app/prod", &buffer, ExportOptions{
+			Recursive:   true,
+			KmsKeyId:    kmsKeyId,
+			Compression: gzipCompression,
+		})
+		assert.Nil(t, err)
+
+		var snapshotService *SnapshotService
+		if len(kmsKeyId) == 0 {
+			snapshotService, err = NewSnapshotService(&buffer)
+		} else {
+			snapshotService, err = NewEncryptedSnapshotService(&buffer, &serviceObject)
+		}
+		assert.Nil(t, err)
+
+		output, err := ResolveParametersInText(snapshotService, "{{ssm:/app/prod/host}} {{ssm-secure:/app/prod/password}}", ResolveOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, "example.com s3cr3t", output)
+	}
+}
+
+func TestExportPathCompressedIsSmaller(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/blob": {Name: "/app/blob", Type: stringType, Value: string(bytes.Repeat([]byte("{\"key\": \"value\"},"), 200))},
+	})
+
+	var plain, compressed bytes.Buffer
+	assert.Nil(t, ExportPath(&serviceObject, "/app", &plain, ExportOptions{}))
+	assert.Nil(t, ExportPath(&serviceObject, "/app", &compressed, ExportOptions{Compression: gzipCompression}))
+
+	assert.True(t, compressed.Len() < plain.Len()/10)
+	assert.Equal(t, []byte{0x1f, 0x8b}, compressed.Bytes()[:2])
+}
+
+func TestRegisterCompression(t *testing.T) {
+	RegisterCompression("deflate-test", Compression{
+		Magic: []byte("DEFL"),
+		NewWriter: func(writer io.Writer) (io.WriteCloser, error) {
+			if _, err := writer.Write([]byte("DEFL")); err != nil {
+				return nil, err
+			}
+			return flate.NewWriter(writer, flate.BestCompression)
+		},
+		NewReader: func(reader io.Reader) (io.ReadCloser, error) {
+			if _, err := io.ReadFull(reader, make([]byte, 4)); err != nil {
+				return nil, err
+			}
+			return flate.NewReader(reader), nil
+		},
+	})
+
+	compressed, err := compress("deflate-test", []byte("{\"Parameters\": []}"))
+	assert.Nil(t, err)
+
+	decompressed, err := decompress(compressed)
+	assert.Nil(t, err)
+	assert.Equal(t, "{\"Parameters\": []}", string(decompressed))
+
+	_, err = compress("no-such-compression", []byte{})
+	assert.NotNil(t, err)
+
+	reader, err := decompressingReader(bytes.NewReader([]byte("{}")))
+	assert.Nil(t, err)
+	plain, err := ioutil.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.Equal(t, "{}", string(plain))
+}
@@ -0,0 +1,135 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const typeConstraint = "type"
+const matchConstraint = "match"
+
+//
+// Maximum number of compiled match constraint patterns kept in memory
+const maxCachedConstraintPatterns = 256
+
+//
+// Compiled patterns of match constraints shared by all resolve calls
+var constraintPatterns = newRegexpCache(maxCachedConstraintPatterns)
+
+//
+// Checks of a parameter value that can be listed among the modifiers of a placeholder as name=argument,
+// e.g. {{ssm:/app/port | type=int}} or {{ssm:/app/cidr | match=^10\.}}. A failed check fails the resolution.
+// Arguments holding the |, {, } or < characters of the placeholder syntax are written between backquotes,
+// e.g. {{ssm:/app/zip | match=`^\d{5}(-\d{4})?$`}}.
+// Constraints check the value as transformed by the modifiers preceding them.
+var constraints = map[string]func(value string, argument string) error{
+	typeConstraint:   checkValueType,
+	matchConstraint:  checkValueMatches,
+	maxAgeConstraint: checkMaxAge,
+	sinkConstraint:   checkSink,
+}
+
+//
+// Types that can be declared with the type constraint, e.g. {{ssm:/app/port | type=int}}
+var valueTypeParsers = map[string]func(value string) error{
+	"int": func(value string) error {
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err
+	},
+	"float": func(value string) error {
+		_, err := strconv.ParseFloat(value, 64)
+		return err
+	},
+	"bool": func(value string) error {
+		_, err := strconv.ParseBool(value)
+		return err
+	},
+	"json": func(value string) error {
+		if !json.Valid([]byte(value)) {
+			return errors.New("invalid JSON")
+		}
+		return nil
+	},
+}
+
+// splits a modifier like "type=int" into the constraint name and its argument, unquoting an argument
+// between backquotes
+func parseConstraintModifier(modifier string) (name string, argument string, isConstraint bool) {
+	separator := strings.Index(modifier, "=")
+	if separator < 0 {
+		return "", "", false
+	}
+
+	argument = strings.TrimSpace(modifier[separator+1:])
+	if len(argument) >= 2 && strings.HasPrefix(argument, "`") && strings.HasSuffix(argument, "`") {
+		argument = argument[1 : len(argument)-1]
+	}
+
+	return strings.TrimSpace(modifier[:separator]), argument, true
+}
+
+// checks that a constraint modifier names a known constraint with a valid argument
+func validateConstraintModifier(modifier string) error {
+	name, argument, _ := parseConstraintModifier(modifier)
+	if _, contains := argumentTransformers[name]; contains {
+		if name == eachTransformer && !strings.Contains(argument, "%s") {
+			return errors.New("template " + argument + " of " + eachTransformer + " has no %s for the item")
+		}
+		return nil
+	}
+
+	if _, contains := constraints[name]; !contains {
+		return errors.New("unknown constraint " + name)
+	}
+
+	if name == typeConstraint {
+		if _, contains := valueTypeParsers[argument]; !contains {
+			return errors.New("unknown type " + argument)
+		}
+	}
+
+	if name == maxAgeConstraint {
+		if err := checkMaxAge("", argument); err != nil {
+			return err
+		}
+	}
+
+	if name == matchConstraint {
+		if _, err := constraintPatterns.compile(argument); err != nil {
+			return fmt.Errorf("invalid pattern %s: %w", argument, err)
+		}
+	}
+
+	return nil
+}
+
+// the value is not part of the error, it may be a secret
+func checkValueType(value string, valueType string) error {
+	parse, contains := valueTypeParsers[valueType]
+	if !contains {
+		return errors.New("unknown type " + valueType)
+	}
+
+	if parse(value) != nil {
+		return errors.New("value is not a valid " + valueType)
+	}
+
+	return nil
+}
+
+// the value is not part of the error, it may be a secret
+func checkValueMatches(value string, pattern string) error {
+	compiled, err := constraintPatterns.compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+
+	if !compiled.MatchString(value) {
+		return errors.New("value does not match " + pattern)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeConstraint(t *testing.T) {
+	testCases := []struct {
+		value     string
+		valueType string
+		valid     bool
+	}{
+		{"8080", "int", true},
+		{"80a", "int", false},
+		{"0.75", "float", true},
+		{"true", "bool", true},
+		{"yes", "bool", false},
+		{`{"a": [1, 2]}`, "json", true},
+		{`{"a": `, "json", false},
+	}
+
+	for _, testCase := range testCases {
+		_, err := applyTransformers(testCase.value, []string{typeConstraint + "=" + testCase.valueType})
+		assert.Equal(t, testCase.valid, err == nil, testCase.value)
+	}
+}
+
+func TestResolveParametersInTextWithTypeConstraint(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/port": {Name: "/app/port", Type: stringType, Value: "8080"},
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject, "port={{ssm:/app/port|type=int}}", ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "port=8080", output)
+
+	_, err = ResolveParametersInText(&serviceObject, "port={{ssm:/app/host | type=int}}", ResolveOptions{})
+	assert.EqualError(t, err, "cannot transform value of parameter reference {{ssm:/app/host}}: type=int: value is not a valid int")
+
+	_, err = ResolveParametersInText(&serviceObject, "port={{ssm:/app/port | type=port}}", ResolveOptions{})
+	assert.EqualError(t, err, "unknown type port in placeholder {{ssm:/app/port | type=port}}")
+
+	_, err = ResolveParametersInText(&serviceObject, "port={{ssm:/app/port | size=2}}", ResolveOptions{})
+	assert.NotNil(t, err)
+}
+
+func TestResolveParametersInTextWithMatchConstraint(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/cidr": {Name: "/app/cidr", Type: stringType, Value: "10.0.0.0/16"},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject, "cidr={{ssm:/app/cidr|match=^10\\.}}", ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "cidr=10.0.0.0/16", output)
+
+	_, err = ResolveParametersInText(&serviceObject, "cidr={{ssm:/app/cidr | match=^192\\.168\\.}}", ResolveOptions{})
+	assert.EqualError(t, err, "cannot transform value of parameter reference {{ssm:/app/cidr}}: match=^192\\.168\\.: value does not match ^192\\.168\\.")
+
+	_, err = ResolveParametersInText(&serviceObject, "cidr={{ssm:/app/cidr | match=^(10}}", ResolveOptions{})
+	assert.NotNil(t, err)
+}
+
+func TestResolveParametersInTextWithQuotedMatchPattern(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/zip":   {Name: "/app/zip", Type: stringType, Value: "12345-6789"},
+		"ssm:/app/color": {Name: "/app/color", Type: stringType, Value: "blue"},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject,
+		"zip={{ssm:/app/zip | match=`^\\d{5}(-\\d{4})?$` <!-- US | ZIP+4 --> | urlencode}} color={{ssm:/app/color | match=`^(red|blue)$`}}", ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "zip=12345-6789 color=blue", output)
+
+	_, err = ResolveParametersInText(&serviceObject, "{{ssm:/app/color | match=`^<(red|green)>$`}}", ResolveOptions{})
+	assert.EqualError(t, err, "cannot transform value of parameter reference {{ssm:/app/color}}: match=`^<(red|green)>$`: value does not match ^<(red|green)>$")
+}
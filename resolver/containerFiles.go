@@ -0,0 +1,160 @@
+package resolver
+
+import (
+	"context"
+	"strings"
+)
+
+//
+// Dockerfile instructions whose values are resolved by ResolveParametersInDockerfile
+var dockerfileResolvableInstructions = map[string]bool{
+	"ENV":   true,
+	"LABEL": true,
+}
+
+//
+// docker-compose keys whose nested values are resolved by ResolveParametersInComposeFile
+var composeResolvableKeys = map[string]bool{
+	"environment": true,
+	"labels":      true,
+}
+
+//
+// Takes the content of a Dockerfile and resolves SSM parameters according to ResolveOptions
+// only inside ENV and LABEL instructions (including their continuation lines).
+// Every other instruction, comment and $ variable is returned untouched.
+func ResolveParametersInDockerfile(
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, error) {
+
+	return ResolveParametersInDockerfileWithContext(context.Background(), service, input, options)
+}
+
+//
+// Same as ResolveParametersInDockerfile, but the SSM requests are canceled when ctx is done.
+func ResolveParametersInDockerfileWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, error) {
+
+	if err := checkDefaultPlaceholderSyntax(options, "ResolveParametersInDockerfile"); err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(input, "\n")
+	resolvable := make([]bool, len(lines))
+
+	inResolvableInstruction := false
+	continued := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if !continued {
+			if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			instruction := strings.ToUpper(strings.Fields(trimmed)[0])
+			inResolvableInstruction = dockerfileResolvableInstructions[instruction]
+		}
+
+		resolvable[i] = inResolvableInstruction
+		continued = strings.HasSuffix(trimmed, "\\")
+	}
+
+	return resolveParametersInSelectedLines(ctx, service, lines, resolvable, options)
+}
+
+//
+// Takes the content of a docker-compose.yaml file and resolves SSM parameters according to ResolveOptions
+// only inside the values nested under environment and labels keys.
+// Every other key, comment and $ variable is returned untouched.
+func ResolveParametersInComposeFile(
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, error) {
+
+	return ResolveParametersInComposeFileWithContext(context.Background(), service, input, options)
+}
+
+//
+// Same as ResolveParametersInComposeFile, but the SSM requests are canceled when ctx is done.
+func ResolveParametersInComposeFileWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, error) {
+
+	if err := checkDefaultPlaceholderSyntax(options, "ResolveParametersInComposeFile"); err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(input, "\n")
+	resolvable := make([]bool, len(lines))
+
+	blockIndent := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if blockIndent >= 0 && indent > blockIndent {
+			resolvable[i] = true
+			continue
+		}
+		blockIndent = -1
+
+		key := strings.TrimSpace(strings.TrimPrefix(trimmed, "- "))
+		if colon := strings.Index(key, ":"); colon > 0 && composeResolvableKeys[key[:colon]] {
+			blockIndent = indent
+			// inline values like 'environment: { KEY: "{{ssm:name}}" }'
+			resolvable[i] = len(strings.TrimSpace(key[colon+1:])) > 0
+		}
+	}
+
+	return resolveParametersInSelectedLines(ctx, service, lines, resolvable, options)
+}
+
+// resolves SSM parameters only in the lines marked as resolvable and joins all lines back together
+func resolveParametersInSelectedLines(
+	ctx context.Context,
+	service ISsmParameterService,
+	lines []string,
+	resolvable []bool,
+	options ResolveOptions) (string, error) {
+
+	selectedLines := []string{}
+	for i, line := range lines {
+		if resolvable[i] {
+			selectedLines = append(selectedLines, line)
+		}
+	}
+
+	resolvedParametersMap, err := ExtractParametersFromTextWithContext(ctx, service, strings.Join(selectedLines, "\n"), options)
+	if err != nil {
+		return "", err
+	}
+
+	resolvedLines := make([]string, len(lines))
+	resolvedSelectedLines := []string{}
+	for i, line := range lines {
+		if resolvable[i] {
+			line, err = replaceParameterPlaceholders(line, resolvedParametersMap)
+			if err != nil {
+				return "", err
+			}
+			resolvedSelectedLines = append(resolvedSelectedLines, line)
+		}
+		resolvedLines[i] = line
+	}
+
+	// placeholders left on the lines which are not resolved, e.g. comments, are expected
+	if err := checkUnresolvedPlaceholders(strings.Join(resolvedSelectedLines, "\n"), options); err != nil {
+		return "", err
+	}
+
+	return strings.Join(resolvedLines, "\n"), nil
+}
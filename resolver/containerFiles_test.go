@@ -0,0 +1,67 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersInDockerfile(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/version": {Name: "/app/version", Type: stringType, Value: "1.2.3"},
+		"ssm:/app/owner":   {Name: "/app/owner", Type: stringType, Value: "team-a"},
+	})
+
+	dockerfile := `FROM alpine:3.18
+# {{ssm:/app/version}} in a comment stays
+RUN echo "{{ssm:/app/version}}" $HOME
+ENV APP_VERSION={{ssm:/app/version}} \
+    APP_HOME=$HOME
+LABEL owner="{{ ssm:/app/owner }}"`
+
+	output, err := ResolveParametersInDockerfile(&serviceObject, dockerfile, ResolveOptions{})
+
+	expectedOutput := `FROM alpine:3.18
+# {{ssm:/app/version}} in a comment stays
+RUN echo "{{ssm:/app/version}}" $HOME
+ENV APP_VERSION=1.2.3 \
+    APP_HOME=$HOME
+LABEL owner="team-a"`
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedOutput, output)
+}
+
+func TestResolveParametersInComposeFile(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/db/host": {Name: "/app/db/host", Type: stringType, Value: "db.internal"},
+		"ssm:/app/owner":   {Name: "/app/owner", Type: stringType, Value: "team-a"},
+	})
+
+	compose := `services:
+  web:
+    image: "web:{{ssm:/app/version}}"
+    command: echo $${PATH}
+    environment:
+      - DB_HOST={{ssm:/app/db/host}}
+      - HOME=$HOME
+    labels:
+      owner: "{{ssm:/app/owner}}"
+    entrypoint: "{{ssm:/app/db/host}}"`
+
+	output, err := ResolveParametersInComposeFile(&serviceObject, compose, ResolveOptions{})
+
+	expectedOutput := `services:
+  web:
+    image: "web:{{ssm:/app/version}}"
+    command: echo $${PATH}
+    environment:
+      - DB_HOST=db.internal
+      - HOME=$HOME
+    labels:
+      owner: "team-a"
+    entrypoint: "{{ssm:/app/db/host}}"`
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedOutput, output)
+}
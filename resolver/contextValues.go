@@ -0,0 +1,92 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// key of the resolved values attached to a context by WithResolvedValues
+type resolvedValuesContextKey struct{}
+
+//
+// Resolves parameterReferences according to ResolveOptions and returns a copy of ctx carrying their values,
+// e.g. to look up per-request tenant configuration in HTTP middleware. Values attached to ctx by previous calls
+// remain visible unless overridden. Read the values with ResolvedString, ResolvedInt and ResolvedBool.
+func WithResolvedValues(
+	ctx context.Context,
+	service ISsmParameterService,
+	parameterReferences []string,
+	options ResolveOptions) (context.Context, error) {
+
+	if err := ctx.Err(); err != nil {
+		return ctx, err
+	}
+
+	resolvedParametersMap, err := ResolveParameterReferenceListWithContext(ctx, service, parameterReferences, options)
+	if err != nil {
+		return ctx, err
+	}
+
+	values := map[string]string{}
+	for ref, param := range resolvedParametersMap {
+		values[ref] = param.Value
+	}
+
+	return withValues(ctx, values), nil
+}
+
+// returns a copy of ctx carrying values on top of the values attached to ctx before
+func withValues(ctx context.Context, values map[string]string) context.Context {
+	mergedValues := map[string]string{}
+	if parentValues, ok := ctx.Value(resolvedValuesContextKey{}).(map[string]string); ok {
+		for ref, value := range parentValues {
+			mergedValues[ref] = value
+		}
+	}
+	for ref, value := range values {
+		mergedValues[ref] = value
+	}
+
+	return context.WithValue(ctx, resolvedValuesContextKey{}, mergedValues)
+}
+
+//
+// Returns the value of parameterReference attached to ctx by WithResolvedValues and whether it is present.
+func ResolvedString(ctx context.Context, parameterReference string) (string, bool) {
+	values, _ := ctx.Value(resolvedValuesContextKey{}).(map[string]string)
+	value, found := values[parameterReference]
+	return value, found
+}
+
+//
+// Returns the value of parameterReference attached to ctx by WithResolvedValues parsed as an int.
+func ResolvedInt(ctx context.Context, parameterReference string) (int, error) {
+	value, found := ResolvedString(ctx, parameterReference)
+	if !found {
+		return 0, errors.New("parameter reference {{" + parameterReference + "}} is not resolved in the context")
+	}
+
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New("value of parameter reference {{" + parameterReference + "}} is not a valid int")
+	}
+
+	return result, nil
+}
+
+//
+// Returns the value of parameterReference attached to ctx by WithResolvedValues parsed as a bool.
+func ResolvedBool(ctx context.Context, parameterReference string) (bool, error) {
+	value, found := ResolvedString(ctx, parameterReference)
+	if !found {
+		return false, errors.New("parameter reference {{" + parameterReference + "}} is not resolved in the context")
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("value of parameter reference {{" + parameterReference + "}} is not a valid bool")
+	}
+
+	return result, nil
+}
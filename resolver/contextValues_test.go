@@ -0,0 +1,45 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithResolvedValues(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/tenants/acme/quota":   {Name: "/tenants/acme/quota", Type: stringType, Value: "100"},
+		"ssm:/tenants/acme/beta":    {Name: "/tenants/acme/beta", Type: stringType, Value: "true"},
+		"ssm:/tenants/acme/name":    {Name: "/tenants/acme/name", Type: stringType, Value: "Acme"},
+		"ssm:/tenants/acme/country": {Name: "/tenants/acme/country", Type: stringType, Value: "NZ"},
+	})
+
+	ctx, err := WithResolvedValues(context.Background(), &serviceObject,
+		[]string{"ssm:/tenants/acme/quota", "ssm:/tenants/acme/beta", "ssm:/tenants/acme/name"}, ResolveOptions{})
+	assert.Nil(t, err)
+
+	ctx, err = WithResolvedValues(ctx, &serviceObject, []string{"ssm:/tenants/acme/country"}, ResolveOptions{})
+	assert.Nil(t, err)
+
+	quota, err := ResolvedInt(ctx, "ssm:/tenants/acme/quota")
+	assert.Nil(t, err)
+	assert.Equal(t, 100, quota)
+
+	beta, err := ResolvedBool(ctx, "ssm:/tenants/acme/beta")
+	assert.Nil(t, err)
+	assert.True(t, beta)
+
+	name, found := ResolvedString(ctx, "ssm:/tenants/acme/name")
+	assert.True(t, found)
+	assert.Equal(t, "Acme", name)
+
+	country, _ := ResolvedString(ctx, "ssm:/tenants/acme/country")
+	assert.Equal(t, "NZ", country)
+
+	_, err = ResolvedInt(ctx, "ssm:/tenants/acme/name")
+	assert.NotNil(t, err)
+
+	_, found = ResolvedString(context.Background(), "ssm:/tenants/acme/name")
+	assert.False(t, found)
+}
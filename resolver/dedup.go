@@ -0,0 +1,75 @@
+package resolver
+
+import (
+	"regexp"
+	"sort"
+)
+
+//
+// Slices longer than this are deduplicated by sorting a copy instead of building a set. A set costs about 50 bytes
+// per distinct element on top of the slice, the sorted copy 16 bytes per element: machine-generated documents with
+// millions of references stay within a few times the size of the reference slice.
+const sortedDedupThreshold = 64 * 1024
+
+// returns the distinct elements of slice, in no particular order
+func dedupSlice(slice []string) []string {
+	if len(slice) > sortedDedupThreshold {
+		return dedupSortedCopy(slice)
+	}
+
+	ht := make(map[string]bool, len(slice))
+
+	for _, element := range slice {
+		ht[element] = true
+	}
+
+	keys := make([]string, len(ht))
+
+	i := 0
+	for k := range ht {
+		keys[i] = k
+		i++
+	}
+
+	return keys
+}
+
+// returns the distinct elements of slice in sorted order, compacting a sorted copy of slice in place
+func dedupSortedCopy(slice []string) []string {
+	sorted := append([]string{}, slice...)
+	sort.Strings(sorted)
+
+	distinct := 0
+	for i, element := range sorted {
+		if i == 0 || element != sorted[distinct-1] {
+			sorted[distinct] = element
+			distinct++
+		}
+	}
+
+	return sorted[:distinct:distinct]
+}
+
+// calls fn with the submatch indexes of every match of pattern in text, one match at a time, instead of
+// collecting the indexes of all the matches first like FindAllStringSubmatchIndex
+func forEachMatch(pattern *regexp.Regexp, text string, fn func(match []int)) {
+	for offset := 0; offset < len(text); {
+		match := pattern.FindStringSubmatchIndex(text[offset:])
+		if match == nil {
+			return
+		}
+
+		for i := range match {
+			if match[i] >= 0 {
+				match[i] += offset
+			}
+		}
+		fn(match)
+
+		if match[1] > offset {
+			offset = match[1]
+		} else {
+			offset++
+		}
+	}
+}
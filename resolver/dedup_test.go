@@ -0,0 +1,44 @@
+package resolver
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupSliceSorted(t *testing.T) {
+	references := []string{}
+	for i := 0; i < sortedDedupThreshold+10; i++ {
+		references = append(references, "ssm:/app/"+strconv.Itoa(i%1000))
+	}
+
+	deduped := dedupSlice(references)
+
+	assert.Equal(t, 1000, len(deduped))
+	assert.ElementsMatch(t, dedupSlice(references[:1000]), deduped)
+	assert.Equal(t, "ssm:/app/0", references[0])
+}
+
+func TestForEachMatch(t *testing.T) {
+	text := "{{ssm:/a}} {{ssm:/b | trim}}{{ssm:/a}}"
+	pattern := regexp.MustCompile("{{\\s*(ssm:[\\w/]+)[^}]*}}")
+
+	matches := [][]int{}
+	forEachMatch(pattern, text, func(match []int) {
+		matches = append(matches, match)
+	})
+
+	assert.Equal(t, pattern.FindAllStringSubmatchIndex(text, -1), matches)
+}
+
+func TestParseParametersFromTextWithManyPlaceholders(t *testing.T) {
+	text := strings.Repeat("{{ssm:/app/a}} {{ssm-secure:/app/b}}\n", 10000)
+
+	references, err := parseParametersFromTextIntoDedupedSlice(text, false)
+
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"ssm:/app/a", "ssm-secure:/app/b"}, references)
+}
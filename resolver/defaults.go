@@ -0,0 +1,174 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//
+// Value a placeholder falls back to when its parameter is missing, e.g. {{ssm:/app/port | default "8080"}}.
+// The default is a Go string literal, it is passed through the transformers of the placeholder like a value.
+// A reference falls back to its default only when all of its placeholders declare the same one.
+const defaultModifier = "default"
+
+// parses a default modifier like default "8080" into its value. A modifier naming default without a quoted value
+// is reported as invalid.
+func parseDefaultModifier(modifier string) (value string, isDefault bool, err error) {
+	argument := strings.TrimPrefix(modifier, defaultModifier)
+	if argument == modifier || (len(argument) > 0 && argument[0] != ' ' && argument[0] != '\t' && argument[0] != '"') {
+		return "", false, nil
+	}
+
+	value, err = strconv.Unquote(strings.TrimSpace(argument))
+	if err != nil {
+		return "", true, errors.New("default value " + strings.TrimSpace(argument) + " is not a quoted string")
+	}
+
+	return value, true, nil
+}
+
+// returns the default declared by the modifiers of a placeholder
+func placeholderDefault(modifiers string) (value string, hasDefault bool) {
+	for _, modifier := range parsePlaceholderModifiers(modifiers) {
+		if value, isDefault, err := parseDefaultModifier(modifier); isDefault && err == nil {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// returns the default of every parameter reference of texts whose placeholders all declare the same default
+func placeholderDefaults(texts ...string) map[string]string {
+	defaults := map[string]string{}
+	withoutDefault := map[string]bool{}
+	for _, text := range texts {
+		addPlaceholderDefaults(defaults, withoutDefault, text)
+	}
+
+	return defaults
+}
+
+// adds the defaults of the placeholders of text to defaults, and drops those of the references with a placeholder
+// declaring no default or another default, which are remembered in withoutDefault
+func addPlaceholderDefaults(defaults map[string]string, withoutDefault map[string]bool, text string) {
+	for _, placeholder := range parameterPlaceholders() {
+		for _, match := range placeholder.FindAllStringSubmatch(text, -1) {
+			ref := match[1]
+			if withoutDefault[ref] {
+				continue
+			}
+
+			value, hasDefault := placeholderDefault(match[2])
+			if current, contains := defaults[ref]; !hasDefault || (contains && current != value) {
+				delete(defaults, ref)
+				withoutDefault[ref] = true
+				continue
+			}
+			defaults[ref] = value
+		}
+	}
+}
+
+// checks that the placeholders of one parameter reference in text do not declare different defaults
+func validatePlaceholderDefaults(text string) error {
+	defaults := map[string]string{}
+	for _, placeholder := range parameterPlaceholders() {
+		for _, match := range placeholder.FindAllStringSubmatch(text, -1) {
+			value, hasDefault := placeholderDefault(match[2])
+			if !hasDefault {
+				continue
+			}
+
+			if current, contains := defaults[match[1]]; contains && current != value {
+				return errors.New("placeholders of parameter reference {{" + match[1] + "}} declare different defaults")
+			}
+			defaults[match[1]] = value
+		}
+	}
+
+	return nil
+}
+
+// fetches parameterReferences like fetchParameters, falling back to the defaults of the references found missing.
+// The other references are fetched again, and the fetch fails as before when a missing reference has no default,
+// unless IgnoreMissingParameters is set: such references are then left out, or resolved to empty values.
+// Fetched parameters with an empty value are handled according to OnEmptyValue.
+func fetchParametersOrDefaults(
+	ctx context.Context,
+	service ISsmParameterService,
+	parameterReferences []string,
+	options ResolveOptions,
+	maxAges map[string]time.Duration,
+	defaults map[string]string) (map[string]SsmParameterInfo, error) {
+
+	parametersWithValues, err := fetchParameters(ctx, service, parameterReferences, options, maxAges)
+
+	var missingParametersError *MissingParametersError
+	if (len(defaults) == 0 && !options.IgnoreMissingParameters) || !errors.As(err, &missingParametersError) {
+		if err != nil {
+			return parametersWithValues, err
+		}
+		return parametersWithValues, applyEmptyValuePolicy(parametersWithValues, defaults, options.OnEmptyValue)
+	}
+
+	// Parameter Store reports missing parameters by name, the other sources by reference
+	missing := map[string]bool{}
+	for _, name := range missingParametersError.Names {
+		missing[name] = true
+	}
+
+	parameterReferencesToFetch := []string{}
+	defaultParameters := map[string]SsmParameterInfo{}
+	for _, ref := range parameterReferences {
+		name := extractParameterNameFromReference(ref)
+		if !missing[ref] && !missing[name] {
+			parameterReferencesToFetch = append(parameterReferencesToFetch, ref)
+			continue
+		}
+
+		value, hasDefault := defaults[ref]
+		if !hasDefault && !options.IgnoreMissingParameters {
+			return nil, err
+		}
+
+		if !hasDefault {
+			if options.skipMissingParameter != nil {
+				options.skipMissingParameter(ref)
+			}
+			if !options.EmptyMissingParameters {
+				continue
+			}
+		}
+
+		defaultParameters[ref] = missingParameter(ref, value)
+	}
+
+	parametersWithValues, err = fetchParameters(ctx, service, parameterReferencesToFetch, options, maxAges)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyEmptyValuePolicy(parametersWithValues, defaults, options.OnEmptyValue); err != nil {
+		return nil, err
+	}
+
+	for ref, param := range defaultParameters {
+		parametersWithValues[ref] = param
+	}
+
+	return parametersWithValues, nil
+}
+
+// returns the parameter standing in for the missing parameter of ref, with value
+func missingParameter(ref string, value string) SsmParameterInfo {
+	parameterType := stringType
+	if strings.HasPrefix(ref, ssmSecurePrefix) || isSecretReference(ref) {
+		parameterType = secureStringType
+	}
+
+	return SsmParameterInfo{Name: extractParameterNameFromReference(ref), Type: parameterType, Value: value}
+}
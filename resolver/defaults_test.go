@@ -0,0 +1,143 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//
+// Mocked service reporting the parameters it has no records of as missing from Parameter Store
+type missingParametersService struct {
+	ServiceMockedObjectWithRecords
+}
+
+func (m *missingParametersService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	parameters := map[string]SsmParameterInfo{}
+	missingNames := []string{}
+	for _, ref := range parameterReferences {
+		if param, contains := m.records[ref]; contains {
+			parameters[ref] = param
+		} else {
+			missingNames = append(missingNames, extractParameterNameFromReference(ref))
+		}
+	}
+
+	if len(missingNames) > 0 {
+		return nil, newMissingParametersError(missingNames)
+	}
+
+	return parameters, nil
+}
+
+func newMissingParametersService() *missingParametersService {
+	return &missingParametersService{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+			"ssm:/app/host":         {Name: "/app/host", Type: stringType, Value: "example.com"},
+			"ssm:/app/port":         {Name: "/app/port", Type: stringType, Value: "443"},
+			"ssm-secure:/app/token": {Name: "/app/token", Type: secureStringType, Value: "t0ken"},
+		}),
+	}
+}
+
+func TestResolveParametersInTextWithDefaults(t *testing.T) {
+	serviceObject := newMissingParametersService()
+
+	input := "host={{ssm:/app/host}}:{{ ssm:/app/port | default \"8080\" }}\n" +
+		"timeout={{ssm:/app/timeout | default \"30s\"}}\n" +
+		"banner={{ssm:/app/banner | default \"it's \\\"up\\\"\" | shellquote}}\n" +
+		"password={{ssm-secure:/app/password | default \"\"}}"
+	resolved, err := ResolveParametersInText(serviceObject, input, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "host=example.com:443\ntimeout=30s\nbanner='it'\\''s \"up\"'\npassword=", resolved)
+}
+
+func TestResolveParametersInTextFailsForMissingParametersWithoutDefault(t *testing.T) {
+	serviceObject := newMissingParametersService()
+
+	for _, input := range []string{
+		"{{ssm:/app/timeout | default \"30s\"}} {{ssm:/app/retries}}",
+		// every placeholder of a reference has to declare the default
+		"{{ssm:/app/timeout | default \"30s\"}} {{ssm:/app/timeout}}",
+	} {
+		_, err := ResolveParametersInText(serviceObject, input, ResolveOptions{})
+
+		var missingParametersError *MissingParametersError
+		assert.True(t, errors.As(err, &missingParametersError), input)
+		assert.Equal(t, StatusNotFound, StatusOf(err))
+	}
+}
+
+func TestResolveParametersInTextWithInvalidDefaults(t *testing.T) {
+	serviceObject := newMissingParametersService()
+
+	for _, input := range []string{
+		"{{ssm:/app/timeout | default 30s}}",
+		"{{ssm:/app/timeout | default \"30s}}",
+		"{{ssm:/app/timeout | default \"30s\"}} {{ssm:/app/timeout | default \"1m\"}}",
+	} {
+		_, err := ResolveParametersInText(serviceObject, input, ResolveOptions{})
+
+		assert.NotNil(t, err, input)
+		assert.Equal(t, StatusParseError, StatusOf(err), input)
+	}
+}
+
+func TestParseDefaultModifier(t *testing.T) {
+	value, isDefault, err := parseDefaultModifier("default \"a=b\"")
+	assert.Nil(t, err)
+	assert.True(t, isDefault)
+	assert.Equal(t, "a=b", value)
+
+	_, isDefault, _ = parseDefaultModifier("defaults")
+	assert.False(t, isDefault)
+
+	_, isDefault, _ = parseDefaultModifier("shellquote")
+	assert.False(t, isDefault)
+}
+
+func TestRenderSetWithDefaults(t *testing.T) {
+	serviceObject := newMissingParametersService()
+
+	set := NewRenderSet(serviceObject, ResolveOptions{})
+	assert.Nil(t, set.Add("a", "{{ssm:/app/host}}:{{ssm:/app/metrics-port | default \"9090\"}}"))
+	assert.Nil(t, set.Add("b", "{{ssm:/app/metrics-port | default \"9090\"}}"))
+	rendered, err := set.Render()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com:9090", rendered["a"])
+	assert.Equal(t, "9090", rendered["b"])
+}
+
+func TestResolveParametersInTextPartially(t *testing.T) {
+	serviceObject := newMissingParametersService()
+
+	input := "host={{ssm:/app/host}}\nport={{ssm:/app/metrics-port}}\ntimeout={{ssm:/app/timeout | default \"30s\"}}\n" +
+		"token={{ssm-secure:/app/missing-token}} {{ssm-secure:/app/missing-token}}"
+	resolved, skipped, err := ResolveParametersInTextPartially(context.Background(), serviceObject, input, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "host=example.com\nport={{ssm:/app/metrics-port}}\ntimeout=30s\n"+
+		"token={{ssm-secure:/app/missing-token}} {{ssm-secure:/app/missing-token}}", resolved)
+	assert.Equal(t, []string{"ssm-secure:/app/missing-token", "ssm:/app/metrics-port"}, skipped)
+
+	resolved, skipped, err = ResolveParametersInTextPartially(context.Background(), serviceObject, input,
+		ResolveOptions{EmptyMissingParameters: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "host=example.com\nport=\ntimeout=30s\ntoken= ", resolved)
+	assert.Equal(t, 2, len(skipped))
+}
+
+func TestResolveMapIgnoringMissingParameters(t *testing.T) {
+	serviceObject := newMissingParametersService()
+
+	values, err := ResolveMap(serviceObject, map[string]string{"host": "ssm:/app/host", "port": "ssm:/app/metrics-port"},
+		ResolveOptions{IgnoreMissingParameters: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"host": "example.com"}, values)
+}
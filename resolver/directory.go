@@ -0,0 +1,103 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+//
+// Walks the tree under inputDir and resolves every regular file matching pattern like ResolveParametersInFiles,
+// with a single set of SSM lookups, into the same relative path under outputDir, creating the directories missing
+// there. Pattern follows path.Match and is matched against the slash separated path relative to inputDir, or only
+// against the file name when it has no slash: *.conf matches the .conf files of the whole tree, conf/*.conf only
+// these of inputDir/conf. An empty pattern matches every file. outputDir is not walked when it is under inputDir.
+func ResolveParametersInDirectory(
+	service ISsmParameterService,
+	inputDir string,
+	outputDir string,
+	pattern string,
+	options ResolveOptions) error {
+
+	return ResolveParametersInDirectoryWithContext(context.Background(), service, inputDir, outputDir, pattern, options)
+}
+
+//
+// Same as ResolveParametersInDirectory, but the SSM requests are canceled when ctx is done.
+func ResolveParametersInDirectoryWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	inputDir string,
+	outputDir string,
+	pattern string,
+	options ResolveOptions) error {
+
+	if len(inputDir) == 0 || len(outputDir) == 0 {
+		return errors.New("input or output directory is not provided")
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return withStatus(StatusParseError, errors.New("invalid file name pattern "+pattern))
+	}
+
+	files := map[string]string{}
+	err := filepath.WalkDir(inputDir, func(inputFileName string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if inputFileName != inputDir && isSameFile(inputFileName, outputDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		relativeName, err := filepath.Rel(inputDir, inputFileName)
+		if err != nil {
+			return err
+		}
+
+		if !matchesFilePattern(pattern, filepath.ToSlash(relativeName)) {
+			return nil
+		}
+
+		outputFileName := filepath.Join(outputDir, relativeName)
+		if err := os.MkdirAll(filepath.Dir(outputFileName), 0755); err != nil {
+			return err
+		}
+		files[outputFileName] = inputFileName
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	return ResolveParametersInFilesWithContext(ctx, service, files, options)
+}
+
+// reports whether the slash separated relativeName matches pattern, or its file name does when pattern has no slash
+func matchesFilePattern(pattern string, relativeName string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+
+	if !strings.Contains(pattern, "/") {
+		relativeName = path.Base(relativeName)
+	}
+
+	matched, _ := path.Match(pattern, relativeName)
+	return matched
+}
@@ -0,0 +1,65 @@
+package resolver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersInDirectory(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+		"ssm:/app/port": {Name: "/app/port", Type: stringType, Value: "5432"},
+	})
+
+	dir, err := ioutil.TempDir("", "directory")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputDir := filepath.Join(dir, "templates")
+	outputDir := filepath.Join(inputDir, "out")
+	for name, template := range map[string]string{
+		"app.conf":            "host={{ssm:/app/host}}",
+		"db/pool.conf":        "port={{ssm:/app/port}}",
+		"db/README":           "{{ssm:/app/unknown}}",
+		"nginx/sites/default": "{{ssm:/app/host}}",
+	} {
+		fileName := filepath.Join(inputDir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(fileName), 0755))
+		assert.Nil(t, ioutil.WriteFile(fileName, []byte(template), 0644))
+	}
+
+	for _, run := range []struct {
+		pattern  string
+		expected map[string]string
+	}{
+		{"*.conf", map[string]string{"app.conf": "host=db.internal", "db/pool.conf": "port=5432"}},
+		{"db/*.conf", map[string]string{"db/pool.conf": "port=5432"}},
+		{"nginx/sites/*", map[string]string{"nginx/sites/default": "db.internal"}},
+		// the output directory, created while walking, is not taken for templates
+		{"[^R]*", map[string]string{"app.conf": "host=db.internal", "db/pool.conf": "port=5432", "nginx/sites/default": "db.internal"}},
+	} {
+		assert.Nil(t, os.RemoveAll(outputDir))
+		assert.Nil(t, ResolveParametersInDirectory(&serviceObject, inputDir, outputDir, run.pattern, ResolveOptions{}), run.pattern)
+
+		resolved := map[string]string{}
+		filepath.Walk(outputDir, func(fileName string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				relativeName, _ := filepath.Rel(outputDir, fileName)
+				content, _ := ioutil.ReadFile(fileName)
+				resolved[filepath.ToSlash(relativeName)] = string(content)
+			}
+			return nil
+		})
+		assert.Equal(t, run.expected, resolved, run.pattern)
+	}
+
+	err = ResolveParametersInDirectory(&serviceObject, inputDir, outputDir, "", ResolveOptions{})
+	assert.NotNil(t, err)
+
+	err = ResolveParametersInDirectory(&serviceObject, inputDir, outputDir, "[", ResolveOptions{})
+	assert.Equal(t, StatusParseError, StatusOf(err))
+}
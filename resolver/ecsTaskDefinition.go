@@ -0,0 +1,216 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//
+// Takes an Amazon ECS task definition JSON (as accepted by RegisterTaskDefinition or aws ecs register-task-definition
+// --cli-input-json) and resolves SSM parameters according to ResolveOptions only inside the environment values,
+// secrets valueFrom and dockerLabels values of its containerDefinitions. Every other field is returned untouched.
+// Resolved values are JSON-escaped, and the result is validated to have a family and containers with a name and
+// an image and well-formed environment and secrets entries.
+// The input may contain comments, trailing commas and the other JSON5 conveniences, the result is strict JSON.
+func ResolveParametersInEcsTaskDefinition(
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, error) {
+
+	return ResolveParametersInEcsTaskDefinitionWithContext(context.Background(), service, input, options)
+}
+
+//
+// Same as ResolveParametersInEcsTaskDefinition, but the SSM requests are canceled when ctx is done.
+func ResolveParametersInEcsTaskDefinitionWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, error) {
+
+	if err := checkDefaultPlaceholderSyntax(options, "ResolveParametersInEcsTaskDefinition"); err != nil {
+		return "", err
+	}
+
+	input, err := normalizeJSON5(input)
+	if err != nil {
+		return "", fmt.Errorf("task definition is not a valid JSON object: %w", err)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(input))
+	decoder.UseNumber()
+
+	var taskDefinition map[string]interface{}
+	if err := decoder.Decode(&taskDefinition); err != nil {
+		return "", fmt.Errorf("task definition is not a valid JSON object: %w", err)
+	}
+
+	resolvableValues := collectEcsResolvableValues(taskDefinition)
+
+	texts := make([]string, 0, len(resolvableValues))
+	for _, value := range resolvableValues {
+		texts = append(texts, value.get())
+	}
+
+	resolvedParametersMap, err := ExtractParametersFromTextWithContext(ctx, service, strings.Join(texts, "\n"), options)
+	if err != nil {
+		return "", err
+	}
+
+	resolvedTexts := make([]string, 0, len(resolvableValues))
+	for _, value := range resolvableValues {
+		resolved, err := replaceParameterPlaceholders(value.get(), resolvedParametersMap)
+		if err != nil {
+			return "", err
+		}
+		value.set(resolved)
+		resolvedTexts = append(resolvedTexts, resolved)
+	}
+
+	if err := checkUnresolvedPlaceholders(strings.Join(resolvedTexts, "\n"), options); err != nil {
+		return "", err
+	}
+
+	err = validateEcsTaskDefinition(taskDefinition)
+	if err != nil {
+		return "", err
+	}
+
+	var output bytes.Buffer
+	encoder := json.NewEncoder(&output)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(taskDefinition); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(output.String(), "\n"), nil
+}
+
+// string field of a decoded JSON object
+type ecsStringField struct {
+	object map[string]interface{}
+	key    string
+}
+
+func (f ecsStringField) get() string {
+	return f.object[f.key].(string)
+}
+
+func (f ecsStringField) set(value string) {
+	f.object[f.key] = value
+}
+
+// returns the string fields of the task definition that placeholders can be resolved in
+func collectEcsResolvableValues(taskDefinition map[string]interface{}) []ecsStringField {
+	fields := []ecsStringField{}
+	addField := func(object map[string]interface{}, key string) {
+		if _, isString := object[key].(string); isString {
+			fields = append(fields, ecsStringField{object: object, key: key})
+		}
+	}
+
+	containers, _ := taskDefinition["containerDefinitions"].([]interface{})
+	for _, container := range containers {
+		containerObject, isObject := container.(map[string]interface{})
+		if !isObject {
+			continue
+		}
+
+		environment, _ := containerObject["environment"].([]interface{})
+		for _, entry := range environment {
+			if entryObject, isObject := entry.(map[string]interface{}); isObject {
+				addField(entryObject, "value")
+			}
+		}
+
+		secrets, _ := containerObject["secrets"].([]interface{})
+		for _, entry := range secrets {
+			if entryObject, isObject := entry.(map[string]interface{}); isObject {
+				addField(entryObject, "valueFrom")
+			}
+		}
+
+		if labels, isObject := containerObject["dockerLabels"].(map[string]interface{}); isObject {
+			for key := range labels {
+				addField(labels, key)
+			}
+		}
+	}
+
+	return fields
+}
+
+// checks the parts of the task definition schema ECS rejects most often
+func validateEcsTaskDefinition(taskDefinition map[string]interface{}) error {
+	if family, _ := taskDefinition["family"].(string); len(family) == 0 {
+		return errors.New("task definition has no family")
+	}
+
+	containers, _ := taskDefinition["containerDefinitions"].([]interface{})
+	if len(containers) == 0 {
+		return errors.New("task definition has no containerDefinitions")
+	}
+
+	containerNames := map[string]bool{}
+	for i, container := range containers {
+		containerObject, isObject := container.(map[string]interface{})
+		if !isObject {
+			return errors.New("containerDefinitions[" + strconv.Itoa(i) + "] is not an object")
+		}
+
+		name, _ := containerObject["name"].(string)
+		if len(name) == 0 {
+			return errors.New("containerDefinitions[" + strconv.Itoa(i) + "] has no name")
+		}
+		if containerNames[name] {
+			return errors.New("container name " + name + " is used more than once")
+		}
+		containerNames[name] = true
+
+		if image, _ := containerObject["image"].(string); len(image) == 0 {
+			return errors.New("container " + name + " has no image")
+		}
+
+		err := validateEcsNameValueList(containerObject, "environment", "value", name)
+		if err != nil {
+			return err
+		}
+
+		err = validateEcsNameValueList(containerObject, "secrets", "valueFrom", name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checks that listKey of the container, if present, is a list of objects with string name and valueKey
+func validateEcsNameValueList(container map[string]interface{}, listKey string, valueKey string, containerName string) error {
+	list, present := container[listKey]
+	if !present {
+		return nil
+	}
+
+	entries, isList := list.([]interface{})
+	if !isList {
+		return errors.New(listKey + " of container " + containerName + " is not a list")
+	}
+
+	for i, entry := range entries {
+		entryObject, _ := entry.(map[string]interface{})
+		name, nameIsString := entryObject["name"].(string)
+		_, valueIsString := entryObject[valueKey].(string)
+		if !nameIsString || len(name) == 0 || !valueIsString {
+			return errors.New(listKey + "[" + strconv.Itoa(i) + "] of container " + containerName + " must have a name and a string " + valueKey)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersInEcsTaskDefinition(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/db/host":        {Name: "/app/db/host", Type: stringType, Value: `db "primary"`},
+		"ssm:/app/db/passwordArn": {Name: "/app/db/passwordArn", Type: stringType, Value: "arn:aws:ssm:us-east-1:123456789012:parameter/app/db/password"},
+		"ssm:/app/team":           {Name: "/app/team", Type: stringType, Value: "payments"},
+	})
+
+	input := `{
+  "family": "web",
+  "cpu": "256",
+  "containerDefinitions": [
+    {
+      "name": "web",
+      "image": "nginx:{{ssm:/app/team}}",
+      "memory": 512,
+      "environment": [{"name": "DB_HOST", "value": "{{ssm:/app/db/host}}"}],
+      "secrets": [{"name": "DB_PASSWORD", "valueFrom": "{{ssm:/app/db/passwordArn}}"}],
+      "dockerLabels": {"team": "{{ssm:/app/team}}"}
+    }
+  ]
+}`
+
+	output, err := ResolveParametersInEcsTaskDefinition(&serviceObject, input, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{
+  "containerDefinitions": [
+    {
+      "dockerLabels": {
+        "team": "payments"
+      },
+      "environment": [
+        {
+          "name": "DB_HOST",
+          "value": "db \"primary\""
+        }
+      ],
+      "image": "nginx:{{ssm:/app/team}}",
+      "memory": 512,
+      "name": "web",
+      "secrets": [
+        {
+          "name": "DB_PASSWORD",
+          "valueFrom": "arn:aws:ssm:us-east-1:123456789012:parameter/app/db/password"
+        }
+      ]
+    }
+  ],
+  "cpu": "256",
+  "family": "web"
+}`, output)
+}
+
+func TestResolveParametersInEcsTaskDefinitionValidatesSchema(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+
+	invalidTaskDefinitions := map[string]string{
+		"not JSON":             `{"family": `,
+		"no family":            `{"containerDefinitions": [{"name": "web", "image": "nginx"}]}`,
+		"no containers":        `{"family": "web", "containerDefinitions": []}`,
+		"no image":             `{"family": "web", "containerDefinitions": [{"name": "web"}]}`,
+		"duplicate containers": `{"family": "web", "containerDefinitions": [{"name": "web", "image": "a"}, {"name": "web", "image": "b"}]}`,
+		"numeric env value":    `{"family": "web", "containerDefinitions": [{"name": "web", "image": "a", "environment": [{"name": "PORT", "value": 80}]}]}`,
+	}
+
+	for description, taskDefinition := range invalidTaskDefinitions {
+		_, err := ResolveParametersInEcsTaskDefinition(&serviceObject, taskDefinition, ResolveOptions{})
+		assert.NotNil(t, err, description)
+	}
+}
@@ -0,0 +1,51 @@
+package resolver
+
+import "errors"
+
+//
+// EmptyValuePolicy tells how parameters with an empty value are resolved, see ResolveOptions.OnEmptyValue.
+// An accidentally blanked parameter otherwise renders an empty field that may take a service down silently.
+type EmptyValuePolicy int
+
+const (
+	// Empty values are substituted like the others
+	EmptyValueAllow EmptyValuePolicy = iota
+
+	// Empty values are substituted and reported to ResolveOptions.Warn
+	EmptyValueWarn
+
+	// Empty values fail the resolution
+	EmptyValueFail
+
+	// Empty values are replaced with the default of their placeholders, e.g. {{ssm:/app/port | default "8080"}},
+	// and fail the resolution when the placeholders declare none
+	EmptyValueUseDefault
+)
+
+// fails for the empty values of parameters when policy is EmptyValueFail or EmptyValueUseDefault, replacing them
+// with their defaults for EmptyValueUseDefault
+func applyEmptyValuePolicy(
+	parameters map[string]SsmParameterInfo,
+	defaults map[string]string,
+	policy EmptyValuePolicy) error {
+
+	if policy != EmptyValueFail && policy != EmptyValueUseDefault {
+		return nil
+	}
+
+	for ref, param := range parameters {
+		if len(param.Value) > 0 {
+			continue
+		}
+
+		value, hasDefault := defaults[ref]
+		if policy == EmptyValueFail || !hasDefault {
+			return withStatus(StatusPolicyViolation, errors.New("parameter reference {{"+ref+"}} has an empty value"))
+		}
+
+		param.Value = value
+		parameters[ref] = param
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newEmptyValuesServiceMock() ServiceMockedObjectWithRecords {
+	return NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host":  {Name: "/app/host", Type: stringType, Value: "db.internal"},
+		"ssm:/app/port":  {Name: "/app/port", Type: stringType, Value: ""},
+		"ssm:/app/empty": {Name: "/app/empty", Type: stringType, Value: ""},
+	})
+}
+
+func TestEmptyValuesAreAllowedByDefault(t *testing.T) {
+	serviceObject := newEmptyValuesServiceMock()
+
+	resolved, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/host}}:{{ssm:/app/port}}", ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "db.internal:", resolved)
+}
+
+func TestWarnAboutEmptyValues(t *testing.T) {
+	serviceObject := newEmptyValuesServiceMock()
+	warnings := []ValueWarning{}
+	options := ResolveOptions{
+		OnEmptyValue: EmptyValueWarn,
+		Warn:         func(warning ValueWarning) { warnings = append(warnings, warning) },
+	}
+
+	resolved, err := ResolveParametersInText(&serviceObject, "host={{ssm:/app/host}}\nport={{ssm:/app/port}}", options)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "host=db.internal\nport=", resolved)
+	assert.Equal(t, []ValueWarning{{Reference: "ssm:/app/port", Line: 2, Message: "value is empty"}}, warnings)
+}
+
+func TestFailOnEmptyValues(t *testing.T) {
+	serviceObject := newEmptyValuesServiceMock()
+
+	_, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/host}}:{{ssm:/app/port | default \"5432\"}}",
+		ResolveOptions{OnEmptyValue: EmptyValueFail})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+
+	_, err = ResolveMap(&serviceObject, map[string]string{"port": "ssm:/app/port"}, ResolveOptions{OnEmptyValue: EmptyValueFail})
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+}
+
+func TestUseDefaultsForEmptyValues(t *testing.T) {
+	serviceObject := newEmptyValuesServiceMock()
+	options := ResolveOptions{OnEmptyValue: EmptyValueUseDefault}
+
+	resolved, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/host}}:{{ssm:/app/port | default \"5432\"}}", options)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "db.internal:5432", resolved)
+
+	_, err = ResolveParametersInText(&serviceObject, "{{ssm:/app/host}}:{{ssm:/app/empty}}", options)
+	assert.NotNil(t, err)
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+}
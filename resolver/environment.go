@@ -0,0 +1,51 @@
+package resolver
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// tells whether parameterReference refers to a local environment variable rather than an SSM parameter
+func isEnvironmentReference(parameterReference string) bool {
+	return strings.HasPrefix(parameterReference, envPrefix)
+}
+
+// resolves env: references from the environment of the process. Variables are only readable when they are listed in
+// AllowedEnvironmentVariables and the security profile is permissive, others fail with a policy violation.
+// Unset variables are reported together by a MissingParametersError. Values are secure, like the process
+// credentials they may hold.
+func getEnvironmentVariables(parameterReferences []string, options ResolveOptions) (map[string]SsmParameterInfo, error) {
+	resolvedParametersMap := map[string]SsmParameterInfo{}
+	missingNames := []string{}
+
+	for _, ref := range parameterReferences {
+		name := extractParameterNameFromReference(ref)
+		if options.SecurityProfile >= SecurityStandard {
+			return nil, withStatus(StatusPolicyViolation, errors.New("parameter reference {{"+ref+
+				"}} reads the environment, which the security profile does not allow"))
+		}
+		if !containsString(options.AllowedEnvironmentVariables, name) {
+			return nil, withStatus(StatusPolicyViolation, errors.New("environment variable "+name+" of parameter reference {{"+ref+
+				"}} is not allowed, list it in AllowedEnvironmentVariables"))
+		}
+
+		value, found := os.LookupEnv(name)
+		if !found {
+			missingNames = append(missingNames, ref)
+			continue
+		}
+
+		resolvedParametersMap[ref] = SsmParameterInfo{
+			Name:  name,
+			Type:  secureStringType,
+			Value: value,
+		}
+	}
+
+	if len(missingNames) > 0 {
+		return nil, newMissingParametersError(missingNames)
+	}
+
+	return resolvedParametersMap, nil
+}
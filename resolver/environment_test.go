@@ -0,0 +1,106 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersInTextWithEnvironmentVariables(t *testing.T) {
+	t.Setenv("RESOLVER_TEST_HOST", "localhost")
+	t.Setenv("RESOLVER_TEST_PORT", "5432")
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/db/name": {Name: "/app/db/name", Type: stringType, Value: "orders"},
+	})
+
+	resolved, err := ResolveParametersInText(&serviceObject,
+		"postgres://{{env:RESOLVER_TEST_HOST}}:{{ env:RESOLVER_TEST_PORT | shellquote }}/{{ssm:/app/db/name}}",
+		ResolveOptions{AllowedEnvironmentVariables: []string{"RESOLVER_TEST_HOST", "RESOLVER_TEST_PORT"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "postgres://localhost:'5432'/orders", resolved)
+}
+
+func TestResolveParametersInTextWithEnvironmentVariablesOnly(t *testing.T) {
+	t.Setenv("RESOLVER_TEST_HOST", "localhost")
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+
+	resolved, err := ResolveParametersInText(&serviceObject, "host={{env:RESOLVER_TEST_HOST}}",
+		ResolveOptions{AllowedEnvironmentVariables: []string{"RESOLVER_TEST_HOST"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "host=localhost", resolved)
+}
+
+func TestEnvironmentVariablesAreOptIn(t *testing.T) {
+	t.Setenv("RESOLVER_TEST_SECRET", "topsecret")
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+	text := "key={{env:RESOLVER_TEST_SECRET}}"
+
+	_, err := ResolveParametersInText(&serviceObject, text, ResolveOptions{})
+	assert.NotNil(t, err)
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+
+	allowed := []string{"RESOLVER_TEST_SECRET"}
+	for _, profile := range []SecurityProfile{SecurityStandard, SecurityStrict} {
+		_, err = ResolveParametersInText(&serviceObject, text, ResolveOptions{AllowedEnvironmentVariables: allowed, SecurityProfile: profile})
+		assert.NotNil(t, err)
+		assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+	}
+
+	// values of the environment are secure
+	resolved, err := ResolveParametersInText(&serviceObject, text,
+		ResolveOptions{AllowedEnvironmentVariables: allowed, IgnoreSecureParameters: true})
+	assert.Nil(t, err)
+	assert.Equal(t, text, resolved)
+
+	parameters, err := ResolveParameterReferenceList(&serviceObject, []string{"env:RESOLVER_TEST_SECRET"},
+		ResolveOptions{AllowedEnvironmentVariables: allowed, IgnoreSecureParameters: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(parameters))
+}
+
+func TestResolveParametersInTextWithAllowedEnvironmentVariables(t *testing.T) {
+	t.Setenv("RESOLVER_TEST_HOST", "localhost")
+	t.Setenv("RESOLVER_TEST_TOKEN", "t0ken")
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+	options := ResolveOptions{AllowedEnvironmentVariables: []string{"RESOLVER_TEST_HOST"}}
+
+	resolved, err := ResolveParametersInText(&serviceObject, "host={{env:RESOLVER_TEST_HOST}}", options)
+	assert.Nil(t, err)
+	assert.Equal(t, "host=localhost", resolved)
+
+	_, err = ResolveParametersInText(&serviceObject, "token={{env:RESOLVER_TEST_TOKEN}}", options)
+	assert.NotNil(t, err)
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+}
+
+func TestResolveParametersInTextReportsUnsetEnvironmentVariables(t *testing.T) {
+	t.Setenv("RESOLVER_TEST_HOST", "localhost")
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+
+	_, err := ResolveParametersInText(&serviceObject, "{{env:RESOLVER_TEST_HOST}} {{env:RESOLVER_TEST_UNSET}}",
+		ResolveOptions{AllowedEnvironmentVariables: []string{"RESOLVER_TEST_HOST", "RESOLVER_TEST_UNSET"}})
+
+	var missingParametersError *MissingParametersError
+	assert.True(t, errors.As(err, &missingParametersError))
+	assert.Equal(t, []string{"env:RESOLVER_TEST_UNSET"}, missingParametersError.Names)
+}
+
+func TestEnvironmentVariablesAreNotCached(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+	options := ResolveOptions{Cache: NewMemoryCache(time.Minute), AllowedEnvironmentVariables: []string{"RESOLVER_TEST_HOST"}}
+
+	t.Setenv("RESOLVER_TEST_HOST", "localhost")
+	resolved, err := ResolveParametersInTextWithContext(context.Background(), &serviceObject, "{{env:RESOLVER_TEST_HOST}}", options)
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost", resolved)
+
+	t.Setenv("RESOLVER_TEST_HOST", "db.example.com")
+	resolved, err = ResolveParametersInTextWithContext(context.Background(), &serviceObject, "{{env:RESOLVER_TEST_HOST}}", options)
+	assert.Nil(t, err)
+	assert.Equal(t, "db.example.com", resolved)
+}
@@ -0,0 +1,108 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+)
+
+type ExportOptions struct {
+	// Export the whole subtree under the path rather than its direct children only
+	Recursive bool
+
+	// Leave SecureString parameters out of the snapshot
+	ExcludeSecureParameters bool
+
+	// When set the snapshot is encrypted with a data key generated under this KMS key.
+	// The service passed to ExportPath has to implement IKmsDataKeyService (Service does).
+	KmsKeyId string
+
+	// Name of the Compression of the snapshot, e.g. gzip, uncompressed when empty.
+	// The plaintext of encrypted snapshots is compressed before it is encrypted.
+	Compression string
+}
+
+//
+// Fetches all parameters under path and writes them to writer as a JSON Snapshot (or an EncryptedSnapshot
+// when options.KmsKeyId is set) consumable by NewSnapshotService and NewEncryptedSnapshotService, which
+// decompress snapshots compressed according to options.Compression transparently.
+func ExportPath(
+	service ISsmParameterService,
+	path string,
+	writer io.Writer,
+	options ExportOptions) error {
+
+	return ExportPathWithContext(context.Background(), service, path, writer, options)
+}
+
+//
+// Same as ExportPath, but the SSM and KMS requests are canceled when ctx is done.
+func ExportPathWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	path string,
+	writer io.Writer,
+	options ExportOptions) error {
+
+	if len(path) == 0 {
+		return errors.New("path is not provided")
+	}
+
+	parameters, err := service.callGetParametersByPath(ctx, path, options.Recursive)
+	if err != nil {
+		return err
+	}
+
+	snapshot := Snapshot{Parameters: []SsmParameterInfo{}}
+	for _, param := range parameters {
+		if options.ExcludeSecureParameters && param.Type == secureStringType {
+			continue
+		}
+		snapshot.Parameters = append(snapshot.Parameters, param)
+	}
+	sort.Slice(snapshot.Parameters, func(i, j int) bool { return snapshot.Parameters[i].Name < snapshot.Parameters[j].Name })
+
+	var document interface{} = snapshot
+	if len(options.KmsKeyId) > 0 {
+		keyService, ok := service.(IKmsDataKeyService)
+		if !ok {
+			return errors.New("service does not support KMS encryption")
+		}
+
+		plaintext, err := json.Marshal(snapshot)
+		if err != nil {
+			return err
+		}
+
+		plaintext, err = compress(options.Compression, plaintext)
+		if err != nil {
+			return err
+		}
+
+		document, err = encryptWithDataKey(ctx, keyService, options.KmsKeyId, plaintext)
+		if err != nil {
+			return err
+		}
+	}
+
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+
+	output := encoded.Bytes()
+	if len(options.KmsKeyId) == 0 {
+		output, err = compress(options.Compression, output)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = writer.Write(output)
+	return err
+}
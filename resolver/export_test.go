@@ -0,0 +1,67 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newExportTestService() ServiceMockedObjectWithRecords {
+	return NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/prod/host":            {Name: "/app/prod/host", Type: stringType, Value: "example.com"},
+		"ssm-secure:/app/prod/password": {Name: "/app/prod/password", Type: secureStringType, Value: "s3cr3t"},
+		"ssm:/app/prod/db/port":         {Name: "/app/prod/db/port", Type: stringType, Value: "5432"},
+		"ssm:/app/dev/host":             {Name: "/app/dev/host", Type: stringType, Value: "dev.example.com"},
+	})
+}
+
+func TestExportPath(t *testing.T) {
+	serviceObject := newExportTestService()
+
+	var buffer bytes.Buffer
+	err := ExportPath(&serviceObject, "/app/prod", &buffer, ExportOptions{
+		Recursive:               true,
+		ExcludeSecureParameters: true,
+	})
+	assert.Nil(t, err)
+
+	var snapshot Snapshot
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &snapshot))
+	assert.Equal(t, []SsmParameterInfo{
+		{Name: "/app/prod/db/port", Type: stringType, Value: "5432"},
+		{Name: "/app/prod/host", Type: stringType, Value: "example.com"},
+	}, snapshot.Parameters)
+}
+
+func TestExportPathNonRecursive(t *testing.T) {
+	serviceObject := newExportTestService()
+
+	var buffer bytes.Buffer
+	err := ExportPath(&serviceObject, "/app/prod/", &buffer, ExportOptions{})
+	assert.Nil(t, err)
+
+	var snapshot Snapshot
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &snapshot))
+	assert.Len(t, snapshot.Parameters, 2)
+}
+
+func TestExportPathEncryptedRoundTrip(t *testing.T) {
+	serviceObject := newExportTestService()
+
+	var buffer bytes.Buffer
+	err := ExportPath(&serviceObject, "/app/prod", &buffer, ExportOptions{
+		Recursive: true,
+		KmsKeyId:  "alias/snapshots",
+	})
+	assert.Nil(t, err)
+	assert.NotContains(t, buffer.String(), "s3cr3t")
+
+	snapshotService, err := NewEncryptedSnapshotService(&buffer, &serviceObject)
+	assert.Nil(t, err)
+
+	output, err := ResolveParametersInText(snapshotService, "{{ssm:/app/prod/host}} {{ssm-secure:/app/prod/password}}", ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com s3cr3t", output)
+}
@@ -0,0 +1,208 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+//
+// Names of the registered transformers, e.g. upper for {{ssm:/app/env | upper}}
+var transformerNameFormat = regexp.MustCompile("^[A-Za-z][\\w-]*$")
+
+//
+// Prefixes of the placeholders of the registered sources, e.g. vault:
+var sourcePrefixFormat = regexp.MustCompile("^[a-z][a-z0-9-]*:$")
+
+//
+// Name of a value in the placeholders of a registered source, e.g. secret/data/app#password in
+// {{vault:secret/data/app#password}}
+const sourceValueName = "[\\w/+=.@:#-]+"
+
+//
+// Source fetches the values of the placeholders with the prefix it is registered with by RegisterSource,
+// from a store the resolver does not know about, e.g. HashiCorp Vault or an internal configuration service.
+type Source struct {
+	// Returns the value named name, the parameter reference without its prefix. A missing value is reported
+	// with a MissingParametersError naming it. Fetch is called concurrently.
+	Fetch func(ctx context.Context, name string) (string, error)
+
+	// The values are secrets: their placeholders are left unresolved like ssm-secure: ones
+	// when ResolveOptions.IgnoreSecureParameters is set
+	Secure bool
+}
+
+// a registered Source with the placeholders of its prefix
+type registeredSource struct {
+	Source
+	placeholder *regexp.Regexp
+}
+
+var sourcesMutex sync.RWMutex
+
+//
+// Sources registered by RegisterSource, by prefix
+var sources = map[string]registeredSource{}
+
+var registeredTransformersMutex sync.Mutex
+
+//
+// Names of the transformers registered by RegisterTransformer, which can be replaced unlike the built-in ones
+var registeredTransformers = map[string]bool{}
+
+var secretSinksMutex sync.RWMutex
+
+//
+// Sinks registered by RegisterSecretSink, by name
+var registeredSecretSinks = map[string]SecretSink{}
+
+//
+// Registers transform as the transformer named name, e.g. RegisterTransformer("upper", ...) for
+// {{ssm:/app/env | upper}}, replacing a transformer registered before. Built-in transformers, constraints and
+// modifiers cannot be replaced.
+func RegisterTransformer(name string, transform func(value string) (string, error)) error {
+	if !transformerNameFormat.MatchString(name) || name == defaultModifier {
+		return errors.New("invalid transformer name " + name)
+	}
+	if transform == nil {
+		return errors.New("transformer " + name + " is nil")
+	}
+
+	registeredTransformersMutex.Lock()
+	defer registeredTransformersMutex.Unlock()
+
+	_, isTransformer := lookupTransformer(name)
+	_, isArgumentTransformer := argumentTransformers[name]
+	_, isConstraint := constraints[name]
+	if (isTransformer || isArgumentTransformer || isConstraint) && !registeredTransformers[name] {
+		return errors.New("transformer " + name + " is built in")
+	}
+
+	transformersMutex.Lock()
+	defer transformersMutex.Unlock()
+
+	transformers[name] = transform
+	registeredTransformers[name] = true
+	return nil
+}
+
+//
+// Registers source for the placeholders with prefix, e.g. vault: for {{vault:secret/data/app#password}},
+// replacing a source registered before for prefix. Prefixes of the built-in placeholders cannot be registered.
+// Placeholders of sources use the {{ }} delimiters of the default placeholder syntax. Sources are usually registered
+// while the program initializes, e.g. from an init function or a plugin loaded by the plugins package; documents
+// resolved while a source is registered may or may not see its placeholders.
+func RegisterSource(prefix string, source Source) error {
+	if !sourcePrefixFormat.MatchString(prefix) {
+		return errors.New("invalid source prefix " + prefix)
+	}
+	for _, builtIn := range []string{ssmNonSecurePrefix, ssmSecurePrefix, secretsManagerPrefix, envPrefix, renderPrefix} {
+		if prefix == builtIn {
+			return errors.New("source prefix " + prefix + " is built in")
+		}
+	}
+	if source.Fetch == nil {
+		return errors.New("source " + prefix + " has no Fetch function")
+	}
+
+	sourcesMutex.Lock()
+	defer sourcesMutex.Unlock()
+
+	registered, replaced := sources[prefix]
+	if !replaced {
+		registered.placeholder = regexp.MustCompile("{{\\s*(" + regexp.QuoteMeta(prefix) + sourceValueName + ")\\s*" + placeholderModifiers + "}}")
+		placeholders := parameterPlaceholders()
+		allParameterPlaceholders.Store(append(append([]*regexp.Regexp{}, placeholders...), registered.placeholder))
+	}
+	registered.Source = source
+	sources[prefix] = registered
+
+	return nil
+}
+
+//
+// Registers sink under name, replacing a sink registered before. Secure placeholders select it like the sinks of
+// ResolveOptions.SecretSinks, e.g. {{ssm-secure:/app/token | sink=keyring}}, which take precedence over it.
+func RegisterSecretSink(name string, sink SecretSink) error {
+	if len(name) == 0 || sink == nil {
+		return errors.New("secret sink name or sink is not provided")
+	}
+
+	secretSinksMutex.Lock()
+	defer secretSinksMutex.Unlock()
+
+	registeredSecretSinks[name] = sink
+	return nil
+}
+
+// returns the sink named name among the sinks of options, then among the registered ones
+func lookupSecretSink(name string, options ResolveOptions) (SecretSink, bool) {
+	if sink, contains := options.SecretSinks[name]; contains {
+		return sink, true
+	}
+
+	secretSinksMutex.RLock()
+	defer secretSinksMutex.RUnlock()
+
+	sink, contains := registeredSecretSinks[name]
+	return sink, contains
+}
+
+// returns the registered source of parameterReference, if any
+func lookupSource(parameterReference string) (registeredSource, bool) {
+	separator := strings.Index(parameterReference, ":")
+	if separator < 0 {
+		return registeredSource{}, false
+	}
+
+	sourcesMutex.RLock()
+	defer sourcesMutex.RUnlock()
+
+	source, found := sources[parameterReference[:separator+1]]
+	return source, found
+}
+
+// tells whether parameterReference has the prefix of a registered source
+func isSourceReference(parameterReference string) bool {
+	_, found := lookupSource(parameterReference)
+	return found
+}
+
+// calls fn with the start and end of the reference in every placeholder of a registered source in text,
+// skipping the secure sources when ignoreSecureParameters is set
+func forEachSourcePlaceholder(text string, ignoreSecureParameters bool, fn func(match []int)) {
+	sourcesMutex.RLock()
+	defer sourcesMutex.RUnlock()
+
+	for _, source := range sources {
+		if !source.Secure || !ignoreSecureParameters {
+			forEachMatch(source.placeholder, text, fn)
+		}
+	}
+}
+
+// fetches the value of parameterReference from its registered source
+func getSourceValue(ctx context.Context, parameterReference string) (SsmParameterInfo, error) {
+	source, found := lookupSource(parameterReference)
+	if !found {
+		return SsmParameterInfo{}, errors.New("no source is registered for parameter reference {{" + parameterReference + "}}")
+	}
+
+	value, err := source.Fetch(ctx, extractParameterNameFromReference(parameterReference))
+	if err != nil {
+		var missingParametersError *MissingParametersError
+		if errors.As(err, &missingParametersError) {
+			return SsmParameterInfo{}, err
+		}
+		return SsmParameterInfo{}, newParameterReferenceError(parameterReference, "cannot fetch parameter reference {{%s}}: %w", err)
+	}
+
+	param := SsmParameterInfo{Name: parameterReference, Type: stringType, Value: value}
+	if source.Secure {
+		param.Type = secureStringType
+	}
+
+	return param, nil
+}
@@ -0,0 +1,123 @@
+package resolver
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// drops the source registered for prefix and its placeholders
+func unregisterSource(prefix string) {
+	sourcesMutex.Lock()
+	defer sourcesMutex.Unlock()
+
+	placeholders := []*regexp.Regexp{}
+	for _, placeholder := range parameterPlaceholders() {
+		if placeholder != sources[prefix].placeholder {
+			placeholders = append(placeholders, placeholder)
+		}
+	}
+	allParameterPlaceholders.Store(placeholders)
+	delete(sources, prefix)
+}
+
+func TestRegisterSource(t *testing.T) {
+	values := map[string]string{"secret/data/app#user": "admin", "secret/data/app#password": "s3cr3t"}
+	fetch := func(ctx context.Context, name string) (string, error) {
+		value, found := values[name]
+		if !found {
+			return "", &MissingParametersError{Names: []string{name}}
+		}
+		return value, nil
+	}
+
+	assert.Nil(t, RegisterSource("testvault:", Source{Fetch: fetch}))
+	assert.Nil(t, RegisterSource("testvault-secure:", Source{Fetch: fetch, Secure: true}))
+	defer unregisterSource("testvault:")
+	defer unregisterSource("testvault-secure:")
+
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	text := "{{testvault:secret/data/app#user | shellquote}}@{{ssm:/app/host}} {{testvault-secure:secret/data/app#password}}"
+	output, err := ResolveParametersInText(&serviceObject, text, ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "'admin'@db.internal s3cr3t", output)
+
+	output, err = ResolveParametersInText(&serviceObject, text, ResolveOptions{IgnoreSecureParameters: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "'admin'@db.internal {{testvault-secure:secret/data/app#password}}", output)
+
+	_, err = ResolveParametersInText(&serviceObject, "{{testvault:secret/data/app#missing}}", ResolveOptions{})
+	assert.NotNil(t, err)
+	assert.Equal(t, StatusNotFound, StatusOf(err))
+
+	assert.Contains(t, Capabilities().PlaceholderPrefixes, "testvault:")
+
+	for _, prefix := range []string{"ssm:", "env:", "Vault:", "vault"} {
+		assert.NotNil(t, RegisterSource(prefix, Source{Fetch: fetch}), prefix)
+	}
+}
+
+func TestRegisterTransformer(t *testing.T) {
+	assert.Nil(t, RegisterTransformer("testupper", func(value string) (string, error) {
+		return strings.ToUpper(value), nil
+	}))
+	defer func() {
+		transformersMutex.Lock()
+		delete(transformers, "testupper")
+		transformersMutex.Unlock()
+	}()
+
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/env": {Name: "/app/env", Type: stringType, Value: "prod"},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/env | testupper}}", ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "PROD", output)
+
+	for _, name := range []string{shellQuoteTransformer, eachTransformer, typeConstraint, "a|b"} {
+		assert.NotNil(t, RegisterTransformer(name, oneLine), name)
+	}
+}
+
+func TestRegisteredSourcesWithIgnoredSecureParameters(t *testing.T) {
+	fetch := func(ctx context.Context, name string) (string, error) { return "value of " + name, nil }
+	assert.Nil(t, RegisterSource("testconfig:", Source{Fetch: fetch}))
+	assert.Nil(t, RegisterSource("testsecrets:", Source{Fetch: fetch, Secure: true}))
+	defer unregisterSource("testconfig:")
+	defer unregisterSource("testsecrets:")
+
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+	parameters, err := ResolveParameterReferenceListWithContext(context.Background(), &serviceObject,
+		[]string{"testconfig:app/host", "testsecrets:app/token"}, ResolveOptions{IgnoreSecureParameters: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(parameters))
+	assert.Equal(t, "value of app/host", parameters["testconfig:app/host"].Value)
+}
+
+func TestRegisterSourceWhileResolving(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			_, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/host | shellquote}}", ResolveOptions{Format: propertiesFormat})
+			assert.Nil(t, err)
+		}
+	}()
+
+	fetch := func(ctx context.Context, name string) (string, error) { return name, nil }
+	assert.Nil(t, RegisterSource("testrace:", Source{Fetch: fetch}))
+	defer unregisterSource("testrace:")
+	<-done
+}
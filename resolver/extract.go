@@ -0,0 +1,99 @@
+package resolver
+
+import "context"
+
+//
+// Result of ExtractParametersFromFiles
+type ExtractionReport struct {
+	// Resolved parameters of all the files, keyed by parameter reference
+	Parameters map[string]SsmParameterInfo
+
+	// Parameter references found in every file, keyed by file name
+	References map[string][]string
+}
+
+//
+// Reads inputFileNames and resolves the SSM parameters referenced in them according to ResolveOptions like
+// ResolveParametersInFile does, including the validation of placeholder modifiers and constraints, but leaves
+// the files untouched and writes nothing. Meant for pipelines rendering the documents with a different engine.
+// The parameters of all the files are fetched together.
+func ExtractParametersFromFiles(
+	service ISsmParameterService,
+	inputFileNames []string,
+	options ResolveOptions) (ExtractionReport, error) {
+
+	return ExtractParametersFromFilesWithContext(context.Background(), service, inputFileNames, options)
+}
+
+//
+// Same as ExtractParametersFromFiles, but the SSM requests are canceled when ctx is done.
+func ExtractParametersFromFilesWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	inputFileNames []string,
+	options ResolveOptions) (ExtractionReport, error) {
+
+	report := ExtractionReport{
+		Parameters: map[string]SsmParameterInfo{},
+		References: map[string][]string{},
+	}
+
+	options.allowBinaryValues = true
+
+	texts := map[string]string{}
+	allTexts := []string{}
+	allReferences := []string{}
+	for _, inputFileName := range inputFileNames {
+		text, err := readValidatedTextFromFile(inputFileName)
+		if err != nil {
+			return report, err
+		}
+
+		text, err = prepareTemplate(ctx, service, text, options)
+		if err != nil {
+			return report, err
+		}
+
+		references, err := parseAndValidatePlaceholders(text, options)
+		if err != nil {
+			return report, err
+		}
+
+		texts[inputFileName] = text
+		allTexts = append(allTexts, text)
+		report.References[inputFileName] = references
+		allReferences = append(allReferences, references...)
+	}
+
+	resolvedParametersMap, err := resolveParsedReferences(ctx, service, dedupSlice(allReferences), options,
+		placeholderMaxAges(allTexts...), placeholderDefaults(allTexts...))
+	if err != nil {
+		return report, err
+	}
+
+	// substitutes into copies nobody reads to apply the transformers and constraints of every placeholder
+	for _, text := range texts {
+		resolvedParametersMap, err = sanitizeResolvedValues(text, resolvedParametersMap, options.SanitizeValues)
+		if err != nil {
+			return report, err
+		}
+
+		_, err = replaceParameterPlaceholders(text, resolvedParametersMap)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	sinks, err := placeholderSinks(allTexts...)
+	if err != nil {
+		return report, err
+	}
+
+	resolvedParametersMap, err = deliverSecrets(ctx, resolvedParametersMap, sinks, options)
+	if err != nil {
+		return report, err
+	}
+	report.Parameters = resolvedParametersMap
+
+	return report, nil
+}
@@ -0,0 +1,81 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractParametersFromFiles(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+		"ssm:/app/port": {Name: "/app/port", Type: stringType, Value: "443"},
+	})
+
+	dir, err := ioutil.TempDir("", "extract")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	webFileName := filepath.Join(dir, "web.conf")
+	workerFileName := filepath.Join(dir, "worker.conf")
+	web := "listen {{ssm:/app/host}}:{{ssm:/app/port | type=int}};"
+	assert.Nil(t, ioutil.WriteFile(webFileName, []byte(web), 0644))
+	assert.Nil(t, ioutil.WriteFile(workerFileName, []byte("upstream {{ssm:/app/host}};"), 0644))
+
+	report, err := ExtractParametersFromFiles(&serviceObject, []string{webFileName, workerFileName}, ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+		"ssm:/app/port": {Name: "/app/port", Type: stringType, Value: "443"},
+	}, report.Parameters)
+
+	sort.Strings(report.References[webFileName])
+	assert.Equal(t, []string{"ssm:/app/host", "ssm:/app/port"}, report.References[webFileName])
+	assert.Equal(t, []string{"ssm:/app/host"}, report.References[workerFileName])
+
+	unchanged, err := ioutil.ReadFile(webFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, web, string(unchanged))
+}
+
+func TestExtractParametersFromFilesChecksConstraints(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+	})
+
+	dir, err := ioutil.TempDir("", "extract")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "web.conf")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("port {{ssm:/app/host | type=int}};"), 0644))
+
+	_, err = ExtractParametersFromFiles(&serviceObject, []string{inputFileName}, ResolveOptions{})
+	assert.NotNil(t, err)
+}
+
+func TestExtractParametersFromFilesWithContextCanceled(t *testing.T) {
+	serviceObject := &slowService{ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+	}), delay: time.Second}
+
+	dir, err := ioutil.TempDir("", "extract")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "web.conf")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("listen {{ssm:/app/host}};"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ExtractParametersFromFilesWithContext(ctx, serviceObject, []string{inputFileName}, ResolveOptions{})
+
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,180 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+//
+// SyncPolicy selects when written files are flushed to disk, trading durability against render latency
+type SyncPolicy int
+
+const (
+	// Files are flushed by the OS whenever it decides to
+	SyncNone SyncPolicy = iota
+
+	// Every file is flushed right after it is written, before the next write starts on the same writer
+	SyncPerFile
+
+	// All files are written first and flushed together once the last one is written, letting slow disks
+	// coalesce the writes. Single file APIs flush their file like SyncPerFile.
+	SyncPerBatch
+)
+
+//
+// Takes a map of (output file name) to (input file name), resolves SSM parameters in all inputs according to
+// ResolveOptions with a single set of SSM lookups and writes every resolved document to its output file, in the format
+// of the output file. At most MaxParallelWriters files are written at the same time and they are flushed according
+// to SyncPolicy. Nothing is written when an input cannot be read or resolved; the returned error is the first
+// failed write in output file name order, the other files are written anyway. When ResolveOptions.Verify is set,
+// the written files are verified together and all of them are rolled back on any failure.
+func ResolveParametersInFiles(
+	service ISsmParameterService,
+	files map[string]string,
+	options ResolveOptions) error {
+
+	return ResolveParametersInFilesWithContext(context.Background(), service, files, options)
+}
+
+//
+// Same as ResolveParametersInFiles, but the SSM requests are canceled when ctx is done.
+func ResolveParametersInFilesWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	files map[string]string,
+	options ResolveOptions) error {
+
+	outputFileNames := make([]string, 0, len(files))
+	for outputFileName := range files {
+		if len(outputFileName) == 0 {
+			return errors.New("output file name is not provided")
+		}
+		outputFileNames = append(outputFileNames, outputFileName)
+	}
+	sort.Strings(outputFileNames)
+
+	// outputs overwriting one of the inputs are replaced atomically, the templates are never left truncated
+	inPlace := map[string]bool{}
+	for _, outputFileName := range outputFileNames {
+		for _, inputFileName := range files {
+			if isSameFile(inputFileName, outputFileName) {
+				inPlace[outputFileName] = true
+			}
+		}
+	}
+
+	unresolvedTexts := map[string]string{}
+	for _, outputFileName := range outputFileNames {
+		text, err := readValidatedTextFromFile(files[outputFileName])
+		if err != nil {
+			return err
+		}
+
+		text, err = prepareTemplate(ctx, service, text, options)
+		if err != nil {
+			return err
+		}
+
+		_, err = lookupFormat(options.Format, outputFileName)
+		if err != nil {
+			return err
+		}
+		unresolvedTexts[outputFileName] = text
+	}
+
+	allTexts := make([]string, 0, len(unresolvedTexts))
+	for _, outputFileName := range outputFileNames {
+		allTexts = append(allTexts, unresolvedTexts[outputFileName])
+	}
+
+	options.allowBinaryValues = true
+	resolvedParametersMap, err := extractParametersFromTexts(ctx, service, allTexts, withDefaultPlaceholderSyntax(options))
+	if err != nil {
+		return err
+	}
+
+	resolvedTexts := map[string]string{}
+	for _, outputFileName := range outputFileNames {
+		resolvedTexts[outputFileName], err = renderOutputFile(ctx, unresolvedTexts[outputFileName], outputFileName, resolvedParametersMap, options)
+		if err != nil {
+			return fmt.Errorf("cannot render %s: %w", outputFileName, err)
+		}
+	}
+
+	var previousFiles map[string]previousOutputFile
+	if options.Verify != nil {
+		previousFiles, err = savePreviousOutputFiles(outputFileNames)
+		if err != nil {
+			return fmt.Errorf("cannot save previous output files: %w", err)
+		}
+	}
+
+	writeErrors := writeOutputFiles(outputFileNames, resolvedTexts, inPlace, options)
+
+	if options.SyncPolicy == SyncPerBatch {
+		for i, outputFileName := range outputFileNames {
+			if writeErrors[i] == nil {
+				writeErrors[i] = syncFile(outputFileName)
+			}
+		}
+	}
+
+	err = nil
+	for i, writeErr := range writeErrors {
+		if writeErr != nil {
+			err = fmt.Errorf("cannot write %s: %w", outputFileNames[i], writeErr)
+			break
+		}
+	}
+
+	if options.Verify == nil {
+		return err
+	}
+
+	if err == nil {
+		err = options.Verify(ctx, outputFileNames)
+		if err != nil {
+			err = fmt.Errorf("verification of written files failed: %w", err)
+		}
+	}
+
+	if err != nil {
+		restoreErr := restorePreviousOutputFiles(outputFileNames, previousFiles, options)
+		if restoreErr != nil {
+			return fmt.Errorf("%w, previous files not restored: %v", err, restoreErr)
+		}
+		return fmt.Errorf("%w, previous files restored", err)
+	}
+
+	return nil
+}
+
+// writes the resolved text of every output file with at most MaxParallelWriters writers, atomically for the files
+// of inPlace, and returns the write error of every file, in the order of outputFileNames
+func writeOutputFiles(outputFileNames []string, resolvedTexts map[string]string, inPlace map[string]bool, options ResolveOptions) []error {
+	maxParallelWriters := options.MaxParallelWriters
+	if maxParallelWriters < 1 {
+		maxParallelWriters = 1
+	}
+	semaphore := make(chan struct{}, maxParallelWriters)
+
+	writeErrors := make([]error, len(outputFileNames))
+
+	var wg sync.WaitGroup
+	for i, outputFileName := range outputFileNames {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, outputFileName string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			writeErrors[i] = writeOutputFile(resolvedTexts[outputFileName], outputFileName, inPlace[outputFileName], options)
+		}(i, outputFileName)
+	}
+	wg.Wait()
+
+	return writeErrors
+}
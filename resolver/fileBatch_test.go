@@ -0,0 +1,238 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersInFiles(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db=internal"},
+		"ssm:/app/port": {Name: "/app/port", Type: stringType, Value: "5432"},
+	})
+
+	dir, err := ioutil.TempDir("", "fileBatch")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{}
+	for i, template := range []string{"host={{ssm:/app/host}}", "port: {{ssm:/app/port}}", "{{ssm:/app/host}}:{{ssm:/app/port}}"} {
+		inputFileName := filepath.Join(dir, "input"+string(rune('0'+i)))
+		assert.Nil(t, ioutil.WriteFile(inputFileName, []byte(template), 0644))
+		files[filepath.Join(dir, []string{"app.properties", "app.yaml", "app.txt"}[i])] = inputFileName
+	}
+
+	for _, syncPolicy := range []SyncPolicy{SyncNone, SyncPerFile, SyncPerBatch} {
+		err = ResolveParametersInFiles(&serviceObject, files, ResolveOptions{MaxParallelWriters: 2, SyncPolicy: syncPolicy})
+		assert.Nil(t, err)
+
+		for outputFileName, expected := range map[string]string{
+			"app.properties": "host=db\\=internal",
+			"app.yaml":       "port: 5432",
+			"app.txt":        "db=internal:5432",
+		} {
+			output, err := ioutil.ReadFile(filepath.Join(dir, outputFileName))
+			assert.Nil(t, err)
+			assert.Equal(t, expected, string(output))
+		}
+	}
+}
+
+func TestResolveParametersInFilesWriteFailure(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	dir, err := ioutil.TempDir("", "fileBatch")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "input")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("{{ssm:/app/host}}"), 0644))
+
+	err = ResolveParametersInFiles(&serviceObject, map[string]string{
+		filepath.Join(dir, "missing", "a.conf"): inputFileName,
+		filepath.Join(dir, "b.conf"):            inputFileName,
+	}, ResolveOptions{MaxParallelWriters: 4, SyncPolicy: SyncPerBatch})
+	assert.NotNil(t, err)
+
+	output, err := ioutil.ReadFile(filepath.Join(dir, "b.conf"))
+	assert.Nil(t, err)
+	assert.Equal(t, "db.internal", string(output))
+}
+
+func TestResolveParametersInFileSyncPolicy(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	dir, err := ioutil.TempDir("", "fileBatch")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "input")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("host {{ssm:/app/host}}"), 0644))
+
+	outputFileName := filepath.Join(dir, "output")
+	assert.Nil(t, ResolveParametersInFile(&serviceObject, inputFileName, outputFileName, ResolveOptions{SyncPolicy: SyncPerBatch}))
+
+	output, err := ioutil.ReadFile(outputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, "host db.internal", string(output))
+}
+
+func TestResolveParametersInFileInPlace(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	dir, err := ioutil.TempDir("", "fileBatch")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "app.conf")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("host {{ssm:/app/host}}"), 0640))
+
+	// the output is the input through a symlink, which is kept
+	linkName := filepath.Join(dir, "app.link")
+	assert.Nil(t, os.Symlink(inputFileName, linkName))
+	assert.True(t, isSameFile(inputFileName, linkName))
+
+	assert.Nil(t, ResolveParametersInFile(&serviceObject, inputFileName, linkName, ResolveOptions{}))
+
+	output, err := ioutil.ReadFile(inputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, "host db.internal", string(output))
+
+	linkInfo, err := os.Lstat(linkName)
+	assert.Nil(t, err)
+	assert.True(t, linkInfo.Mode()&os.ModeSymlink != 0)
+
+	stats, err := os.Stat(inputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0640), stats.Mode().Perm())
+
+	entries, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entries))
+}
+
+func TestResolveParametersInFilesInPlace(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+		"ssm:/app/port": {Name: "/app/port", Type: stringType, Value: "5432"},
+	})
+
+	dir, err := ioutil.TempDir("", "fileBatch")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	hostFileName := filepath.Join(dir, "host.conf")
+	portFileName := filepath.Join(dir, "port.conf")
+	assert.Nil(t, ioutil.WriteFile(hostFileName, []byte("{{ssm:/app/host}}"), 0644))
+	assert.Nil(t, ioutil.WriteFile(portFileName, []byte("{{ssm:/app/port}}"), 0644))
+
+	err = ResolveParametersInFiles(&serviceObject, map[string]string{
+		hostFileName: hostFileName,
+		portFileName: portFileName,
+	}, ResolveOptions{MaxParallelWriters: 2})
+	assert.Nil(t, err)
+
+	for fileName, expected := range map[string]string{hostFileName: "db.internal", portFileName: "5432"} {
+		output, err := ioutil.ReadFile(fileName)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, string(output))
+	}
+}
+
+func TestResolveParametersInPlaceWithBackup(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	dir, err := ioutil.TempDir("", "fileBatch")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "app.conf")
+	assert.Nil(t, ioutil.WriteFile(fileName, []byte("host {{ssm:/app/host}}"), 0600))
+	assert.Nil(t, ioutil.WriteFile(fileName+".bak", []byte("stale"), 0644))
+
+	assert.Nil(t, ResolveParametersInPlace(&serviceObject, fileName, ResolveOptions{BackupSuffix: ".bak"}))
+
+	for name, expected := range map[string]string{fileName: "host db.internal", fileName + ".bak": "host {{ssm:/app/host}}"} {
+		output, err := ioutil.ReadFile(name)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, string(output))
+
+		stats, err := os.Stat(name)
+		assert.Nil(t, err)
+		assert.Equal(t, os.FileMode(0600), stats.Mode().Perm())
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entries))
+
+	// a failed resolution leaves the file and its backup alone
+	assert.Nil(t, ioutil.WriteFile(fileName, []byte("{{ssm:/app/missing}}"), 0600))
+	assert.NotNil(t, ResolveParametersInPlace(&serviceObject, fileName, ResolveOptions{BackupSuffix: ".bak"}))
+
+	output, err := ioutil.ReadFile(fileName + ".bak")
+	assert.Nil(t, err)
+	assert.Equal(t, "host {{ssm:/app/host}}", string(output))
+}
+
+func TestResolveParametersInFilesAppliesLimitsPerFile(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+		"ssm:/app/port": {Name: "/app/port", Type: stringType, Value: "5432"},
+	})
+
+	dir, err := ioutil.TempDir("", "fileBatch")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{}
+	for i, template := range []string{"host={{ssm:/app/host}}", "port={{ssm:/app/port}}"} {
+		inputFileName := filepath.Join(dir, "input"+string(rune('0'+i)))
+		assert.Nil(t, ioutil.WriteFile(inputFileName, []byte(template), 0644))
+		files[filepath.Join(dir, "output"+string(rune('0'+i)))] = inputFileName
+	}
+
+	err = ResolveParametersInFiles(&serviceObject, files, ResolveOptions{Limits: ResolveLimits{MaxPlaceholders: 1}})
+	assert.Nil(t, err)
+
+	output, err := ioutil.ReadFile(filepath.Join(dir, "output1"))
+	assert.Nil(t, err)
+	assert.Equal(t, "port=5432", string(output))
+}
+
+func TestResolveParametersInFilesWithContextCanceled(t *testing.T) {
+	serviceObject := &slowService{ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	}), delay: time.Second}
+
+	dir, err := ioutil.TempDir("", "fileBatch")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "input")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("host={{ssm:/app/host}}"), 0644))
+	outputFileName := filepath.Join(dir, "output")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = ResolveParametersInFilesWithContext(ctx, serviceObject, map[string]string{outputFileName: inputFileName}, ResolveOptions{})
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	_, err = os.Stat(outputFileName)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 )
 
 //
@@ -37,18 +38,158 @@ func readTextFromFile(source string) (string, error) {
 	return unresolvedText, nil
 }
 
+// validates the file size and returns the text of the file
+func readValidatedTextFromFile(source string) (string, error) {
+	if len(source) == 0 {
+		return "", errors.New("file name is not provided")
+	}
+
+	err := validateFileAndSize(source)
+	if err != nil {
+		return "", err
+	}
+
+	return readTextFromFile(source)
+}
+
 func writeToFile(resolvedText string, destination string) error {
 	f, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
 
-	defer f.Close()
+	_, err = f.WriteString(resolvedText)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
 
+// writes text to a temporary file next to destination, flushes it to disk and renames it over destination, so that
+// destination is never seen truncated, e.g. when it is the template being resolved, even after a crash.
+// A symlink destination is kept, its target is replaced. The previous content of the target is kept in the file
+// named after it with backupSuffix, unless backupSuffix is empty.
+func writeToFileAtomically(text string, destination string, backupSuffix string) error {
+	target, err := filepath.EvalSymlinks(destination)
 	if err != nil {
 		return err
 	}
-	_, err = f.WriteString(resolvedText)
+
+	info, err := os.Stat(target)
 	if err != nil {
 		return err
 	}
 
+	f, err := ioutil.TempFile(filepath.Dir(target), "."+filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(text)
+	if err == nil {
+		err = f.Chmod(info.Mode().Perm())
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(backupSuffix) > 0 {
+		if err := backupFile(target, target+backupSuffix, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(f.Name(), target); err != nil {
+		return err
+	}
+
+	// the rename itself is durable once the directory is flushed, which not every platform supports
+	_ = syncDirectory(filepath.Dir(target))
 	return nil
 }
+
+// keeps the content of source in backup, by a hard link when the file system supports them or by a copy otherwise
+func backupFile(source string, backup string, perm os.FileMode) error {
+	if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if os.Link(source, backup) == nil {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(source)
+	if err != nil {
+		return err
+	}
+
+	if err := writeToFileWithPermissions(string(content), backup, perm); err != nil {
+		return err
+	}
+
+	return syncFile(backup)
+}
+
+// tells whether the paths refer to the same existing file, through symlinks and hard links too
+func isSameFile(first string, second string) bool {
+	firstInfo, err := os.Stat(first)
+	if err != nil {
+		return false
+	}
+
+	secondInfo, err := os.Stat(second)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(firstInfo, secondInfo)
+}
+
+// writes text to destination creating or truncating the file with the given permissions
+func writeToFileWithPermissions(text string, destination string, perm os.FileMode) error {
+	f, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	// permissions of an existing file are not changed by OpenFile
+	err = f.Chmod(perm)
+	if err == nil {
+		_, err = f.WriteString(text)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// flushes the content of the file to disk with fsync
+func syncFile(name string) error {
+	f, err := os.OpenFile(name, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return f.Sync()
+}
+
+// flushes the entries of the directory to disk with fsync, e.g. after a rename in it
+func syncDirectory(name string) error {
+	dir, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
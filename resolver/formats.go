@@ -0,0 +1,189 @@
+package resolver
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const propertiesFormat = "properties"
+const iniFormat = "ini"
+
+//
+// Format escapes the value substituted for the placeholder starting at offset in template according to the syntax
+// of the document around it, so that resolved values cannot break the document. Transformers are applied before.
+type Format func(template string, offset int, value string) (string, error)
+
+var formatsMutex sync.RWMutex
+
+//
+// Formats by name, selected with ResolveOptions.Format
+var formats = map[string]Format{
+	propertiesFormat: escapePropertiesValue,
+	iniFormat:        escapeIniValue,
+	tomlFormat:       escapeTomlValue,
+	jsonFormat:       escapeJsonValue,
+	xmlFormat:        escapeXmlValue,
+	yamlFormat:       escapeYamlValue,
+}
+
+//
+// Formats by file name extension, used by the file APIs when ResolveOptions.Format is not set
+var formatExtensions = map[string]string{
+	".properties": propertiesFormat,
+	".ini":        iniFormat,
+	".toml":       tomlFormat,
+	".xml":        xmlFormat,
+}
+
+//
+// Registers format under name, replacing a format registered before, and makes the file APIs use it
+// for files with one of the extensions (e.g. ".conf").
+func RegisterFormat(name string, format Format, extensions ...string) {
+	formatsMutex.Lock()
+	defer formatsMutex.Unlock()
+
+	formats[name] = format
+	for _, extension := range extensions {
+		formatExtensions[strings.ToLower(extension)] = name
+	}
+}
+
+// returns the format named name, or the format of the extension of fileName when name is empty.
+// It returns nil when neither selects a format.
+func lookupFormat(name string, fileName string) (Format, error) {
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+
+	name = selectFormatName(name, fileName)
+	if len(name) == 0 {
+		return nil, nil
+	}
+
+	format, known := formats[name]
+	if !known {
+		return nil, withStatus(StatusParseError, errors.New("unknown format "+name))
+	}
+
+	return format, nil
+}
+
+// transformers whose output the format named by the key substitutes as is, besides escapingTransformers:
+// oneline already writes newlines as the \n escape of JSON and double-quoted YAML strings
+var formatSkippingTransformers = map[string][]string{
+	jsonFormat: {oneLineTransformer},
+	yamlFormat: {oneLineTransformer},
+}
+
+// reports whether the value of a placeholder with modifiers is substituted without the escaping of the format named
+// formatName: the last transformer already escaped it for the document, escaping it again would corrupt it
+func skipsFormat(formatName string, modifiers []string) bool {
+	if len(modifiers) == 0 {
+		return false
+	}
+
+	last := modifiers[len(modifiers)-1]
+	return containsString(escapingTransformers, last) || containsString(formatSkippingTransformers[formatName], last)
+}
+
+// returns name, or the name of the format of the extension of fileName when name is empty
+func selectFormatName(name string, fileName string) string {
+	if len(name) == 0 {
+		return formatExtensions[strings.ToLower(filepath.Ext(fileName))]
+	}
+
+	return name
+}
+
+// placeholder of reference on a 1-based line of a template, substituted with the value at [start, end) of the
+// resolved document
+type substitution struct {
+	reference string
+	line      int
+	start     int
+	end       int
+}
+
+// replaces the placeholders of the resolved parameter references in text with the parameter values passed through
+// the transformers listed in the placeholder and escaped by format named formatName, unless skipsFormat, in one pass
+// over the original text, calling onSubstitute, when not nil, for every placeholder substituted.
+// With a comment syntax every line with a substituted value is preceded by a comment naming its parameters.
+// Without a format, a comment syntax and onSubstitute it is replaceParameterPlaceholders.
+func replaceParameterPlaceholdersInFormat(
+	text string,
+	resolvedParametersMap map[string]SsmParameterInfo,
+	format Format,
+	formatName string,
+	syntax *watermarkSyntax,
+	onSubstitute func(substitution)) (string, error) {
+
+	if format == nil && syntax == nil && onSubstitute == nil {
+		return replaceParameterPlaceholders(text, resolvedParametersMap)
+	}
+
+	matches := [][]int{}
+	for _, placeholder := range parameterPlaceholders() {
+		matches = append(matches, placeholder.FindAllStringSubmatchIndex(text, -1)...)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i][0] < matches[j][0] })
+
+	watermarks := []watermark{}
+	if syntax != nil {
+		watermarks = watermarkLines(text, matches, resolvedParametersMap, syntax)
+	}
+
+	buffer := getSubstitutionBuffer()
+	defer putSubstitutionBuffer(buffer)
+
+	last := 0
+	line, lineOffset := 1, 0
+	// writes the text from last to end with the watermarks in between
+	writeUntil := func(end int) {
+		for len(watermarks) > 0 && watermarks[0].offset <= end {
+			if watermarks[0].offset >= last {
+				buffer.WriteString(text[last:watermarks[0].offset])
+				buffer.WriteString(watermarks[0].line)
+				last = watermarks[0].offset
+			}
+			watermarks = watermarks[1:]
+		}
+		buffer.WriteString(text[last:end])
+	}
+
+	for _, match := range matches {
+		ref := text[match[2]:match[3]]
+		param, resolved := resolvedParametersMap[ref]
+		if !resolved {
+			continue
+		}
+
+		modifiers := parsePlaceholderModifiers(text[match[4]:match[5]])
+		value, err := applyTransformers(param.Value, modifiers)
+		if err != nil {
+			return "", withStatus(StatusPolicyViolation, newParameterReferenceError(ref, "cannot transform value of parameter reference {{%s}}: %w", err))
+		}
+
+		if format != nil && !skipsFormat(formatName, modifiers) {
+			value, err = format(text, match[0], value)
+			if err != nil {
+				return "", withStatus(StatusPolicyViolation, newParameterReferenceError(ref, "cannot substitute parameter reference {{%s}}: %w", err))
+			}
+		}
+
+		writeUntil(match[0])
+		start := buffer.Len()
+		buffer.WriteString(value)
+		last = match[1]
+
+		if onSubstitute != nil {
+			line += strings.Count(text[lineOffset:match[0]], "\n")
+			lineOffset = match[0]
+			onSubstitute(substitution{reference: ref, line: line, start: start, end: buffer.Len()})
+		}
+	}
+	writeUntil(len(text))
+
+	return buffer.String(), nil
+}
@@ -0,0 +1,50 @@
+package resolver
+
+import (
+	"errors"
+	"time"
+)
+
+//
+// Freshness a placeholder requires from its value, e.g. {{ssm:/app/token | max-age=5m}}.
+// A value fetched from Parameter Store for the resolve call is always fresh, max-age only limits
+// how old a value served from a cache can be.
+const maxAgeConstraint = "max-age"
+
+// max-age is met when the value is fetched, only its argument is checked
+func checkMaxAge(value string, maxAge string) error {
+	duration, err := time.ParseDuration(maxAge)
+	if err != nil || duration < 0 {
+		return errors.New("invalid max-age " + maxAge + ", expected a duration like 30s or 5m")
+	}
+
+	return nil
+}
+
+// returns the strictest max-age declared for every parameter reference of texts that declares one
+func placeholderMaxAges(texts ...string) map[string]time.Duration {
+	maxAges := map[string]time.Duration{}
+
+	for _, text := range texts {
+		for _, placeholder := range parameterPlaceholders() {
+			for _, match := range placeholder.FindAllStringSubmatch(text, -1) {
+				for _, modifier := range parsePlaceholderModifiers(match[2]) {
+					name, argument, isConstraint := parseConstraintModifier(modifier)
+					if !isConstraint || name != maxAgeConstraint {
+						continue
+					}
+
+					maxAge, err := time.ParseDuration(argument)
+					if err != nil {
+						continue
+					}
+					if current, contains := maxAges[match[1]]; !contains || maxAge < current {
+						maxAges[match[1]] = maxAge
+					}
+				}
+			}
+		}
+	}
+
+	return maxAges
+}
@@ -0,0 +1,30 @@
+package resolver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaceholderMaxAges(t *testing.T) {
+	text := "{{ssm:/app/token | max-age=5m}} {{ ssm:/app/token|max-age=30s }} {{ssm-secure:/app/key | max-age=1h | shellquote}} {{ssm:/app/host}}"
+
+	assert.Equal(t, map[string]time.Duration{
+		"ssm:/app/token":      30 * time.Second,
+		"ssm-secure:/app/key": time.Hour,
+	}, placeholderMaxAges(text))
+}
+
+func TestResolveParametersInTextWithMaxAge(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/token": {Name: "/app/token", Type: stringType, Value: "t0k3n"},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/token | max-age=5m}}", ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "t0k3n", output)
+
+	_, err = ResolveParametersInText(&serviceObject, "{{ssm:/app/token | max-age=soon}}", ResolveOptions{})
+	assert.NotNil(t, err)
+}
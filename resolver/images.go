@@ -0,0 +1,116 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+const ec2ImageDataType = "aws:ec2:image"
+
+//
+// Format of an AMI ID, the only values SSM accepts for parameters of aws:ec2:image data type
+var amiIdFormat = regexp.MustCompile("^ami-([0-9a-f]{8}|[0-9a-f]{17})$")
+
+//
+// Describes EC2 images
+type IEc2ImageService interface {
+	callDescribeImages(ctx context.Context, imageIds []string) (map[string]Ec2ImageInfo, error)
+}
+
+type Ec2ImageInfo struct {
+	ImageId      string
+	Name         string
+	Architecture string
+	Description  string
+}
+
+// checks that the values of aws:ec2:image parameters are AMI IDs
+func validateParameterDataTypes(resolvedParametersMap map[string]SsmParameterInfo) error {
+	for ref, param := range resolvedParametersMap {
+		if param.DataType == ec2ImageDataType && !amiIdFormat.MatchString(param.Value) {
+			return withStatus(StatusPolicyViolation, errors.New("parameter reference {{"+ref+"}} of "+ec2ImageDataType+" data type has value "+
+				param.Value+" which is not an AMI ID"))
+		}
+	}
+
+	return nil
+}
+
+//
+// Takes resolved parameters and describes the images of those of aws:ec2:image data type with EC2 DescribeImages,
+// e.g. to check the architecture of an AMI before templating it into a launch configuration.
+// It will return a map of (parameter reference) to Ec2ImageInfo.
+func DescribeImageParameters(
+	service IEc2ImageService,
+	resolvedParametersMap map[string]SsmParameterInfo) (map[string]Ec2ImageInfo, error) {
+
+	return DescribeImageParametersWithContext(context.Background(), service, resolvedParametersMap)
+}
+
+//
+// Same as DescribeImageParameters, but the EC2 requests are canceled when ctx is done.
+func DescribeImageParametersWithContext(
+	ctx context.Context,
+	service IEc2ImageService,
+	resolvedParametersMap map[string]SsmParameterInfo) (map[string]Ec2ImageInfo, error) {
+
+	imageIds := []string{}
+	for _, param := range resolvedParametersMap {
+		if param.DataType == ec2ImageDataType {
+			imageIds = append(imageIds, param.Value)
+		}
+	}
+
+	result := map[string]Ec2ImageInfo{}
+	if len(imageIds) == 0 {
+		return result, nil
+	}
+
+	images, err := service.callDescribeImages(ctx, dedupSlice(imageIds))
+	if err != nil {
+		return nil, err
+	}
+
+	for ref, param := range resolvedParametersMap {
+		if param.DataType != ec2ImageDataType {
+			continue
+		}
+
+		image, found := images[param.Value]
+		if !found {
+			return nil, errors.New("image " + param.Value + " of parameter reference {{" + ref + "}} does not exist or is not accessible")
+		}
+		result[ref] = image
+	}
+
+	return result, nil
+}
+
+func (s *Service) callDescribeImages(ctx context.Context, imageIds []string) (map[string]Ec2ImageInfo, error) {
+	if s.EC2Client == nil {
+		return nil, errors.New("EC2 client is not configured")
+	}
+
+	output, err := s.EC2Client.DescribeImagesWithContext(ctx, &ec2.DescribeImagesInput{
+		ImageIds: aws.StringSlice(imageIds),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	images := map[string]Ec2ImageInfo{}
+	for _, image := range output.Images {
+		images[aws.StringValue(image.ImageId)] = Ec2ImageInfo{
+			ImageId:      aws.StringValue(image.ImageId),
+			Name:         aws.StringValue(image.Name),
+			Architecture: aws.StringValue(image.Architecture),
+			Description:  aws.StringValue(image.Description),
+		}
+	}
+
+	return images, nil
+}
@@ -0,0 +1,52 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//
+// Mocked EC2 service knowing one image
+type imageServiceMock struct{}
+
+func (m *imageServiceMock) callDescribeImages(ctx context.Context, imageIds []string) (map[string]Ec2ImageInfo, error) {
+	images := map[string]Ec2ImageInfo{}
+	for _, id := range imageIds {
+		if id == "ami-0123456789abcdef0" {
+			images[id] = Ec2ImageInfo{ImageId: id, Name: "al2023", Architecture: "arm64", Description: "Amazon Linux 2023"}
+		}
+	}
+	return images, nil
+}
+
+func TestExtractParametersFromTextValidatesImageIds(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/ami": {Name: "/app/ami", Type: stringType, Value: "ami-0123456789abcdef0", DataType: ec2ImageDataType},
+		"ssm:/app/bad": {Name: "/app/bad", Type: stringType, Value: "ubuntu-22.04", DataType: ec2ImageDataType},
+	})
+
+	_, err := ExtractParametersFromText(&serviceObject, "{{ssm:/app/ami}}", ResolveOptions{})
+	assert.Nil(t, err)
+
+	_, err = ExtractParametersFromText(&serviceObject, "{{ssm:/app/bad}}", ResolveOptions{})
+	assert.NotNil(t, err)
+}
+
+func TestDescribeImageParameters(t *testing.T) {
+	images, err := DescribeImageParameters(&imageServiceMock{}, map[string]SsmParameterInfo{
+		"ssm:/app/ami":  {Name: "/app/ami", Type: stringType, Value: "ami-0123456789abcdef0", DataType: ec2ImageDataType},
+		"ssm:/app/name": {Name: "/app/name", Type: stringType, Value: "web"},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]Ec2ImageInfo{
+		"ssm:/app/ami": {ImageId: "ami-0123456789abcdef0", Name: "al2023", Architecture: "arm64", Description: "Amazon Linux 2023"},
+	}, images)
+
+	_, err = DescribeImageParameters(&imageServiceMock{}, map[string]SsmParameterInfo{
+		"ssm:/app/ami": {Name: "/app/ami", Type: stringType, Value: "ami-00000000", DataType: ec2ImageDataType},
+	})
+	assert.NotNil(t, err)
+}
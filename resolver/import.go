@@ -0,0 +1,169 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+//
+// Writes parameters into SSM Parameter Store
+type ISsmParameterWriter interface {
+	callGetExistingParameters(ctx context.Context, names []string) (map[string]SsmParameterInfo, error)
+	callPutParameter(ctx context.Context, param SsmParameterInfo, overwrite bool, kmsKeyId string) error
+}
+
+//
+// What ImportSnapshot does with a parameter which already exists in Parameter Store
+type OverwritePolicy int
+
+const (
+	// Existing parameters are left untouched
+	OverwriteNever OverwritePolicy = iota
+	// Existing parameters are overwritten when their type or value differ from the snapshot
+	OverwriteIfChanged
+	// Existing parameters are always overwritten, creating a new version
+	OverwriteAlways
+)
+
+type ImportOptions struct {
+	// Compute the ImportResult without writing anything
+	DryRun bool
+
+	OverwritePolicy OverwritePolicy
+
+	// KMS key used for SecureString parameters, the account default key when empty
+	KmsKeyId string
+
+	// The snapshot is an EncryptedSnapshot, its data key is decrypted by the service
+	// which has to implement IKmsDataKeyService (Service does).
+	Encrypted bool
+}
+
+//
+// Names of the parameters ImportSnapshot created, updated or skipped
+type ImportResult struct {
+	Created []string
+	Updated []string
+	Skipped []string
+}
+
+//
+// Reads a Snapshot (or an EncryptedSnapshot) from reader and stores its parameters in Parameter Store
+// according to ImportOptions, e.g. to migrate parameters exported by ExportPath between accounts or regions.
+func ImportSnapshot(
+	service ISsmParameterWriter,
+	reader io.Reader,
+	options ImportOptions) (*ImportResult, error) {
+
+	return ImportSnapshotWithContext(context.Background(), service, reader, options)
+}
+
+//
+// Same as ImportSnapshot, but the SSM and KMS requests are canceled when ctx is done.
+func ImportSnapshotWithContext(
+	ctx context.Context,
+	service ISsmParameterWriter,
+	reader io.Reader,
+	options ImportOptions) (*ImportResult, error) {
+
+	var keyService IKmsDataKeyService
+	if options.Encrypted {
+		var ok bool
+		keyService, ok = service.(IKmsDataKeyService)
+		if !ok {
+			return nil, errors.New("service does not support KMS encryption")
+		}
+	}
+
+	snapshot, err := decodeSnapshot(ctx, reader, keyService)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, param := range snapshot.Parameters {
+		names = append(names, param.Name)
+	}
+
+	existingParameters, err := service.callGetExistingParameters(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Created: []string{}, Updated: []string{}, Skipped: []string{}}
+	for _, param := range snapshot.Parameters {
+		existing, exists := existingParameters[param.Name]
+
+		switch {
+		case !exists:
+			result.Created = append(result.Created, param.Name)
+		case options.OverwritePolicy == OverwriteAlways,
+			options.OverwritePolicy == OverwriteIfChanged && (existing.Type != param.Type || existing.Value != param.Value):
+			result.Updated = append(result.Updated, param.Name)
+		default:
+			result.Skipped = append(result.Skipped, param.Name)
+			continue
+		}
+
+		if options.DryRun {
+			continue
+		}
+
+		err = service.callPutParameter(ctx, param, exists, options.KmsKeyId)
+		if err != nil {
+			return result, fmt.Errorf("cannot import parameter %s: %w", param.Name, err)
+		}
+	}
+
+	return result, nil
+}
+
+//
+// This function returns the parameters among names which exist in Parameter Store, missing ones are not an error.
+func (s *Service) callGetExistingParameters(ctx context.Context, names []string) (map[string]SsmParameterInfo, error) {
+	existingParameters := map[string]SsmParameterInfo{}
+
+	for start := 0; start < len(names); start += maxParametersRetrievedFromSsm {
+		end := start + maxParametersRetrievedFromSsm
+		if end > len(names) {
+			end = len(names)
+		}
+
+		parametersOutput, err := s.SSMClient.GetParametersWithContext(ctx, &ssm.GetParametersInput{
+			Names:          aws.StringSlice(names[start:end]),
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, param := range parametersOutput.Parameters {
+			existingParameters[*param.Name] = newSsmParameterInfo(param)
+		}
+	}
+
+	return existingParameters, nil
+}
+
+func (s *Service) callPutParameter(ctx context.Context, param SsmParameterInfo, overwrite bool, kmsKeyId string) error {
+	input := &ssm.PutParameterInput{
+		Name:      aws.String(param.Name),
+		Type:      aws.String(param.Type),
+		Value:     aws.String(param.Value),
+		Overwrite: aws.Bool(overwrite),
+	}
+	if param.Type == secureStringType && len(kmsKeyId) > 0 {
+		input.KeyId = aws.String(kmsKeyId)
+	}
+	if len(param.DataType) > 0 {
+		input.DataType = aws.String(param.DataType)
+	}
+
+	_, err := s.SSMClient.PutParameterWithContext(ctx, input)
+	return err
+}
@@ -0,0 +1,117 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testImportSnapshot = `{
+  "Parameters": [
+    {"Name": "/app/new", "Type": "String", "Value": "new"},
+    {"Name": "/app/same", "Type": "String", "Value": "same"},
+    {"Name": "/app/changed", "Type": "SecureString", "Value": "rotated"}
+  ]
+}`
+
+func newImportTestService() ServiceMockedObjectWithRecords {
+	return NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/same":           {Name: "/app/same", Type: stringType, Value: "same"},
+		"ssm-secure:/app/changed": {Name: "/app/changed", Type: secureStringType, Value: "original"},
+	})
+}
+
+func TestImportSnapshotOverwritePolicies(t *testing.T) {
+	created := SsmParameterInfo{Name: "/app/new", Type: stringType, Value: "new"}
+	same := SsmParameterInfo{Name: "/app/same", Type: stringType, Value: "same"}
+	changed := SsmParameterInfo{Name: "/app/changed", Type: secureStringType, Value: "rotated"}
+
+	testCases := []struct {
+		policy        OverwritePolicy
+		updated       []string
+		skipped       []string
+		putParameters []SsmParameterInfo
+		putOverwrites []bool
+		changedValue  string
+	}{
+		{OverwriteNever, []string{}, []string{"/app/same", "/app/changed"}, []SsmParameterInfo{created}, []bool{false}, "original"},
+		{OverwriteIfChanged, []string{"/app/changed"}, []string{"/app/same"}, []SsmParameterInfo{created, changed}, []bool{false, true}, "rotated"},
+		{OverwriteAlways, []string{"/app/same", "/app/changed"}, []string{}, []SsmParameterInfo{created, same, changed}, []bool{false, true, true}, "rotated"},
+	}
+
+	for _, testCase := range testCases {
+		serviceObject := newImportTestService()
+
+		result, err := ImportSnapshot(&serviceObject, strings.NewReader(testImportSnapshot), ImportOptions{
+			OverwritePolicy: testCase.policy,
+			KmsKeyId:        "alias/target",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"/app/new"}, result.Created)
+		assert.Equal(t, testCase.updated, result.Updated)
+		assert.Equal(t, testCase.skipped, result.Skipped)
+
+		assert.Equal(t, testCase.putParameters, serviceObject.putParameters)
+		assert.Equal(t, testCase.putOverwrites, serviceObject.putOverwrites)
+		assert.Equal(t, created, serviceObject.records["ssm:/app/new"])
+		assert.Equal(t, testCase.changedValue, serviceObject.records["ssm-secure:/app/changed"].Value)
+		assert.Equal(t, secureStringType, serviceObject.records["ssm-secure:/app/changed"].Type)
+	}
+}
+
+func TestImportSnapshotDryRun(t *testing.T) {
+	serviceObject := newImportTestService()
+
+	result, err := ImportSnapshot(&serviceObject, strings.NewReader(testImportSnapshot), ImportOptions{
+		DryRun:          true,
+		OverwritePolicy: OverwriteAlways,
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, result.Created, 1)
+	assert.Len(t, result.Updated, 2)
+	assert.Empty(t, serviceObject.putParameters)
+}
+
+func TestImportSnapshotKmsKeyForSecureStrings(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+
+	_, err := ImportSnapshot(&serviceObject, strings.NewReader(testImportSnapshot), ImportOptions{
+		KmsKeyId: "alias/target",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"alias/target", "alias/target", "alias/target"}, serviceObject.putKmsKeyIds)
+	assert.Equal(t, "rotated", serviceObject.records["ssm-secure:/app/changed"].Value)
+}
+
+func TestImportEncryptedSnapshot(t *testing.T) {
+	source := newExportTestService()
+
+	var buffer bytes.Buffer
+	assert.Nil(t, ExportPath(&source, "/app/prod", &buffer, ExportOptions{Recursive: true, KmsKeyId: "alias/snapshots"}))
+
+	target := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+	result, err := ImportSnapshot(&target, &buffer, ImportOptions{Encrypted: true})
+
+	assert.Nil(t, err)
+	assert.Len(t, result.Created, 3)
+	assert.Equal(t, "s3cr3t", target.records["ssm-secure:/app/prod/password"].Value)
+}
+
+func TestImportSnapshotWithCanceledContext(t *testing.T) {
+	serviceObject := newImportTestService()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := ImportSnapshotWithContext(ctx, &serviceObject, strings.NewReader(testImportSnapshot), ImportOptions{})
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, []string{"/app/new"}, result.Created)
+	assert.Empty(t, serviceObject.putParameters)
+}
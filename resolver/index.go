@@ -0,0 +1,182 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//
+// Placeholder of a parameter found by BuildIndex
+type IndexLocation struct {
+	File string
+
+	// 1-based line number
+	Line int
+
+	// Parameter reference of the placeholder, e.g. ssm:/app/legacy/flag
+	Reference string
+}
+
+//
+// ParameterIndex tells which files and lines of a corpus of templates reference every parameter, e.g. to find
+// what breaks when a parameter is deleted. It is built by BuildIndex, saved with Save and loaded with LoadIndex.
+type ParameterIndex struct {
+	// Locations of the placeholders of every parameter keyed by parameter name, ordered by file and line
+	Parameters map[string][]IndexLocation
+
+	// Names of the indexed files, sorted
+	Files []string
+}
+
+//
+// Indexes the placeholders of the files of fsys matching one of patterns (see path.Match). A pattern without
+// a slash is matched against the base name of the files in any directory, e.g. "*.conf", any other pattern
+// against the whole slash separated path, e.g. "etc/*/app.conf". Every file is indexed when no pattern is given.
+func BuildIndex(fsys fs.FS, patterns ...string) (*ParameterIndex, error) {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+	}
+
+	index := &ParameterIndex{
+		Parameters: map[string][]IndexLocation{},
+		Files:      []string{},
+	}
+
+	err := fs.WalkDir(fsys, ".", func(fileName string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || !matchesIndexPatterns(fileName, patterns) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > MaxFileSizeInBytes {
+			return errors.New("file " + fileName + " is too large")
+		}
+
+		content, err := fs.ReadFile(fsys, fileName)
+		if err != nil {
+			return err
+		}
+
+		index.add(fileName, string(content))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+func matchesIndexPatterns(fileName string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		name := fileName
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(fileName)
+		}
+
+		// patterns are validated by BuildIndex
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// indexes the placeholders of text, files are walked in lexical order so locations stay ordered
+func (i *ParameterIndex) add(fileName string, text string) {
+	i.Files = append(i.Files, fileName)
+
+	for lineIndex, line := range strings.Split(text, "\n") {
+		for _, placeholder := range parameterPlaceholders() {
+			for _, match := range placeholder.FindAllStringSubmatch(line, -1) {
+				name, _, _ := splitParameterSelector(extractParameterNameFromReference(match[1]))
+				i.Parameters[name] = append(i.Parameters[name], IndexLocation{
+					File:      fileName,
+					Line:      lineIndex + 1,
+					Reference: match[1],
+				})
+			}
+		}
+	}
+}
+
+//
+// Returns the locations of the placeholders referencing the parameter named name, e.g. /app/legacy/flag,
+// ordered by file and line. It returns an empty slice when no template references the parameter.
+func (i *ParameterIndex) Locations(name string) []IndexLocation {
+	locations := append([]IndexLocation{}, i.Parameters[name]...)
+	sort.SliceStable(locations, func(a, b int) bool {
+		if locations[a].File != locations[b].File {
+			return locations[a].File < locations[b].File
+		}
+		return locations[a].Line < locations[b].Line
+	})
+
+	return locations
+}
+
+//
+// Returns the names of the referenced parameters, sorted.
+func (i *ParameterIndex) Names() []string {
+	names := make([]string, 0, len(i.Parameters))
+	for name := range i.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+//
+// Saves the index as JSON to fileName.
+func (i *ParameterIndex) Save(fileName string) error {
+	if len(fileName) == 0 {
+		return errors.New("file name is not provided")
+	}
+
+	encoded, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeToFile(string(encoded), fileName)
+}
+
+//
+// Loads an index saved with ParameterIndex.Save.
+func LoadIndex(fileName string) (*ParameterIndex, error) {
+	text, err := readValidatedTextFromFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var index ParameterIndex
+	if err := json.Unmarshal([]byte(text), &index); err != nil {
+		return nil, fmt.Errorf("index file %s is not a valid JSON: %w", fileName, err)
+	}
+
+	if index.Parameters == nil {
+		index.Parameters = map[string][]IndexLocation{}
+	}
+
+	return &index, nil
+}
@@ -0,0 +1,59 @@
+package resolver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildIndex(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/app.conf":       {Data: []byte("flag={{ssm:/app/legacy/flag}}\nhost={{ssm:/app/host}}")},
+		"app/secrets.conf":   {Data: []byte("\n\npassword={{ssm-secure:/app/legacy/flag}}")},
+		"db/db.conf":         {Data: []byte("host={{ ssm:/app/host | trim }}")},
+		"app/README.md":      {Data: []byte("{{ssm:/app/legacy/flag}}")},
+		"templates/app.tmpl": {Data: []byte("{{ssm:/app/port}}")},
+	}
+
+	index, err := BuildIndex(fsys, "*.conf", "templates/*.tmpl")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"app/app.conf", "app/secrets.conf", "db/db.conf", "templates/app.tmpl"}, index.Files)
+	assert.Equal(t, []string{"/app/host", "/app/legacy/flag", "/app/port"}, index.Names())
+	assert.Equal(t, []IndexLocation{
+		{File: "app/app.conf", Line: 1, Reference: "ssm:/app/legacy/flag"},
+		{File: "app/secrets.conf", Line: 3, Reference: "ssm-secure:/app/legacy/flag"},
+	}, index.Locations("/app/legacy/flag"))
+	assert.Equal(t, []IndexLocation{
+		{File: "app/app.conf", Line: 2, Reference: "ssm:/app/host"},
+		{File: "db/db.conf", Line: 1, Reference: "ssm:/app/host"},
+	}, index.Locations("/app/host"))
+	assert.Empty(t, index.Locations("/app/unused"))
+}
+
+func TestBuildIndexInvalidPattern(t *testing.T) {
+	_, err := BuildIndex(fstest.MapFS{}, "[")
+	assert.NotNil(t, err)
+}
+
+func TestSaveAndLoadIndex(t *testing.T) {
+	index, err := BuildIndex(fstest.MapFS{
+		"app.conf": {Data: []byte("{{ssm:/app/host}}")},
+	})
+	assert.Nil(t, err)
+
+	dir, err := ioutil.TempDir("", "index")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "index.json")
+	assert.Nil(t, index.Save(fileName))
+
+	loaded, err := LoadIndex(fileName)
+	assert.Nil(t, err)
+	assert.Equal(t, index, loaded)
+}
@@ -0,0 +1,196 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+)
+
+//
+// Layout of the parameter tree under the inventory path: <path>/hosts/<host>/<variable> holds host variables,
+// <path>/groups/<group>/hosts the comma separated hosts of the group (String or StringList) and
+// <path>/groups/<group>/vars/<variable> group variables
+const inventoryHostsNode = "hosts"
+const inventoryGroupsNode = "groups"
+const inventoryGroupVarsNode = "vars"
+
+//
+// Ansible group of hosts not listed in any group
+const ungroupedInventoryGroup = "ungrouped"
+
+type InventoryOptions struct {
+	// Leave SecureString parameters out of the inventory
+	ExcludeSecureParameters bool
+}
+
+//
+// Ansible group of an Inventory
+type InventoryGroup struct {
+	Hosts []string          `json:"hosts"`
+	Vars  map[string]string `json:"vars"`
+}
+
+//
+// Hosts, groups and variables read from a parameter tree by BuildInventory
+type Inventory struct {
+	Groups   map[string]*InventoryGroup
+	HostVars map[string]map[string]string
+}
+
+//
+// Reads the parameter tree under path (see inventoryHostsNode and inventoryGroupsNode for the layout) into an Inventory.
+func BuildInventory(
+	service ISsmParameterService,
+	path string,
+	options InventoryOptions) (*Inventory, error) {
+
+	return BuildInventoryWithContext(context.Background(), service, path, options)
+}
+
+//
+// Same as BuildInventory, but the SSM requests are canceled when ctx is done.
+func BuildInventoryWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	path string,
+	options InventoryOptions) (*Inventory, error) {
+
+	if len(path) == 0 {
+		return nil, errors.New("path is not provided")
+	}
+
+	parameters, err := service.callGetParametersByPath(ctx, path, true)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &Inventory{
+		Groups:   map[string]*InventoryGroup{},
+		HostVars: map[string]map[string]string{},
+	}
+	group := func(name string) *InventoryGroup {
+		if _, contains := inventory.Groups[name]; !contains {
+			inventory.Groups[name] = &InventoryGroup{Hosts: []string{}, Vars: map[string]string{}}
+		}
+		return inventory.Groups[name]
+	}
+
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	for _, param := range parameters {
+		if options.ExcludeSecureParameters && param.Type == secureStringType {
+			continue
+		}
+
+		nodes := strings.SplitN(strings.TrimPrefix(param.Name, prefix), "/", 3)
+		switch {
+		case len(nodes) == 3 && nodes[0] == inventoryHostsNode:
+			if _, contains := inventory.HostVars[nodes[1]]; !contains {
+				inventory.HostVars[nodes[1]] = map[string]string{}
+			}
+			inventory.HostVars[nodes[1]][nodes[2]] = param.Value
+
+		case len(nodes) == 3 && nodes[0] == inventoryGroupsNode && nodes[2] == inventoryHostsNode:
+			for _, host := range strings.Split(param.Value, ",") {
+				if host = strings.TrimSpace(host); len(host) > 0 {
+					group(nodes[1]).Hosts = append(group(nodes[1]).Hosts, host)
+				}
+			}
+
+		case len(nodes) == 3 && nodes[0] == inventoryGroupsNode && strings.HasPrefix(nodes[2], inventoryGroupVarsNode+"/"):
+			group(nodes[1]).Vars[strings.TrimPrefix(nodes[2], inventoryGroupVarsNode+"/")] = param.Value
+		}
+	}
+
+	grouped := map[string]bool{}
+	for _, g := range inventory.Groups {
+		sort.Strings(g.Hosts)
+		for _, host := range g.Hosts {
+			grouped[host] = true
+		}
+	}
+	for host := range inventory.HostVars {
+		if !grouped[host] {
+			group(ungroupedInventoryGroup).Hosts = append(group(ungroupedInventoryGroup).Hosts, host)
+		}
+	}
+	if ungrouped, contains := inventory.Groups[ungroupedInventoryGroup]; contains {
+		sort.Strings(ungrouped.Hosts)
+	}
+
+	return inventory, nil
+}
+
+//
+// Returns the variables of host: the variables of all its groups (in group name order) overridden by its own variables.
+// This is the pillar of a Salt minion.
+func (inventory *Inventory) MergedHostVars(host string) map[string]string {
+	groupNames := []string{}
+	for name := range inventory.Groups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+
+	vars := map[string]string{}
+	for _, name := range groupNames {
+		if containsString(inventory.Groups[name].Hosts, host) {
+			for key, value := range inventory.Groups[name].Vars {
+				vars[key] = value
+			}
+		}
+	}
+	for key, value := range inventory.HostVars[host] {
+		vars[key] = value
+	}
+
+	return vars
+}
+
+//
+// Implements the dynamic inventory script protocol of Ansible and the cmd_json external pillar of Salt
+// for the parameter tree under path, writing JSON to writer. args are the arguments of the script:
+// "--list" writes all groups with their hosts and variables and the variables of all hosts in _meta.hostvars,
+// "--host <host>" the variables of host and "--pillar <minion>" the variables of minion merged with its groups'.
+func RunInventory(
+	service ISsmParameterService,
+	path string,
+	args []string,
+	writer io.Writer,
+	options InventoryOptions) error {
+
+	inventory, err := BuildInventory(service, path, options)
+	if err != nil {
+		return err
+	}
+
+	var document interface{}
+	switch {
+	case len(args) == 1 && args[0] == "--list":
+		list := map[string]interface{}{
+			"_meta": map[string]interface{}{"hostvars": inventory.HostVars},
+		}
+		for name, group := range inventory.Groups {
+			list[name] = group
+		}
+		document = list
+
+	case len(args) == 2 && args[0] == "--host":
+		hostVars := inventory.HostVars[args[1]]
+		if hostVars == nil {
+			hostVars = map[string]string{}
+		}
+		document = hostVars
+
+	case len(args) == 2 && args[0] == "--pillar":
+		document = inventory.MergedHostVars(args[1])
+
+	default:
+		return errors.New("usage: --list | --host <host> | --pillar <minion>")
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
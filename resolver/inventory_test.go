@@ -0,0 +1,63 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newInventoryTestService() ServiceMockedObjectWithRecords {
+	return NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/inventory/groups/web/hosts":           {Name: "/inventory/groups/web/hosts", Type: "StringList", Value: "web2,web1"},
+		"ssm:/inventory/groups/web/vars/http_port":  {Name: "/inventory/groups/web/vars/http_port", Type: stringType, Value: "80"},
+		"ssm:/inventory/hosts/web1/http_port":       {Name: "/inventory/hosts/web1/http_port", Type: stringType, Value: "8080"},
+		"ssm:/inventory/hosts/db1/role":             {Name: "/inventory/hosts/db1/role", Type: stringType, Value: "primary"},
+		"ssm-secure:/inventory/hosts/db1/root_pass": {Name: "/inventory/hosts/db1/root_pass", Type: secureStringType, Value: "s3cr3t"},
+	})
+}
+
+func TestRunInventoryList(t *testing.T) {
+	serviceObject := newInventoryTestService()
+
+	var buffer bytes.Buffer
+	err := RunInventory(&serviceObject, "/inventory", []string{"--list"}, &buffer, InventoryOptions{ExcludeSecureParameters: true})
+	assert.Nil(t, err)
+
+	var list map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &list))
+	assert.Equal(t, map[string]interface{}{
+		"_meta": map[string]interface{}{
+			"hostvars": map[string]interface{}{
+				"web1": map[string]interface{}{"http_port": "8080"},
+				"db1":  map[string]interface{}{"role": "primary"},
+			},
+		},
+		"web": map[string]interface{}{
+			"hosts": []interface{}{"web1", "web2"},
+			"vars":  map[string]interface{}{"http_port": "80"},
+		},
+		"ungrouped": map[string]interface{}{
+			"hosts": []interface{}{"db1"},
+			"vars":  map[string]interface{}{},
+		},
+	}, list)
+}
+
+func TestRunInventoryHostAndPillar(t *testing.T) {
+	serviceObject := newInventoryTestService()
+
+	var buffer bytes.Buffer
+	err := RunInventory(&serviceObject, "/inventory/", []string{"--host", "db1"}, &buffer, InventoryOptions{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"role": "primary", "root_pass": "s3cr3t"}`, buffer.String())
+
+	buffer.Reset()
+	err = RunInventory(&serviceObject, "/inventory", []string{"--pillar", "web2"}, &buffer, InventoryOptions{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"http_port": "80"}`, buffer.String())
+
+	err = RunInventory(&serviceObject, "/inventory", []string{"--hosts"}, &buffer, InventoryOptions{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,148 @@
+package resolver
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// rewrites the commented JSON and JSON5 conveniences of input into strict JSON: // and /* */ comments are removed,
+// trailing commas dropped, 'single quoted' strings double quoted and unquoted object keys quoted.
+// Placeholders are kept as they are, so a template can be normalized before it is resolved.
+// Strict JSON is returned unchanged.
+func normalizeJSON5(input string) (string, error) {
+	var output strings.Builder
+	output.Grow(len(input))
+
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case strings.HasPrefix(input[i:], "//"):
+			end := strings.IndexByte(input[i:], '\n')
+			if end < 0 {
+				return output.String(), nil
+			}
+			i += end - 1
+
+		case strings.HasPrefix(input[i:], "/*"):
+			end := strings.Index(input[i+2:], "*/")
+			if end < 0 {
+				return "", errors.New("unterminated /* comment")
+			}
+			output.WriteByte(' ')
+			i += end + 3
+
+		case strings.HasPrefix(input[i:], "{{"):
+			end := strings.Index(input[i:], "}}")
+			if end < 0 {
+				output.WriteString(input[i:])
+				return output.String(), nil
+			}
+			output.WriteString(input[i : i+end+2])
+			i += end + 1
+
+		case c == '"' || c == '\'':
+			end, err := json5StringEnd(input, i)
+			if err != nil {
+				return "", err
+			}
+			if c == '"' {
+				output.WriteString(input[i : end+1])
+			} else {
+				output.WriteString(singleToDoubleQuoted(input[i+1 : end]))
+			}
+			i = end
+
+		case c == ',':
+			if next := json5NextToken(input, i+1); next < len(input) && (input[next] == '}' || input[next] == ']') {
+				continue
+			}
+			output.WriteByte(c)
+
+		case isJSON5IdentifierStart(c):
+			end := i + 1
+			for end < len(input) && (isJSON5IdentifierStart(input[end]) || (input[end] >= '0' && input[end] <= '9')) {
+				end++
+			}
+			if next := json5NextToken(input, end); next < len(input) && input[next] == ':' {
+				output.WriteString("\"" + input[i:end] + "\"")
+			} else {
+				output.WriteString(input[i:end])
+			}
+			i = end - 1
+
+		default:
+			output.WriteByte(c)
+		}
+	}
+
+	return output.String(), nil
+}
+
+// returns the index of the quote closing the string starting at start
+func json5StringEnd(input string, start int) (int, error) {
+	quote := input[start]
+	for i := start + 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			i++
+		case quote:
+			return i, nil
+		}
+	}
+
+	return 0, errors.New("unterminated string starting at offset " + strconv.Itoa(start))
+}
+
+// returns the index of the first character after offset which is neither whitespace nor part of a comment
+func json5NextToken(input string, offset int) int {
+	for offset < len(input) {
+		switch {
+		case strings.ContainsRune(" \t\r\n", rune(input[offset])):
+			offset++
+		case strings.HasPrefix(input[offset:], "//"):
+			end := strings.IndexByte(input[offset:], '\n')
+			if end < 0 {
+				return len(input)
+			}
+			offset += end
+		case strings.HasPrefix(input[offset:], "/*"):
+			end := strings.Index(input[offset+2:], "*/")
+			if end < 0 {
+				return len(input)
+			}
+			offset += end + 4
+		default:
+			return offset
+		}
+	}
+
+	return offset
+}
+
+// converts the content of a 'single quoted' string into a "double quoted" one
+func singleToDoubleQuoted(content string) string {
+	var output strings.Builder
+	output.WriteByte('"')
+	for i := 0; i < len(content); i++ {
+		switch {
+		case content[i] == '\\' && i+1 < len(content) && content[i+1] == '\'':
+			output.WriteByte('\'')
+			i++
+		case content[i] == '\\' && i+1 < len(content):
+			output.WriteString(content[i : i+2])
+			i++
+		case content[i] == '"':
+			output.WriteString("\\\"")
+		default:
+			output.WriteByte(content[i])
+		}
+	}
+	output.WriteByte('"')
+
+	return output.String()
+}
+
+func isJSON5IdentifierStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,61 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeJSON5(t *testing.T) {
+	input := "{\n" +
+		"  // database settings\n" +
+		"  host: 'db.internal', /* primary */\n" +
+		"  \"url\": \"http://{{ssm:/app/host}}\",\n" +
+		"  port: {{ssm:/app/port}},\n" +
+		"  quoted: 'it\\'s \"fine\"',\n" +
+		"  list: [1, 2, ],\n" +
+		"}"
+
+	output, err := normalizeJSON5(input)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "{\n"+
+		"  \n"+
+		"  \"host\": \"db.internal\",  \n"+
+		"  \"url\": \"http://{{ssm:/app/host}}\",\n"+
+		"  \"port\": {{ssm:/app/port}},\n"+
+		"  \"quoted\": \"it's \\\"fine\\\"\",\n"+
+		"  \"list\": [1, 2 ]\n"+
+		"}", output)
+}
+
+func TestNormalizeJSON5StrictJSONUnchanged(t *testing.T) {
+	input := "{\"a\": [true, null, \"// not a comment\"], \"b\": {\"c\": 1.5}}"
+
+	output, err := normalizeJSON5(input)
+
+	assert.Nil(t, err)
+	assert.Equal(t, input, output)
+}
+
+func TestNormalizeJSON5Unterminated(t *testing.T) {
+	_, err := normalizeJSON5("{\"a\": 1 /* comment")
+	assert.NotNil(t, err)
+
+	_, err = normalizeJSON5("{\"a\": 'text}")
+	assert.NotNil(t, err)
+}
+
+func TestResolveParametersWithOverlaysCommentedJSON(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	result, err := ResolveParametersWithOverlays(&serviceObject,
+		"{\n  // defaults\n  host: '{{ssm:/app/host}}',\n  port: 5432,\n}",
+		map[string]string{"prod": "{port: 6432, /* {{ssm:/not/fetched}} */}"},
+		ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, "{\"host\": \"db.internal\", \"port\": 6432}", result["prod"])
+}
@@ -0,0 +1,116 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const jsonFormat = "json"
+
+// escapes value for the JSON string around offset. Outside of strings only JSON values, e.g. numbers, booleans,
+// null or a whole object, can be substituted, to keep the document valid JSON.
+func escapeJsonValue(template string, offset int, value string) (string, error) {
+	if jsonInStringAt(template, offset) {
+		return escapeJsonString(value), nil
+	}
+
+	if !json.Valid([]byte(value)) {
+		return "", errors.New("value is not a JSON number, boolean, null, object or array, put the placeholder inside a \"string\"")
+	}
+
+	return value, nil
+}
+
+// reports whether offset is inside a JSON string scanning template from the start. Placeholders are skipped so that
+// characters of their modifiers are not taken for JSON syntax.
+func jsonInStringAt(template string, offset int) bool {
+	inString := false
+	for i := 0; i < offset; i++ {
+		if strings.HasPrefix(template[i:], "{{") {
+			if end := strings.Index(template[i:], "}}"); end > 0 && i+end < offset {
+				i += end + 1
+				continue
+			}
+		}
+
+		switch {
+		case inString && template[i] == '\\':
+			i++
+		case template[i] == '"':
+			inString = !inString
+		}
+	}
+
+	return inString
+}
+
+// escapes backslashes, quotes and control characters. HTML characters are left as they are, unlike json.Marshal.
+func escapeJsonString(value string) string {
+	var escaped strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '\\':
+			escaped.WriteString("\\\\")
+		case r == '"':
+			escaped.WriteString("\\\"")
+		case r == '\n':
+			escaped.WriteString("\\n")
+		case r == '\r':
+			escaped.WriteString("\\r")
+		case r == '\t':
+			escaped.WriteString("\\t")
+		case r < 0x20:
+			escaped.WriteString(fmt.Sprintf("\\u%04x", r))
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+
+	return escaped.String()
+}
+
+//
+// Takes a JSON document and resolves SSM parameters according to ResolveOptions with the json format, whatever
+// ResolveOptions.Format says: values substituted inside strings are JSON-escaped, and values substituted outside
+// of strings have to be JSON values themselves. The resolved document is validated to parse as JSON.
+//
+// Deprecated: use Resolver.ResolveJSON of github.com/parameterResolver/resolver/v2.
+func ResolveParametersInJSON(
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, error) {
+
+	return ResolveParametersInJSONWithContext(context.Background(), service, input, options)
+}
+
+//
+// Same as ResolveParametersInJSON, but the SSM requests are canceled when ctx is done.
+//
+// Deprecated: use Resolver.ResolveJSON of github.com/parameterResolver/resolver/v2.
+func ResolveParametersInJSONWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, error) {
+
+	options.Format = jsonFormat
+	resolved, err := ResolveParametersInTextWithContext(ctx, service, input, options)
+	if err != nil {
+		return "", err
+	}
+
+	var document interface{}
+	if err := json.Unmarshal([]byte(resolved), &document); err != nil {
+		var syntaxError *json.SyntaxError
+		if errors.As(err, &syntaxError) {
+			line := strings.Count(resolved[:syntaxError.Offset], "\n") + 1
+			err = fmt.Errorf("line %d: %w", line, err)
+		}
+		return "", withStatus(StatusParseError, fmt.Errorf("resolved document is not valid JSON: %w", err))
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,107 @@
+package resolver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeJsonValue(t *testing.T) {
+	testCases := []struct {
+		template string
+		value    string
+		expected string
+		valid    bool
+	}{
+		{`{"host": "{{ssm:x}}"}`, `example.com`, `example.com`, true},
+		{`{"password": "{{ssm:x}}"}`, `p"a\ss`, `p\"a\\ss`, true},
+		{`{"key": "{{ssm:x}}"}`, "line1\nline2\t\x01", `line1\nline2\t\u0001`, true},
+		{`{"url": "<a href='{{ssm:x}}'>"}`, `a&b`, `a&b`, true},
+		{`{"a": "\"", "b": "{{ssm:x}}"}`, `"`, `\"`, true},
+		{`{"a": "{{ssm:y | default "}"}}", "b": "{{ssm:x}}"}`, `"`, `\"`, true},
+		{`{"port": {{ssm:x}}}`, `8080`, `8080`, true},
+		{`{"enabled": {{ssm:x}}}`, `true`, `true`, true},
+		{`{"tags": {{ssm:x}}}`, `["a", "b"]`, `["a", "b"]`, true},
+		{`{"a": "\"", "port": {{ssm:x}}}`, `8080`, `8080`, true},
+		{`{"host": {{ssm:x}}}`, `example.com`, ``, false},
+		{`{"port": {{ssm:x}}}`, `80 80`, ``, false},
+	}
+
+	for _, testCase := range testCases {
+		offset := strings.LastIndex(testCase.template, "{{ssm:x}}")
+		escaped, err := escapeJsonValue(testCase.template, offset, testCase.value)
+		assert.Equal(t, testCase.valid, err == nil, testCase.template)
+		assert.Equal(t, testCase.expected, escaped, testCase.template)
+	}
+}
+
+func TestResolveParametersInJSON(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/port":           {Name: "/app/port", Type: stringType, Value: "5432"},
+		"ssm-secure:/app/db/pass": {Name: "/app/db/pass", Type: secureStringType, Value: "p\"a\\ss\n"},
+		"ssm:/app/host":           {Name: "/app/host", Type: stringType, Value: "db.example.com"},
+	})
+
+	resolved, err := ResolveParametersInJSON(&serviceObject,
+		`{"db": {"host": "{{ssm:/app/host}}", "port": {{ssm:/app/port}}, "password": "{{ssm-secure:/app/db/pass}}"}}`,
+		ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{"db": {"host": "db.example.com", "port": 5432, "password": "p\"a\\ss\n"}}`, resolved)
+
+	_, err = ResolveParametersInJSON(&serviceObject, `{"host": {{ssm:/app/host}}}`, ResolveOptions{})
+	assert.NotNil(t, err)
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+
+	_, err = ResolveParametersInJSON(&serviceObject, "{\n\"port\": {{ssm:/app/port}},\n}", ResolveOptions{})
+	assert.NotNil(t, err)
+	assert.Equal(t, StatusParseError, StatusOf(err))
+	assert.Contains(t, err.Error(), "line 3")
+}
+
+func TestResolveParametersInTextWithJsonFormat(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/banner": {Name: "/app/banner", Type: stringType, Value: "say \"hi\""},
+	})
+
+	resolved, err := ResolveParametersInText(&serviceObject, `{"banner": "> {{ssm:/app/banner}}"}`,
+		ResolveOptions{Format: jsonFormat})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{"banner": "> say \"hi\""}`, resolved)
+}
+
+func TestResolveParametersInJSONWithOneLineTransformer(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/cert": {Name: "/app/cert", Type: stringType, Value: "line1\nline2"},
+	})
+
+	resolved, err := ResolveParametersInJSON(&serviceObject, `{"cert": "{{ssm:/app/cert | oneline}}"}`, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{"cert": "line1\nline2"}`, resolved)
+}
+
+func TestResolveParametersInJsonFileWithoutFormat(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/banner": {Name: "/app/banner", Type: stringType, Value: `say \"hi\"`},
+	})
+
+	dir, err := ioutil.TempDir("", "jsonFormat")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "input")
+	outputFileName := filepath.Join(dir, "app.json")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte(`{"banner": "{{ssm:/app/banner}}"}`), 0644))
+
+	assert.Nil(t, ResolveParametersInFile(&serviceObject, inputFileName, outputFileName, ResolveOptions{}))
+
+	output, err := ioutil.ReadFile(outputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"banner": "say \"hi\""}`, string(output))
+}
@@ -0,0 +1,115 @@
+package resolver
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+//
+// Data keys used to encrypt parameter snapshots (envelope encryption)
+type IKmsDataKeyService interface {
+	generateDataKey(ctx context.Context, keyId string) (plaintextKey []byte, encryptedKey []byte, err error)
+	decryptDataKey(ctx context.Context, encryptedKey []byte) ([]byte, error)
+}
+
+//
+// Snapshot encrypted with an AES-256-GCM data key, which is itself encrypted with a KMS key.
+type EncryptedSnapshot struct {
+	KmsKeyId         string
+	EncryptedDataKey []byte
+	Nonce            []byte
+	Ciphertext       []byte
+}
+
+func (s *Service) generateDataKey(ctx context.Context, keyId string) ([]byte, []byte, error) {
+	if s.KMSClient == nil {
+		return nil, nil, errors.New("KMS client is not configured")
+	}
+
+	output, err := s.KMSClient.GenerateDataKeyWithContext(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(keyId),
+		KeySpec: aws.String(kms.DataKeySpecAes256),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return output.Plaintext, output.CiphertextBlob, nil
+}
+
+func (s *Service) decryptDataKey(ctx context.Context, encryptedKey []byte) ([]byte, error) {
+	if s.KMSClient == nil {
+		return nil, errors.New("KMS client is not configured")
+	}
+
+	output, err := s.KMSClient.DecryptWithContext(ctx, &kms.DecryptInput{
+		CiphertextBlob: encryptedKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Plaintext, nil
+}
+
+// encrypts plaintext with a new data key generated under the KMS key keyId
+func encryptWithDataKey(ctx context.Context, keyService IKmsDataKeyService, keyId string, plaintext []byte) (*EncryptedSnapshot, error) {
+	dataKey, encryptedDataKey, err := keyService.generateDataKey(ctx, keyId)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newDataKeyCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return &EncryptedSnapshot{
+		KmsKeyId:         keyId,
+		EncryptedDataKey: encryptedDataKey,
+		Nonce:            nonce,
+		Ciphertext:       aead.Seal(nil, nonce, plaintext, nil),
+	}, nil
+}
+
+// decrypts the content of an encrypted snapshot
+func decryptWithDataKey(ctx context.Context, keyService IKmsDataKeyService, encrypted *EncryptedSnapshot) ([]byte, error) {
+	dataKey, err := keyService.decryptDataKey(ctx, encrypted.EncryptedDataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newDataKeyCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, encrypted.Nonce, encrypted.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt snapshot: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func newDataKeyCipher(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
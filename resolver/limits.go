@@ -0,0 +1,87 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+//
+// Guardrails for templates that are not trusted, so that a hostile or broken template cannot exhaust
+// the process resolving it. Zero fields do not limit anything.
+type ResolveLimits struct {
+	// Time spent parsing and validating the placeholders of one document, checked after every step
+	MaxParseTime time.Duration
+
+	// Number of placeholders in one document or parameter value, repeated placeholders included
+	MaxPlaceholders int
+
+	// Size of a resolved document or nested parameter value relative to the size of its sources: the template,
+	// the documents it includes and the values of the parameters it references. Bounds the growth of values
+	// nesting placeholders in recursive mode and of documents including other documents.
+	MaxExpansionFactor float64
+}
+
+//
+// Wrapped by the errors of the resolve calls exceeding ResolveOptions.Limits
+var ErrLimitExceeded = errors.New("resolve limit exceeded")
+
+// fails when text has more placeholders than allowed
+func checkPlaceholderCount(limits ResolveLimits, text string) error {
+	return checkPlaceholderTotal(limits, countPlaceholders(limits, text))
+}
+
+// returns the number of placeholders in text, counting at most one more than allowed, 0 when not limited
+func countPlaceholders(limits ResolveLimits, text string) int {
+	if limits.MaxPlaceholders <= 0 {
+		return 0
+	}
+
+	count := 0
+	for _, placeholder := range parameterPlaceholders() {
+		count += len(placeholder.FindAllStringIndex(text, limits.MaxPlaceholders+1))
+	}
+
+	return count
+}
+
+// fails when count placeholders are more than allowed, e.g. for a document parsed in parts
+func checkPlaceholderTotal(limits ResolveLimits, count int) error {
+	if limits.MaxPlaceholders > 0 && count > limits.MaxPlaceholders {
+		return withStatus(StatusPolicyViolation, fmt.Errorf("%w: more than %d placeholders", ErrLimitExceeded, limits.MaxPlaceholders))
+	}
+
+	return nil
+}
+
+// fails when parsing started at start took longer than allowed
+func checkParseTime(limits ResolveLimits, start time.Time) error {
+	return checkParseDuration(limits, time.Since(start))
+}
+
+// fails when parsing took elapsed, longer than allowed, e.g. for a document parsed in parts
+func checkParseDuration(limits ResolveLimits, elapsed time.Duration) error {
+	if limits.MaxParseTime > 0 && elapsed > limits.MaxParseTime {
+		return withStatus(StatusPolicyViolation, fmt.Errorf("%w: parsing took %s, more than %s", ErrLimitExceeded, elapsed, limits.MaxParseTime))
+	}
+
+	return nil
+}
+
+// returns the largest size allowed for a text resolved from sources of sourceSize bytes, 0 when not limited
+func maxExpandedSize(limits ResolveLimits, sourceSize int) int {
+	if limits.MaxExpansionFactor <= 0 {
+		return 0
+	}
+
+	return int(limits.MaxExpansionFactor * float64(sourceSize))
+}
+
+// fails when a text of size bytes is larger than maxSize, unless maxSize is 0
+func checkExpandedSize(maxSize int, size int, what string) error {
+	if maxSize > 0 && size > maxSize {
+		return withStatus(StatusPolicyViolation, fmt.Errorf("%w: %s expands to %d bytes, more than %d", ErrLimitExceeded, what, size, maxSize))
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitsMaxPlaceholders(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+	})
+	options := ResolveOptions{Limits: ResolveLimits{MaxPlaceholders: 3}}
+
+	_, err := ResolveParametersInText(&serviceObject, strings.Repeat("{{ssm:/app/host}}", 3), options)
+	assert.Nil(t, err)
+
+	_, err = ResolveParametersInText(&serviceObject, strings.Repeat("{{ssm:/app/host}}", 4), options)
+	assert.True(t, errors.Is(err, ErrLimitExceeded))
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+}
+
+func TestLimitsMaxExpansionFactorRecursive(t *testing.T) {
+	// every level doubles the value: 2^5 copies of the innermost value
+	records := map[string]SsmParameterInfo{
+		"ssm:/app/l5": {Name: "/app/l5", Type: stringType, Value: "0123456789"},
+	}
+	for _, level := range []string{"4", "3", "2", "1", "0"} {
+		nested := "{{ssm:/app/l" + string(rune(level[0]+1)) + "}}"
+		records["ssm:/app/l"+level] = SsmParameterInfo{Name: "/app/l" + level, Type: stringType, Value: nested + nested}
+	}
+	serviceObject := NewServiceMockedObjectWithExtraRecords(records)
+
+	resolved, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/l0}}", ResolveOptions{Recursive: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 320, len(resolved))
+
+	_, err = ResolveParametersInText(&serviceObject, "{{ssm:/app/l0}}", ResolveOptions{
+		Recursive: true,
+		Limits:    ResolveLimits{MaxExpansionFactor: 1.5},
+	})
+	assert.True(t, errors.Is(err, ErrLimitExceeded))
+}
+
+func TestLimitsMaxExpansionFactorRenderSet(t *testing.T) {
+	renderSet := NewRenderSet(nil, ResolveOptions{Limits: ResolveLimits{MaxExpansionFactor: 2}})
+	assert.Nil(t, renderSet.Add("a", strings.Repeat("x", 100)))
+	assert.Nil(t, renderSet.Add("b", strings.Repeat("{{render:a}}", 2), "a"))
+	assert.Nil(t, renderSet.Add("c", strings.Repeat("{{render:b}}", 3), "b"))
+
+	report, err := renderSet.Execute(context.Background())
+
+	assert.NotNil(t, err)
+	assert.Nil(t, report.Documents["b"].Err)
+	assert.True(t, errors.Is(report.Documents["c"].Err, ErrLimitExceeded))
+}
@@ -0,0 +1,122 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//
+// Error found on a specific line of a document resolved with ResolveParametersInTextByLine
+type LineError struct {
+	// 1-based line number
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return "line " + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+//
+// Placeholder of Reference substituted on a line of a document resolved with ResolveParametersInTextByLine
+type LineSubstitution struct {
+	// 1-based line number
+	Line      int
+	Reference string
+}
+
+//
+// Takes text document and resolves all parameters in it according to ResolveOptions like ResolveParametersInText.
+// It returns the resolved document and every substitution made with the line number of its placeholder.
+// Errors in placeholders are returned as *LineError of their line, missing parameter, secure prefix, constraint,
+// transformer and the other errors about a single parameter reference as *LineError of the first line referencing it.
+func ResolveParametersInTextByLine(
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, []LineSubstitution, error) {
+
+	return ResolveParametersInTextByLineWithContext(context.Background(), service, input, options)
+}
+
+//
+// Same as ResolveParametersInTextByLine, but the SSM requests are canceled when ctx is done.
+func ResolveParametersInTextByLineWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, []LineSubstitution, error) {
+
+	if err := checkDefaultPlaceholderSyntax(options, "ResolveParametersInTextByLine"); err != nil {
+		return "", nil, err
+	}
+
+	lines := strings.Split(input, "\n")
+	lineReferences := make([][]string, len(lines))
+	for i, line := range lines {
+		references, err := parseAndValidatePlaceholders(line, options)
+		if err != nil {
+			return "", nil, &LineError{Line: i + 1, Err: err}
+		}
+		lineReferences[i] = references
+	}
+
+	substitutions := []LineSubstitution{}
+	resolvedText, err := resolveText(ctx, service, input, options, func(substituted substitution) {
+		substitutions = append(substitutions, LineSubstitution{Line: substituted.line, Reference: substituted.reference})
+	})
+	if err != nil {
+		if line := referencingLine(lineReferences, err); line > 0 {
+			return "", nil, &LineError{Line: line, Err: err}
+		}
+		return "", nil, err
+	}
+
+	return resolvedText, substitutions, nil
+}
+
+// returns the 1-based number of the first line referencing a parameter err is about, 0 when err names none of them
+func referencingLine(lineReferences [][]string, err error) int {
+	var isReferenced func(ref string) bool
+
+	var missingParametersError *MissingParametersError
+	var secureParametersError *SecureParametersNotAllowedError
+	var parameterReferenceError *ParameterReferenceError
+	switch {
+	case errors.As(err, &missingParametersError):
+		isReferenced = func(ref string) bool {
+			name := extractParameterNameFromReference(ref)
+			parameterName, _, _ := splitParameterSelector(name)
+			return containsString(missingParametersError.Names, name) || containsString(missingParametersError.Names, parameterName) ||
+				containsString(missingParametersError.Names, ref)
+		}
+
+	case errors.As(err, &secureParametersError):
+		isReferenced = func(ref string) bool {
+			return containsString(secureParametersError.References, ref)
+		}
+
+	case errors.As(err, &parameterReferenceError):
+		isReferenced = func(ref string) bool {
+			return ref == parameterReferenceError.Reference
+		}
+
+	default:
+		return 0
+	}
+
+	for i, references := range lineReferences {
+		for _, ref := range references {
+			if isReferenced(ref) {
+				return i + 1
+			}
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,111 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersInTextByLine(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host":     {Name: "/app/host", Type: stringType, Value: "example.com"},
+		"ssm-secure:/app/p": {Name: "/app/p", Type: secureStringType, Value: "s3cr3t"},
+	})
+
+	text := "host={{ssm:/app/host}}\n\nurl=https://{{ssm:/app/host}}/?p={{ssm-secure:/app/p}}"
+	output, substitutions, err := ResolveParametersInTextByLine(&serviceObject, text, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "host=example.com\n\nurl=https://example.com/?p=s3cr3t", output)
+	assert.ElementsMatch(t, []LineSubstitution{
+		{Line: 1, Reference: "ssm:/app/host"},
+		{Line: 3, Reference: "ssm:/app/host"},
+		{Line: 3, Reference: "ssm-secure:/app/p"},
+	}, substitutions)
+}
+
+func TestResolveParametersInTextByLineError(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/key": {Name: "/app/key", Type: stringType, Value: "not a certificate"},
+	})
+
+	_, _, err := ResolveParametersInTextByLine(&serviceObject, "a\nb={{ssm:/app/key|nosuchtransformer}}", ResolveOptions{})
+	lineError, ok := err.(*LineError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, lineError.Line)
+
+	_, _, err = ResolveParametersInTextByLine(&serviceObject, "a\nb\nc={{ssm:/app/key|pem-leaf}}", ResolveOptions{})
+	lineError, ok = err.(*LineError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, lineError.Line)
+}
+
+func TestResolveParametersInTextByLineResolutionError(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/key":    {Name: "/app/key", Type: stringType, Value: "not a number"},
+		"ssm:/app/secret": {Name: "/app/secret", Type: secureStringType, Value: "s3cr3t"},
+	})
+
+	// errors of the service name no parameter reference
+	_, _, err := ResolveParametersInTextByLine(&serviceObject, "a={{ssm:/app/key}}\nb={{ssm:/app/k}}", ResolveOptions{})
+	assert.NotNil(t, err)
+	_, ok := err.(*LineError)
+	assert.False(t, ok)
+
+	_, _, err = ResolveParametersInTextByLine(newMissingParametersService(), "a={{ssm:/app/host}}\nb={{ssm:/app/missing}}", ResolveOptions{})
+	lineError, ok := err.(*LineError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, lineError.Line)
+	var missingParametersError *MissingParametersError
+	assert.True(t, errors.As(err, &missingParametersError))
+
+	_, _, err = ResolveParametersInTextByLine(&serviceObject, "a={{ssm:/app/key}}\n\nc={{ssm:/app/secret}}", ResolveOptions{})
+	lineError, ok = err.(*LineError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, lineError.Line)
+
+	_, _, err = ResolveParametersInTextByLine(&serviceObject, "a\nb={{ssm:/app/key | type=int}}", ResolveOptions{})
+	lineError, ok = err.(*LineError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, lineError.Line)
+}
+
+func TestResolveParametersInTextByLineWithFormat(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/greeting": {Name: "/app/greeting", Type: stringType, Value: "a=b:c"},
+		"ssm:/app/port":     {Name: "/app/port", Type: stringType, Value: "eighty"},
+	})
+
+	output, substitutions, err := ResolveParametersInTextByLine(&serviceObject, "# app\ngreeting={{ssm:/app/greeting}}",
+		ResolveOptions{Format: propertiesFormat})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "# app\ngreeting=a\\=b\\:c", output)
+	assert.Equal(t, []LineSubstitution{{Line: 2, Reference: "ssm:/app/greeting"}}, substitutions)
+
+	_, _, err = ResolveParametersInTextByLine(&serviceObject, "a\nport={{ssm:/app/port | type=int}}", ResolveOptions{Format: propertiesFormat})
+	lineError, ok := err.(*LineError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, lineError.Line)
+	var parameterReferenceError *ParameterReferenceError
+	assert.True(t, errors.As(err, &parameterReferenceError))
+	assert.Equal(t, "ssm:/app/port", parameterReferenceError.Reference)
+}
+
+func TestResolveParametersInTextByLineWithCanceledContext(t *testing.T) {
+	serviceObject := &slowService{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+			"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+		}),
+		delay: time.Minute,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := ResolveParametersInTextByLineWithContext(ctx, serviceObject, "host={{ssm:/app/host}}", ResolveOptions{})
+
+	assert.True(t, errors.Is(err, context.Canceled))
+}
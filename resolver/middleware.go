@@ -0,0 +1,104 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+//
+// Placeholder of the tenant in the parameter references of a ParameterMiddleware, e.g. ssm:/tenants/{tenant}/quota
+const tenantPlaceholder = "{tenant}"
+
+//
+// Tenants have to be usable as a segment of a parameter name
+var tenantFormat = regexp.MustCompile("^[\\w-]+$")
+
+// key of the tenant attached to a request context by ParameterMiddleware
+type tenantContextKey struct{}
+
+//
+// net/http middleware resolving ParameterReferences for the tenant of every request and attaching the values
+// to the request context. Handlers read the values with ResolvedString, ResolvedInt and ResolvedBool passing
+// the reference as configured, {tenant} included, e.g.
+// ResolvedInt(r.Context(), "ssm:/tenants/{tenant}/quota"), and the tenant with RequestTenant.
+type ParameterMiddleware struct {
+	Service ISsmParameterService
+
+	// Returns the tenant of a request, e.g. TenantFromHeader("X-Tenant-ID") or TenantFromHost
+	Tenant func(request *http.Request) (string, error)
+
+	// References resolved for every request, {tenant} is replaced with the tenant of the request
+	ParameterReferences []string
+
+	Options ResolveOptions
+}
+
+//
+// Wraps next, responding with 400 Bad Request when the tenant cannot be determined
+// and with 502 Bad Gateway when the parameters of the tenant cannot be resolved.
+func (m *ParameterMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		tenant, err := m.Tenant(request)
+		if err == nil && !tenantFormat.MatchString(tenant) {
+			err = errors.New("invalid tenant " + tenant)
+		}
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		tenantReferences := map[string]string{}
+		for _, ref := range m.ParameterReferences {
+			tenantReferences[ref] = strings.Replace(ref, tenantPlaceholder, tenant, -1)
+		}
+
+		values, err := ResolveMapWithContext(request.Context(), m.Service, tenantReferences, m.Options)
+		if err != nil {
+			http.Error(writer, "cannot resolve parameters of tenant "+tenant, http.StatusBadGateway)
+			return
+		}
+
+		ctx := context.WithValue(request.Context(), tenantContextKey{}, tenant)
+		next.ServeHTTP(writer, request.WithContext(withValues(ctx, values)))
+	})
+}
+
+//
+// Returns the tenant attached to the request context by ParameterMiddleware.
+func RequestTenant(ctx context.Context) (string, bool) {
+	tenant, found := ctx.Value(tenantContextKey{}).(string)
+	return tenant, found
+}
+
+//
+// Returns a ParameterMiddleware.Tenant function reading the tenant from the header of the request.
+func TenantFromHeader(header string) func(request *http.Request) (string, error) {
+	return func(request *http.Request) (string, error) {
+		tenant := request.Header.Get(header)
+		if len(tenant) == 0 {
+			return "", errors.New("header " + header + " is not provided")
+		}
+		return tenant, nil
+	}
+}
+
+//
+// ParameterMiddleware.Tenant function using the first label of the request host as the tenant,
+// e.g. acme for acme.example.com:8443.
+func TenantFromHost(request *http.Request) (string, error) {
+	host := request.Host
+	if hostWithoutPort, _, err := net.SplitHostPort(host); err == nil {
+		host = hostWithoutPort
+	}
+
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 || len(labels[0]) == 0 {
+		return "", errors.New("host " + request.Host + " has no tenant subdomain")
+	}
+
+	return labels[0], nil
+}
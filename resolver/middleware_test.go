@@ -0,0 +1,56 @@
+package resolver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterMiddleware(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/tenants/acme/quota":   {Name: "/tenants/acme/quota", Type: stringType, Value: "100"},
+		"ssm:/tenants/globex/quota": {Name: "/tenants/globex/quota", Type: stringType, Value: "5"},
+	})
+
+	middleware := &ParameterMiddleware{
+		Service:             &serviceObject,
+		Tenant:              TenantFromHost,
+		ParameterReferences: []string{"ssm:/tenants/{tenant}/quota"},
+	}
+	handler := middleware.Handler(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		tenant, _ := RequestTenant(request.Context())
+		quota, err := ResolvedInt(request.Context(), "ssm:/tenants/{tenant}/quota")
+		assert.Nil(t, err)
+		writer.Write([]byte(tenant + "=" + strconv.Itoa(quota)))
+	}))
+
+	for host, expected := range map[string]string{"acme.example.com": "acme=100", "globex.example.com:8443": "globex=5"} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "http://"+host+"/", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, expected, recorder.Body.String())
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "http://initech.example.com/", nil))
+	assert.Equal(t, http.StatusBadGateway, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "http://localhost/", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestTenantFromHeader(t *testing.T) {
+	request := httptest.NewRequest("GET", "http://example.com/", nil)
+
+	_, err := TenantFromHeader("X-Tenant-ID")(request)
+	assert.NotNil(t, err)
+
+	request.Header.Set("X-Tenant-ID", "acme")
+	tenant, err := TenantFromHeader("X-Tenant-ID")(request)
+	assert.Nil(t, err)
+	assert.Equal(t, "acme", tenant)
+}
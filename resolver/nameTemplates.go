@@ -0,0 +1,129 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//
+// Maximum depth of placeholders nested in parameter names, e.g. 2 for {{ssm:/a/{{ssm:/b/{{ssm:/c}}}}}}
+const maxNameTemplateDepth = 5
+
+//
+// Start of a placeholder whose parameter or secret name is computed from placeholders, e.g. "{{ssm:/app/" of
+// {{ssm:/app/{{ssm:/app/active-color}}/endpoint}}, matched against the text from the last {{ before a placeholder
+var nameTemplateStart = regexp.MustCompile("^{{\\s*(?:(?:" + ssmNonSecurePrefix + "|" + ssmSecurePrefix + ")[\\w-/]*|" +
+	secretsManagerPrefix + "[\\w/+=.@:-]*)$")
+
+//
+// Parameter references of placeholders with {{ }} placeholders nested in their names, up to maxNameTemplateDepth levels
+var nameTemplateReference = func() string {
+	nested := "{{[^{}]*}}"
+	for i := 1; i < maxNameTemplateDepth; i++ {
+		nested = "{{(?:[^{}]|" + nested + ")*}}"
+	}
+
+	return "(?:(?:" + ssmNonSecurePrefix + "|" + ssmSecurePrefix + ")(?:[\\w-/]|" + nested + ")*" + nested + "(?:[\\w-/]|" + nested + ")*|" +
+		secretsManagerPrefix + "(?:[\\w/+=.@:-]|" + nested + ")*" + nested + "(?:[\\w/+=.@:-]|" + nested + ")*)"
+}()
+
+//
+// Values a placeholder nested in a parameter name can resolve to, the characters of parameter names
+var parameterNameSegment = regexp.MustCompile("^[\\w-/]+$")
+
+// prepares the template text of a resolve call: translates its placeholder delimiters (see PlaceholderSyntax) and
+// substitutes the placeholders nested in parameter names (see expandParameterNameTemplates)
+func prepareTemplate(ctx context.Context, service ISsmParameterService, text string, options ResolveOptions) (string, error) {
+	text, err := translatePlaceholderSyntax(text, options.PlaceholderSyntax)
+	if err != nil {
+		return "", err
+	}
+
+	return expandParameterNameTemplates(ctx, service, text, withDefaultPlaceholderSyntax(options))
+}
+
+// returns the submatch indexes of the placeholders of text nested in the parameter names of other placeholders
+func findNestedPlaceholders(text string) [][]int {
+	nestedMatches := [][]int{}
+	for _, placeholder := range parameterPlaceholders() {
+		for _, match := range placeholder.FindAllStringSubmatchIndex(text, -1) {
+			start := strings.LastIndex(text[:match[0]], "{{")
+			if start >= 0 && nameTemplateStart.MatchString(text[start:match[0]]) {
+				nestedMatches = append(nestedMatches, match)
+			}
+		}
+	}
+
+	return nestedMatches
+}
+
+// substitutes the placeholders of text nested in the parameter names of other placeholders, innermost first, so that
+// {{ssm:/app/{{ssm:/app/active-color}}/endpoint}} becomes {{ssm:/app/blue/endpoint}} when /app/active-color is blue.
+// Values have to be made of the characters of parameter names: they cannot hold placeholders, so names cannot depend
+// on themselves. Nested placeholders left unresolved, e.g. secure ones with IgnoreSecureParameters, are left as they are.
+func expandParameterNameTemplates(
+	ctx context.Context,
+	service ISsmParameterService,
+	text string,
+	options ResolveOptions) (string, error) {
+
+	for depth := 0; ; depth++ {
+		nestedMatches := findNestedPlaceholders(text)
+
+		if len(nestedMatches) == 0 {
+			return text, nil
+		}
+
+		if depth >= maxNameTemplateDepth {
+			return "", withStatus(StatusParseError, errors.New("placeholders are nested in parameter names deeper than "+
+				strconv.Itoa(maxNameTemplateDepth)+" levels"))
+		}
+
+		nestedReferences := make([]string, 0, len(nestedMatches))
+		for _, match := range nestedMatches {
+			nestedReferences = append(nestedReferences, text[match[2]:match[3]])
+		}
+
+		resolvedParametersMap, err := ResolveParameterReferenceListWithContext(ctx, service, nestedReferences, options)
+		if err != nil {
+			return "", err
+		}
+
+		sort.Slice(nestedMatches, func(i, j int) bool { return nestedMatches[i][0] < nestedMatches[j][0] })
+
+		var buffer strings.Builder
+		last := 0
+		for _, match := range nestedMatches {
+			ref := text[match[2]:match[3]]
+			param, resolved := resolvedParametersMap[ref]
+			if !resolved {
+				continue
+			}
+
+			value, err := applyTransformers(param.Value, parsePlaceholderModifiers(text[match[4]:match[5]]))
+			if err != nil {
+				return "", withStatus(StatusPolicyViolation, newParameterReferenceError(ref, "cannot transform value of parameter reference {{%s}}: %w", err))
+			}
+
+			if !parameterNameSegment.MatchString(value) {
+				return "", withStatus(StatusPolicyViolation, errors.New("value of parameter reference {{"+ref+
+					"}} cannot be part of a parameter name"))
+			}
+
+			buffer.WriteString(text[last:match[0]])
+			buffer.WriteString(value)
+			last = match[1]
+		}
+
+		if last == 0 {
+			return text, nil
+		}
+		buffer.WriteString(text[last:])
+
+		text = buffer.String()
+	}
+}
@@ -0,0 +1,119 @@
+package resolver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newNameTemplatesServiceMock() *countingService {
+	return &countingService{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+			"ssm:/app/active-color":      {Name: "/app/active-color", Type: stringType, Value: "blue"},
+			"ssm:/app/blue/endpoint":     {Name: "/app/blue/endpoint", Type: stringType, Value: "blue.example.com"},
+			"ssm:/app/green/endpoint":    {Name: "/app/green/endpoint", Type: stringType, Value: "green.example.com"},
+			"ssm:/app/stage":             {Name: "/app/stage", Type: stringType, Value: "prod"},
+			"ssm:/prod/color":            {Name: "/prod/color", Type: stringType, Value: "green"},
+			"ssm-secure:/app/blue/token": {Name: "/app/blue/token", Type: secureStringType, Value: "t0ken"},
+			"ssm:/app/bad-color":         {Name: "/app/bad-color", Type: stringType, Value: "{{ssm:/app/stage}}"},
+		}),
+	}
+}
+
+func TestResolveParametersInTextWithNameTemplates(t *testing.T) {
+	serviceObject := newNameTemplatesServiceMock()
+
+	resolved, err := ResolveParametersInText(serviceObject,
+		"endpoint={{ssm:/app/{{ssm:/app/active-color}}/endpoint}}\ntoken={{ ssm-secure:/app/{{ ssm:/app/active-color }}/token | urlencode }}",
+		ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "endpoint=blue.example.com\ntoken=t0ken", resolved)
+	assert.Equal(t, 2, serviceObject.calls)
+}
+
+func TestResolveParametersInTextWithNestedNameTemplates(t *testing.T) {
+	serviceObject := newNameTemplatesServiceMock()
+
+	resolved, err := ResolveParametersInText(serviceObject, "{{ssm:/app/{{ssm:/{{ssm:/app/stage}}/color}}/endpoint}}", ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "green.example.com", resolved)
+}
+
+func TestResolveParametersInFileWithNameTemplates(t *testing.T) {
+	serviceObject := newNameTemplatesServiceMock()
+
+	dir := t.TempDir()
+	inputFileName := filepath.Join(dir, "app.conf.tmpl")
+	outputFileName := filepath.Join(dir, "app.conf")
+	assert.Nil(t, os.WriteFile(inputFileName, []byte("endpoint=${ssm:/app/${ssm:/app/active-color}/endpoint}"), 0600))
+
+	options := ResolveOptions{PlaceholderSyntax: PlaceholderSyntax{Open: "${", Close: "}"}}
+	assert.Nil(t, ResolveParametersInFile(serviceObject, inputFileName, outputFileName, options))
+
+	resolved, err := os.ReadFile(outputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, "endpoint=blue.example.com", string(resolved))
+}
+
+func TestRenderSetWithNameTemplates(t *testing.T) {
+	serviceObject := newNameTemplatesServiceMock()
+
+	set := NewRenderSet(serviceObject, ResolveOptions{})
+	assert.Nil(t, set.Add("a", "{{ssm:/app/{{ssm:/app/active-color}}/endpoint}}"))
+	assert.Nil(t, set.Add("b", "{{ssm:/app/active-color}} {{ssm:/app/{{ssm:/app/active-color}}/endpoint}}"))
+	rendered, err := set.Render()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "blue.example.com", rendered["a"])
+	assert.Equal(t, "blue blue.example.com", rendered["b"])
+	assert.Equal(t, 2, serviceObject.calls)
+}
+
+func TestNameTemplateFailures(t *testing.T) {
+	serviceObject := newNameTemplatesServiceMock()
+
+	// values holding anything but name characters, placeholders included, cannot be part of names
+	_, err := ResolveParametersInText(serviceObject, "{{ssm:/app/{{ssm:/app/bad-color}}/endpoint}}", ResolveOptions{})
+	assert.NotNil(t, err)
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+
+	_, err = ResolveParametersInText(serviceObject, "{{ssm:/app/{{ssm:/app/missing}}/endpoint}}", ResolveOptions{})
+	assert.NotNil(t, err)
+
+	// every parameter exists, /a/a/a... is nested one level deeper than allowed
+	text := "{{ssm:/a}}"
+	for i := 0; i <= maxNameTemplateDepth; i++ {
+		text = "{{ssm:/a/" + text + "}}"
+	}
+	_, err = expandParameterNameTemplates(context.Background(), anyParameterService{}, text, ResolveOptions{})
+	assert.Equal(t, StatusParseError, StatusOf(err))
+}
+
+// resolves every reference to a parameter with value a
+type anyParameterService struct {
+	ISsmParameterService
+}
+
+func (anyParameterService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	parameters := map[string]SsmParameterInfo{}
+	for _, ref := range parameterReferences {
+		parameters[ref] = SsmParameterInfo{Name: extractParameterNameFromReference(ref), Type: stringType, Value: "a"}
+	}
+
+	return parameters, nil
+}
+
+func TestNameTemplatesLeftWithIgnoredSecureParameters(t *testing.T) {
+	serviceObject := newNameTemplatesServiceMock()
+
+	text := "{{ssm:/app/{{ssm-secure:/app/blue/token}}/endpoint}}"
+	expanded, err := expandParameterNameTemplates(context.Background(), serviceObject, text, ResolveOptions{IgnoreSecureParameters: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, text, expanded)
+}
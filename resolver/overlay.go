@@ -0,0 +1,242 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//
+// Takes a base JSON document and a map of (environment name) to overlay JSON document,
+// resolves SSM parameters in all of them according to ResolveOptions with a single set of
+// SSM lookups and deep-merges every resolved overlay on top of the resolved base.
+// It will return a map of (environment name) to the final JSON document.
+// Objects are merged key by key, any other overlay value replaces the base value.
+// Documents may contain comments, trailing commas and the other JSON5 conveniences, the result is strict JSON.
+func ResolveParametersWithOverlays(
+	service ISsmParameterService,
+	baseDocument string,
+	overlayDocuments map[string]string,
+	options ResolveOptions) (map[string]string, error) {
+
+	return ResolveParametersWithOverlaysWithContext(context.Background(), service, baseDocument, overlayDocuments, options)
+}
+
+//
+// Same as ResolveParametersWithOverlays, but the SSM requests are canceled when ctx is done.
+func ResolveParametersWithOverlaysWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	baseDocument string,
+	overlayDocuments map[string]string,
+	options ResolveOptions) (map[string]string, error) {
+
+	if err := checkDefaultPlaceholderSyntax(options, "ResolveParametersWithOverlays"); err != nil {
+		return nil, err
+	}
+
+	baseDocument, err := normalizeJSON5(baseDocument)
+	if err != nil {
+		return nil, fmt.Errorf("base document is not a valid JSON: %w", err)
+	}
+
+	normalizedOverlayDocuments := map[string]string{}
+	for environment, overlayDocument := range overlayDocuments {
+		normalizedOverlayDocuments[environment], err = normalizeJSON5(overlayDocument)
+		if err != nil {
+			return nil, fmt.Errorf("overlay document for environment %s is not a valid JSON: %w", environment, err)
+		}
+	}
+	overlayDocuments = normalizedOverlayDocuments
+
+	allDocuments := []string{baseDocument}
+	for _, overlay := range overlayDocuments {
+		allDocuments = append(allDocuments, overlay)
+	}
+
+	resolvedParametersMap, err := extractParametersFromTexts(ctx, service, allDocuments, options)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := decodeResolvedJSON(baseDocument, resolvedParametersMap)
+	if err != nil {
+		return nil, fmt.Errorf("base document is not a valid JSON: %w", err)
+	}
+
+	result := map[string]string{}
+	for environment, overlayDocument := range overlayDocuments {
+		overlay, err := decodeResolvedJSON(overlayDocument, resolvedParametersMap)
+		if err != nil {
+			return nil, fmt.Errorf("overlay document for environment %s is not a valid JSON: %w", environment, err)
+		}
+
+		merged, err := json.MarshalIndent(deepMerge(base, overlay), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := checkUnresolvedPlaceholders(string(merged), options); err != nil {
+			return nil, fmt.Errorf("document for environment %s: %w", environment, err)
+		}
+		result[environment] = string(merged)
+	}
+
+	return result, nil
+}
+
+// decodes the JSON document with the resolved parameters substituted into it. Placeholders outside of strings are
+// substituted into the text first, their values have to be JSON values; the document is then decoded, numbers as
+// json.Number so that they keep their precision, and the placeholders inside strings are substituted into the
+// decoded keys and strings.
+func decodeResolvedJSON(document string, resolvedParametersMap map[string]SsmParameterInfo) (interface{}, error) {
+	document, err := substituteBareJsonPlaceholders(document, resolvedParametersMap)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+
+	var tree interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the top-level value")
+	}
+
+	return substituteJsonStrings(tree, resolvedParametersMap)
+}
+
+// substitutes the placeholders of document outside of JSON strings with their values, which must be JSON values
+func substituteBareJsonPlaceholders(document string, resolvedParametersMap map[string]SsmParameterInfo) (string, error) {
+	matches := [][]int{}
+	for _, placeholder := range parameterPlaceholders() {
+		matches = append(matches, placeholder.FindAllStringSubmatchIndex(document, -1)...)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i][0] < matches[j][0] })
+
+	var output strings.Builder
+	last := 0
+	for _, match := range matches {
+		ref := document[match[2]:match[3]]
+		param, resolved := resolvedParametersMap[ref]
+		if !resolved || jsonInStringAt(document, match[0]) {
+			continue
+		}
+
+		value, err := applyTransformers(param.Value, parsePlaceholderModifiers(document[match[4]:match[5]]))
+		if err != nil {
+			return "", withStatus(StatusPolicyViolation, newParameterReferenceError(ref, "cannot transform value of parameter reference {{%s}}: %w", err))
+		}
+
+		value, err = escapeJsonValue(document, match[0], value)
+		if err != nil {
+			return "", withStatus(StatusPolicyViolation, newParameterReferenceError(ref, "cannot substitute parameter reference {{%s}}: %w", err))
+		}
+
+		output.WriteString(document[last:match[0]])
+		output.WriteString(value)
+		last = match[1]
+	}
+	output.WriteString(document[last:])
+
+	return output.String(), nil
+}
+
+// substitutes the resolved parameters into the keys and strings of the decoded JSON value
+func substituteJsonStrings(value interface{}, resolvedParametersMap map[string]SsmParameterInfo) (interface{}, error) {
+	switch value := value.(type) {
+	case string:
+		return replaceParameterPlaceholders(value, resolvedParametersMap)
+
+	case []interface{}:
+		for i, element := range value {
+			substituted, err := substituteJsonStrings(element, resolvedParametersMap)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = substituted
+		}
+		return value, nil
+
+	case map[string]interface{}:
+		substitutedObject := make(map[string]interface{}, len(value))
+		for key, element := range value {
+			substitutedKey, err := replaceParameterPlaceholders(key, resolvedParametersMap)
+			if err != nil {
+				return nil, err
+			}
+			substitutedObject[substitutedKey], err = substituteJsonStrings(element, resolvedParametersMap)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return substitutedObject, nil
+	}
+
+	return value, nil
+}
+
+//
+// Reads baseFileName and every file of overlayFileNames (environment name to file name) and
+// resolves them with ResolveParametersWithOverlays.
+func ResolveParametersInOverlayFiles(
+	service ISsmParameterService,
+	baseFileName string,
+	overlayFileNames map[string]string,
+	options ResolveOptions) (map[string]string, error) {
+
+	return ResolveParametersInOverlayFilesWithContext(context.Background(), service, baseFileName, overlayFileNames, options)
+}
+
+//
+// Same as ResolveParametersInOverlayFiles, but the SSM requests are canceled when ctx is done.
+func ResolveParametersInOverlayFilesWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	baseFileName string,
+	overlayFileNames map[string]string,
+	options ResolveOptions) (map[string]string, error) {
+
+	baseDocument, err := readValidatedTextFromFile(baseFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	overlayDocuments := map[string]string{}
+	for environment, overlayFileName := range overlayFileNames {
+		overlayDocuments[environment], err = readValidatedTextFromFile(overlayFileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ResolveParametersWithOverlaysWithContext(ctx, service, baseDocument, overlayDocuments, options)
+}
+
+// returns a copy of base with overlay merged on top of it, base itself is not modified
+func deepMerge(base interface{}, overlay interface{}) interface{} {
+	baseObject, baseIsObject := base.(map[string]interface{})
+	overlayObject, overlayIsObject := overlay.(map[string]interface{})
+	if !baseIsObject || !overlayIsObject {
+		return overlay
+	}
+
+	merged := map[string]interface{}{}
+	for key, value := range baseObject {
+		merged[key] = value
+	}
+	for key, value := range overlayObject {
+		if baseValue, contains := merged[key]; contains {
+			merged[key] = deepMerge(baseValue, value)
+		} else {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,68 @@
+package resolver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersWithOverlays(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/dev/db/host":  {Name: "/dev/db/host", Type: stringType, Value: "dev.db"},
+		"ssm:/prod/db/host": {Name: "/prod/db/host", Type: stringType, Value: "prod.db"},
+		"ssm:/app/name":     {Name: "/app/name", Type: stringType, Value: "web"},
+	})
+
+	base := `{"name": "{{ssm:/app/name}}", "db": {"host": "localhost", "port": 5432}, "replicas": 1}`
+	overlays := map[string]string{
+		"dev":  `{"db": {"host": "{{ssm:/dev/db/host}}"}}`,
+		"prod": `{"db": {"host": "{{ssm:/prod/db/host}}"}, "replicas": 3}`,
+	}
+
+	documents, err := ResolveParametersWithOverlays(&serviceObject, base, overlays, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Len(t, documents, 2)
+
+	var dev, prod map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(documents["dev"]), &dev))
+	assert.Nil(t, json.Unmarshal([]byte(documents["prod"]), &prod))
+
+	assert.Equal(t, map[string]interface{}{
+		"name":     "web",
+		"db":       map[string]interface{}{"host": "dev.db", "port": float64(5432)},
+		"replicas": float64(1),
+	}, dev)
+	assert.Equal(t, map[string]interface{}{
+		"name":     "web",
+		"db":       map[string]interface{}{"host": "prod.db", "port": float64(5432)},
+		"replicas": float64(3),
+	}, prod)
+}
+
+func TestResolveParametersWithOverlaysInvalidJson(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+
+	_, err := ResolveParametersWithOverlays(&serviceObject, `{"a": 1}`, map[string]string{"dev": `{"a": `}, ResolveOptions{})
+
+	assert.NotNil(t, err)
+}
+
+func TestResolveParametersWithOverlaysKeepsNumbersAndEscapesStrings(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/id":    {Name: "/app/id", Type: stringType, Value: "9007199254740993"},
+		"ssm:/app/motto": {Name: "/app/motto", Type: stringType, Value: `say "hi"`},
+	})
+
+	base := `{"id": {{ssm:/app/id}}, "max": 18446744073709551615, "motto": "{{ssm:/app/motto}}"}`
+	documents, err := ResolveParametersWithOverlays(&serviceObject, base, map[string]string{"dev": `{"ratio": 0.1}`}, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "{\n"+
+		"  \"id\": 9007199254740993,\n"+
+		"  \"max\": 18446744073709551615,\n"+
+		"  \"motto\": \"say \\\"hi\\\"\",\n"+
+		"  \"ratio\": 0.1\n"+
+		"}", documents["dev"])
+}
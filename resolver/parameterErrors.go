@@ -0,0 +1,81 @@
+package resolver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//
+// Error returned when parameters do not exist or are not accessible, wrapped with StatusNotFound.
+// Use errors.As to get the names of the parameters.
+type MissingParametersError struct {
+	// Names of the parameters, without the ssm: or ssm-secure: prefix
+	Names []string
+}
+
+func (e *MissingParametersError) Error() string {
+	return "The following parameter(s) cannot be resolved: " + strings.Join(e.Names, ",")
+}
+
+//
+// Error returned when references with the non-secure prefix ssm: resolve to SecureString parameters, wrapped with
+// StatusPolicyViolation. Secure parameters have to be referenced with ssm-secure: to be resolved.
+type SecureParametersNotAllowedError struct {
+	// Offending parameter references, sorted
+	References []string
+}
+
+func (e *SecureParametersNotAllowedError) Error() string {
+	placeholders := make([]string, len(e.References))
+	for i, ref := range e.References {
+		placeholders[i] = "{{" + ref + "}}"
+	}
+
+	return "non-secure prefix " + ssmNonSecurePrefix + " is used for parameter reference(s) of secure type " + secureStringType +
+		": " + strings.Join(placeholders, ",")
+}
+
+//
+// Error about the parameter of a single reference, e.g. a value failing a constraint or a transformer, or a failed
+// fetch from a registered source. Use errors.As to get the reference.
+type ParameterReferenceError struct {
+	// Parameter reference, e.g. ssm:/app/db/password
+	Reference string
+
+	// Error naming the reference
+	Err error
+}
+
+func (e *ParameterReferenceError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ParameterReferenceError) Unwrap() error {
+	return e.Err
+}
+
+//
+// Error returned when ResolveOptions.FailOnUnresolvedPlaceholders is set and placeholders are left in a resolved
+// document, e.g. because of a typo in a reference, wrapped with StatusParseError
+type UnresolvedPlaceholdersError struct {
+	// Placeholders left in the document, in order of appearance
+	Placeholders []string
+}
+
+func (e *UnresolvedPlaceholdersError) Error() string {
+	return "the following placeholder(s) are left unresolved: " + strings.Join(e.Placeholders, ",")
+}
+
+// returns the error of the parameters named names, sorted
+func newMissingParametersError(names []string) error {
+	sortedNames := append([]string{}, names...)
+	sort.Strings(sortedNames)
+
+	return withStatus(StatusNotFound, &MissingParametersError{Names: sortedNames})
+}
+
+// returns the error of the parameter reference ref with the message of format, whose first verb is the reference
+func newParameterReferenceError(ref string, format string, args ...interface{}) error {
+	return &ParameterReferenceError{Reference: ref, Err: fmt.Errorf(format, append([]interface{}{ref}, args...)...)}
+}
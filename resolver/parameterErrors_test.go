@@ -0,0 +1,46 @@
+package resolver
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingParametersErrorReportsAllBatches(t *testing.T) {
+	service := newSnapshotService(&Snapshot{Parameters: []SsmParameterInfo{
+		{Name: "/app/host", Type: stringType, Value: "db.internal"},
+	}})
+
+	placeholders := []string{"{{ssm:/app/host}}"}
+	for i := 0; i < 2*maxParametersRetrievedFromSsm; i++ {
+		placeholders = append(placeholders, "{{ssm:/app/missing"+strconv.Itoa(i)+"}}")
+	}
+
+	_, err := ResolveParametersInText(service, strings.Join(placeholders, " "), ResolveOptions{})
+
+	var missingParametersError *MissingParametersError
+	assert.True(t, errors.As(err, &missingParametersError))
+	assert.Equal(t, 2*maxParametersRetrievedFromSsm, len(missingParametersError.Names))
+	assert.Equal(t, "/app/missing0", missingParametersError.Names[0])
+	assert.Equal(t, StatusNotFound, StatusOf(err))
+}
+
+func TestSecureParametersNotAllowedError(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/password": {Name: "/app/password", Type: secureStringType, Value: "secret"},
+		"ssm:/app/key":      {Name: "/app/key", Type: secureStringType, Value: "secret"},
+		"ssm:/app/host":     {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	_, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/password}} {{ssm:/app/host}} {{ssm:/app/key}}", ResolveOptions{})
+
+	var secureParametersNotAllowedError *SecureParametersNotAllowedError
+	assert.True(t, errors.As(err, &secureParametersNotAllowedError))
+	assert.Equal(t, []string{"ssm:/app/key", "ssm:/app/password"}, secureParametersNotAllowedError.References)
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+	assert.Equal(t, "non-secure prefix ssm: is used for parameter reference(s) of secure type SecureString: "+
+		"{{ssm:/app/key}},{{ssm:/app/password}}", err.Error())
+}
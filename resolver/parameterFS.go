@@ -0,0 +1,218 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+//
+// ParameterFS is a read-only fs.FS whose files are parameters, for code reading secrets from files such as TLS loaders:
+// opening app/prod/db/password reads the value of /app/prod/db/password. Values are resolved according to
+// ResolveOptions, so that they are served from the Cache and SecureString parameters are not readable when
+// IgnoreSecureParameters is set. Directories list the parameters under their path, use fs.Sub for a root other
+// than /, e.g. fs.Sub(fsys, "app/prod").
+type ParameterFS struct {
+	service ISsmParameterService
+	options ResolveOptions
+}
+
+func NewParameterFS(service ISsmParameterService, options ResolveOptions) *ParameterFS {
+	// missing parameters are files that do not exist
+	options.IgnoreMissingParameters = false
+
+	return &ParameterFS{
+		service: service,
+		options: options,
+	}
+}
+
+//
+// Opens the parameter named / followed by name, or the directory of the parameters under that path.
+// Missing parameters are reported with fs.ErrNotExist, SecureString parameters left out by IgnoreSecureParameters
+// with fs.ErrPermission.
+func (p *ParameterFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	ctx := context.Background()
+	if name != "." {
+		param, err := p.readParameter(ctx, name)
+		if err == nil {
+			return &parameterFile{
+				Reader: strings.NewReader(param.Value),
+				info:   parameterFileInfo{name: name, size: int64(len(param.Value))},
+			}, nil
+		}
+
+		var missingParametersError *MissingParametersError
+		if !errors.As(err, &missingParametersError) {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+	}
+
+	entries, err := p.readDirectory(ctx, name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return &parameterDirectory{
+		info:    parameterFileInfo{name: name, isDir: true},
+		entries: entries,
+	}, nil
+}
+
+// resolves the parameter of the file name, with the secure prefix when it turns out to be a SecureString
+func (p *ParameterFS) readParameter(ctx context.Context, name string) (SsmParameterInfo, error) {
+	ref := ssmNonSecurePrefix + "/" + name
+	resolvedParametersMap, err := ResolveParameterReferenceListWithContext(ctx, p.service, []string{ref}, p.options)
+
+	var secureParametersNotAllowedError *SecureParametersNotAllowedError
+	if errors.As(err, &secureParametersNotAllowedError) {
+		if p.options.IgnoreSecureParameters {
+			return SsmParameterInfo{}, fs.ErrPermission
+		}
+
+		ref = ssmSecurePrefix + "/" + name
+		resolvedParametersMap, err = ResolveParameterReferenceListWithContext(ctx, p.service, []string{ref}, p.options)
+	}
+	if err != nil {
+		return SsmParameterInfo{}, err
+	}
+
+	param, resolved := resolvedParametersMap[ref]
+	if !resolved {
+		return SsmParameterInfo{}, fs.ErrNotExist
+	}
+
+	return param, nil
+}
+
+// lists the parameters and the directories right under the directory name, which does not exist without parameters
+// but for the root
+func (p *ParameterFS) readDirectory(ctx context.Context, name string) ([]fs.DirEntry, error) {
+	path := "/"
+	if name != "." {
+		path += name
+	}
+
+	parameters, err := ResolveParametersByPathWithContext(ctx, p.service, path, true, p.options)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parameters) == 0 && name != "." {
+		return nil, fs.ErrNotExist
+	}
+
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	infos := map[string]parameterFileInfo{}
+	for parameterName, param := range parameters {
+		relativeName := strings.TrimPrefix(parameterName, prefix)
+		if separator := strings.Index(relativeName, "/"); separator >= 0 {
+			infos[relativeName[:separator]] = parameterFileInfo{name: relativeName[:separator], isDir: true}
+		} else if _, contains := infos[relativeName]; !contains {
+			infos[relativeName] = parameterFileInfo{name: relativeName, size: int64(len(param.Value))}
+		}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(infos))
+	for _, info := range infos {
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	return entries, nil
+}
+
+// parameter value opened by ParameterFS
+type parameterFile struct {
+	*strings.Reader
+	info parameterFileInfo
+}
+
+func (f *parameterFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *parameterFile) Close() error {
+	return nil
+}
+
+// directory of parameters opened by ParameterFS
+type parameterDirectory struct {
+	info    parameterFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *parameterDirectory) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *parameterDirectory) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+func (d *parameterDirectory) Close() error {
+	return nil
+}
+
+// returns the next count entries, or all the remaining ones when count is not positive, see fs.ReadDirFile
+func (d *parameterDirectory) ReadDir(count int) ([]fs.DirEntry, error) {
+	remaining := len(d.entries) - d.offset
+	if count > 0 && remaining == 0 {
+		return nil, io.EOF
+	}
+	if count <= 0 || count > remaining {
+		count = remaining
+	}
+
+	entries := d.entries[d.offset : d.offset+count]
+	d.offset += count
+	return entries, nil
+}
+
+// fs.FileInfo of the files and directories of ParameterFS, which are read-only and have no modification time
+type parameterFileInfo struct {
+	name  string
+	size  int64
+	isDir bool
+}
+
+func (i parameterFileInfo) Name() string {
+	if i.name == "." {
+		return i.name
+	}
+
+	return i.name[strings.LastIndex(i.name, "/")+1:]
+}
+
+func (i parameterFileInfo) Size() int64 {
+	return i.size
+}
+
+func (i parameterFileInfo) Mode() fs.FileMode {
+	if i.isDir {
+		return fs.ModeDir | 0555
+	}
+
+	return 0444
+}
+
+func (i parameterFileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (i parameterFileInfo) IsDir() bool {
+	return i.isDir
+}
+
+func (i parameterFileInfo) Sys() interface{} {
+	return nil
+}
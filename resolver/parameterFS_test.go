@@ -0,0 +1,77 @@
+package resolver
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newParameterFSServiceMock() *missingParametersService {
+	return &missingParametersService{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+			"ssm:/app/prod/db/host":            {Name: "/app/prod/db/host", Type: stringType, Value: "db.example.com"},
+			"ssm:/app/prod/db/password":        {Name: "/app/prod/db/password", Type: secureStringType, Value: "s3cr3t"},
+			"ssm-secure:/app/prod/db/password": {Name: "/app/prod/db/password", Type: secureStringType, Value: "s3cr3t"},
+			"ssm:/app/prod/tls/cert.pem":       {Name: "/app/prod/tls/cert.pem", Type: stringType, Value: "-----BEGIN CERTIFICATE-----"},
+		}),
+	}
+}
+
+func TestParameterFS(t *testing.T) {
+	fsys := NewParameterFS(newParameterFSServiceMock(), ResolveOptions{})
+
+	password, err := fs.ReadFile(fsys, "app/prod/db/password")
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cr3t", string(password))
+
+	entries, err := fs.ReadDir(fsys, "app/prod")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, "db", entries[0].Name())
+	assert.True(t, entries[0].IsDir())
+
+	assert.Nil(t, fstest.TestFS(fsys, "app/prod/db/host", "app/prod/db/password", "app/prod/tls/cert.pem"))
+}
+
+func TestParameterFSWithSub(t *testing.T) {
+	fsys, err := fs.Sub(NewParameterFS(newParameterFSServiceMock(), ResolveOptions{}), "app/prod")
+	assert.Nil(t, err)
+
+	cert, err := fs.ReadFile(fsys, "tls/cert.pem")
+	assert.Nil(t, err)
+	assert.Equal(t, "-----BEGIN CERTIFICATE-----", string(cert))
+}
+
+func TestParameterFSErrors(t *testing.T) {
+	fsys := NewParameterFS(newParameterFSServiceMock(), ResolveOptions{IgnoreSecureParameters: true})
+
+	_, err := fsys.Open("app/prod/db/password")
+	assert.True(t, errors.Is(err, fs.ErrPermission))
+
+	_, err = fsys.Open("app/prod/db/missing")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	_, err = fsys.Open("/app/prod/db/host")
+	assert.True(t, errors.Is(err, fs.ErrInvalid))
+
+	entries, err := fs.ReadDir(fsys, "app/prod/db")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+}
+
+func TestParameterFSUsesCache(t *testing.T) {
+	serviceObject := newParameterFSServiceMock()
+	fsys := NewParameterFS(serviceObject, ResolveOptions{Cache: NewMemoryCache(time.Minute)})
+
+	_, err := fs.ReadFile(fsys, "app/prod/db/host")
+	assert.Nil(t, err)
+
+	delete(serviceObject.records, "ssm:/app/prod/db/host")
+	host, err := fs.ReadFile(fsys, "app/prod/db/host")
+	assert.Nil(t, err)
+	assert.Equal(t, "db.example.com", string(host))
+}
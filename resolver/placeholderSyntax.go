@@ -0,0 +1,98 @@
+package resolver
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+//
+// PlaceholderSyntax sets the delimiters of the placeholders of templates whose {{ }} belong to another engine,
+// e.g. Open "${" and Close "}" for ${ssm:/app/key} in Helm charts, or "<<" and ">>" for Jinja and Go templates.
+// Text between {{ }} is then left as it is. References in parameter values resolved with ResolveOptions.Recursive
+// keep the {{ }} delimiters.
+type PlaceholderSyntax struct {
+	Open  string
+	Close string
+}
+
+const defaultOpenDelimiter = "{{"
+const defaultCloseDelimiter = "}}"
+
+//
+// Stands for the {{ of a template with custom delimiters while it is resolved, so that it is not taken for a placeholder.
+// Characters of the Unicode private use area are not expected in templates.
+const escapedOpenDelimiter = "\uE000\uE000"
+
+//
+// Parameter references of every kind of placeholder, including the {{render:name}} placeholders of render sets
+var anyPlaceholderReference = "(?:" + strings.Join([]string{
+	ssmNonSecurePrefix + parameterNameWithSelector,
+	ssmSecurePrefix + parameterNameWithSelector,
+	secretsManagerPrefix + secretNameOrArn,
+	envPrefix + environmentVariableName,
+	renderPrefix + "[\\w./-]+",
+}, "|") + ")"
+
+// tells whether syntax sets delimiters other than {{ and }}
+func (syntax PlaceholderSyntax) isCustom() bool {
+	return (len(syntax.Open) > 0 || len(syntax.Close) > 0) && (syntax.Open != defaultOpenDelimiter || syntax.Close != defaultCloseDelimiter)
+}
+
+// rewrites the placeholders of text delimited according to syntax into {{ }} placeholders, so that text is resolved
+// like any template. Every {{ already in text is escaped first, restorePlaceholderDelimiters puts them back.
+func translatePlaceholderSyntax(text string, syntax PlaceholderSyntax) (string, error) {
+	if !syntax.isCustom() {
+		return text, nil
+	}
+
+	if len(strings.TrimSpace(syntax.Open)) == 0 || len(strings.TrimSpace(syntax.Close)) == 0 {
+		return "", withStatus(StatusParseError, errors.New("placeholder delimiters "+syntax.Open+" and "+syntax.Close+" are not valid"))
+	}
+
+	if strings.Contains(text, escapedOpenDelimiter) {
+		return "", withStatus(StatusParseError, errors.New("template holds characters reserved for escaping {{ with custom placeholder delimiters"))
+	}
+	text = strings.ReplaceAll(text, defaultOpenDelimiter, escapedOpenDelimiter)
+
+	translate := func(match string) string {
+		return defaultOpenDelimiter + match[len(syntax.Open):len(match)-len(syntax.Close)] + defaultCloseDelimiter
+	}
+
+	placeholder := placeholderPatterns.get(regexp.QuoteMeta(syntax.Open) + "\\s*" + anyPlaceholderReference + "\\s*" +
+		placeholderModifiers + regexp.QuoteMeta(syntax.Close))
+	text = placeholder.ReplaceAllStringFunc(text, translate)
+
+	// placeholders nested in parameter names are translated innermost first, one level at a time
+	nameTemplate := placeholderPatterns.get(regexp.QuoteMeta(syntax.Open) + "\\s*" + nameTemplateReference + "\\s*" +
+		placeholderModifiers + regexp.QuoteMeta(syntax.Close))
+	for depth := 0; depth < maxNameTemplateDepth && nameTemplate.MatchString(text); depth++ {
+		text = nameTemplate.ReplaceAllStringFunc(text, translate)
+	}
+
+	return text, nil
+}
+
+// puts back the {{ of resolved text escaped by translatePlaceholderSyntax
+func restorePlaceholderDelimiters(text string, syntax PlaceholderSyntax) string {
+	if !syntax.isCustom() {
+		return text
+	}
+
+	return strings.ReplaceAll(text, escapedOpenDelimiter, defaultOpenDelimiter)
+}
+
+// returns options resolving templates already translated by translatePlaceholderSyntax
+func withDefaultPlaceholderSyntax(options ResolveOptions) ResolveOptions {
+	options.PlaceholderSyntax = PlaceholderSyntax{}
+	return options
+}
+
+// fails for the APIs substituting placeholders line by line or document by document, which only know {{ }}
+func checkDefaultPlaceholderSyntax(options ResolveOptions, api string) error {
+	if options.PlaceholderSyntax.isCustom() {
+		return errors.New(api + " does not support custom placeholder delimiters")
+	}
+
+	return nil
+}
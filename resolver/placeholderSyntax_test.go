@@ -0,0 +1,94 @@
+package resolver
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newPlaceholderSyntaxServiceMock() ServiceMockedObjectWithRecords {
+	return NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host":        {Name: "/app/host", Type: stringType, Value: "db.internal"},
+		"ssm-secure:/app/pass": {Name: "/app/pass", Type: secureStringType, Value: "it's"},
+	})
+}
+
+func TestResolveParametersInTextWithCustomDelimiters(t *testing.T) {
+	serviceObject := newPlaceholderSyntaxServiceMock()
+	options := ResolveOptions{PlaceholderSyntax: PlaceholderSyntax{Open: "${", Close: "}"}}
+
+	input := "image: {{ .Values.image }}\nhost: ${ssm:/app/host}\npassword: ${ ssm-secure:/app/pass | shellquote }\n" +
+		"literal: {{ssm:/app/host}}"
+	resolved, err := ResolveParametersInText(&serviceObject, input, options)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "image: {{ .Values.image }}\nhost: db.internal\npassword: 'it'\\''s'\nliteral: {{ssm:/app/host}}", resolved)
+
+	values, err := ExtractValuesFromText(&serviceObject, input, options)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"ssm:/app/host": "db.internal", "ssm-secure:/app/pass": "it's"}, values)
+}
+
+func TestResolveParametersInFileWithCustomDelimiters(t *testing.T) {
+	serviceObject := newPlaceholderSyntaxServiceMock()
+	options := ResolveOptions{PlaceholderSyntax: PlaceholderSyntax{Open: "<<", Close: ">>"}}
+
+	dir := t.TempDir()
+	inputFileName := filepath.Join(dir, "app.j2")
+	outputFileName := filepath.Join(dir, "app.conf")
+	assert.Nil(t, os.WriteFile(inputFileName, []byte("{% if x %}host=<< ssm:/app/host >>{{ y }}{% endif %}"), 0600))
+
+	assert.Nil(t, ResolveParametersInFile(&serviceObject, inputFileName, outputFileName, options))
+
+	resolved, err := os.ReadFile(outputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, "{% if x %}host=db.internal{{ y }}{% endif %}", string(resolved))
+}
+
+func TestRenderSetWithCustomDelimiters(t *testing.T) {
+	serviceObject := newPlaceholderSyntaxServiceMock()
+
+	set := NewRenderSet(&serviceObject, ResolveOptions{PlaceholderSyntax: PlaceholderSyntax{Open: "${", Close: "}"}})
+	assert.Nil(t, set.Add("base", "host=${ssm:/app/host} {{ .Values.port }}"))
+	assert.Nil(t, set.Add("app", "${render:base}\n{{ .Values.name }}", "base"))
+	rendered, err := set.Render()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "host=db.internal {{ .Values.port }}\n{{ .Values.name }}", rendered["app"])
+}
+
+func TestInvalidCustomDelimiters(t *testing.T) {
+	serviceObject := newPlaceholderSyntaxServiceMock()
+
+	for _, syntax := range []PlaceholderSyntax{{Open: "${"}, {Open: " ", Close: " "}} {
+		_, err := ResolveParametersInText(&serviceObject, "${ssm:/app/host}", ResolveOptions{PlaceholderSyntax: syntax})
+		assert.NotNil(t, err)
+		assert.Equal(t, StatusParseError, StatusOf(err))
+	}
+
+	_, err := ResolveParametersInText(&serviceObject, "\uE000\uE000 ${ssm:/app/host}",
+		ResolveOptions{PlaceholderSyntax: PlaceholderSyntax{Open: "${", Close: "}"}})
+	assert.NotNil(t, err)
+}
+
+func TestCustomDelimitersNotSupported(t *testing.T) {
+	serviceObject := newPlaceholderSyntaxServiceMock()
+	options := ResolveOptions{PlaceholderSyntax: PlaceholderSyntax{Open: "${", Close: "}"}}
+
+	_, err := ResolveParametersInDockerfile(&serviceObject, "ENV HOST=${ssm:/app/host}", options)
+	assert.NotNil(t, err)
+
+	var output bytes.Buffer
+	err = ResolveParametersInStream(&serviceObject, strings.NewReader("${ssm:/app/host}"), &output, options)
+	assert.NotNil(t, err)
+
+	// {{ }} delimiters set explicitly are the default ones
+	resolved, err := ResolveParametersInDockerfile(&serviceObject, "ENV HOST={{ssm:/app/host}}",
+		ResolveOptions{PlaceholderSyntax: PlaceholderSyntax{Open: "{{", Close: "}}"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "ENV HOST=db.internal", resolved)
+}
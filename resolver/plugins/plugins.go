@@ -0,0 +1,43 @@
+//
+// Package plugins loads Go plugins registering extensions of the resolver, kept out of package resolver so that
+// its consumers are not linked with the plugin runtime.
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"plugin"
+)
+
+//
+// Function a Go plugin loaded by Load exports to register its extensions, with the signature func() error
+const pluginRegisterFunction = "RegisterResolverExtensions"
+
+//
+// Loads the Go plugin (.so) at path, built with go build -buildmode=plugin, and calls its RegisterResolverExtensions
+// function, which registers its sources, transformers, formats and secret sinks with RegisterSource,
+// RegisterTransformer, RegisterFormat and RegisterSecretSink of package resolver. The plugin has to be built with
+// the same Go toolchain and the same version of package resolver as the program. Plugins are only supported
+// on some platforms, e.g. Linux.
+func Load(path string) error {
+	extension, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot open plugin %s: %w", path, err)
+	}
+
+	symbol, err := extension.Lookup(pluginRegisterFunction)
+	if err != nil {
+		return fmt.Errorf("cannot load plugin %s: %w", path, err)
+	}
+
+	register, ok := symbol.(func() error)
+	if !ok {
+		return errors.New("cannot load plugin " + path + ": " + pluginRegisterFunction + " is not a func() error")
+	}
+
+	if err := register(); err != nil {
+		return fmt.Errorf("cannot register extensions of plugin %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package plugins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFailsForMissingPlugin(t *testing.T) {
+	assert.NotNil(t, Load("testdata/missing.so"))
+}
@@ -0,0 +1,49 @@
+package resolver
+
+import (
+	"bytes"
+	"sync"
+)
+
+//
+// Buffers larger than this are dropped instead of being returned to the pool,
+// so that one huge document does not pin its memory for the lifetime of the process
+const maxPooledBufferSize = 1024 * 1024
+
+//
+// Maps larger than this are dropped instead of being returned to the pool
+const maxPooledMapSize = 4096
+
+var substitutionBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+var dedupScratchMaps = sync.Pool{
+	New: func() interface{} { return map[string]bool{} },
+}
+
+func getSubstitutionBuffer() *bytes.Buffer {
+	return substitutionBuffers.Get().(*bytes.Buffer)
+}
+
+func putSubstitutionBuffer(buffer *bytes.Buffer) {
+	if buffer.Cap() > maxPooledBufferSize {
+		return
+	}
+	buffer.Reset()
+	substitutionBuffers.Put(buffer)
+}
+
+func getDedupScratchMap() map[string]bool {
+	return dedupScratchMaps.Get().(map[string]bool)
+}
+
+func putDedupScratchMap(scratch map[string]bool) {
+	if len(scratch) > maxPooledMapSize {
+		return
+	}
+	for key := range scratch {
+		delete(scratch, key)
+	}
+	dedupScratchMaps.Put(scratch)
+}
@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+//
+// Post-render filter reducing the size of resolved files, e.g. on constrained devices
+type PostRenderFilter struct {
+	// Shell pattern (see filepath.Match) matched against the base name of the output file,
+	// the filter applies to every file when empty
+	FilePattern string
+
+	// Remove lines whose first non-blank character is #
+	StripComments bool
+
+	// Replace runs of blank lines with a single blank line
+	CollapseBlankLines bool
+
+	// Remove spaces and tabs at the end of lines
+	TrimTrailingWhitespace bool
+}
+
+// applies the filters whose pattern matches fileName to text
+func applyPostRenderFilters(fileName string, text string, filters []PostRenderFilter) (string, error) {
+	for _, filter := range filters {
+		if len(filter.FilePattern) > 0 {
+			matched, err := filepath.Match(filter.FilePattern, filepath.Base(fileName))
+			if err != nil {
+				return "", fmt.Errorf("invalid post-render filter pattern %s: %w", filter.FilePattern, err)
+			}
+			if !matched {
+				continue
+			}
+		}
+
+		text = filter.apply(text)
+	}
+
+	return text, nil
+}
+
+func (f PostRenderFilter) apply(text string) string {
+	lines := []string{}
+	previousBlank := false
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if f.StripComments && strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if f.CollapseBlankLines {
+			blank := len(trimmed) == 0
+			if blank && previousBlank {
+				continue
+			}
+			previousBlank = blank
+		}
+
+		if f.TrimTrailingWhitespace {
+			line = strings.TrimRight(line, " \t\r")
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
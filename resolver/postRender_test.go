@@ -0,0 +1,26 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPostRenderFilters(t *testing.T) {
+	text := "# header\nkey = value   \n\n\n  # indented comment\nother = x # inline comment\n\n"
+
+	output, err := applyPostRenderFilters("/etc/app/app.conf", text, []PostRenderFilter{
+		{FilePattern: "*.conf", StripComments: true},
+		{CollapseBlankLines: true, TrimTrailingWhitespace: true},
+		{FilePattern: "*.yaml", StripComments: true, CollapseBlankLines: true},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "key = value\n\nother = x # inline comment\n", output)
+}
+
+func TestApplyPostRenderFiltersInvalidPattern(t *testing.T) {
+	_, err := applyPostRenderFilters("app.conf", "text", []PostRenderFilter{{FilePattern: "[", StripComments: true}})
+
+	assert.NotNil(t, err)
+}
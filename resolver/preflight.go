@@ -0,0 +1,179 @@
+package resolver
+
+import (
+	"context"
+	"sort"
+)
+
+//
+// Result of the checks of one parameter reference by Preflight
+type PreflightResult struct {
+	Reference string
+	Type      string
+	DataType  string
+
+	// Why the reference cannot be resolved (missing parameter, denied access, wrong prefix or data type),
+	// nil when it passed
+	Err error
+}
+
+//
+// Result of Preflight
+type PreflightReport struct {
+	// Results of all the references, sorted by reference
+	References []PreflightResult
+
+	// Errors of the templates passed to Preflight by index, nil for the templates that passed
+	TemplateErrors []error
+}
+
+//
+// Reports whether every reference and template passed.
+func (report PreflightReport) Passed() bool {
+	for _, result := range report.References {
+		if result.Err != nil {
+			return false
+		}
+	}
+
+	for _, err := range report.TemplateErrors {
+		if err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+// Checks that every parameter reference a binary will need, listed in parameterReferences or found in templates,
+// exists, is accessible with the current credentials and has the type its prefix declares, and that the placeholders
+// of templates are valid and satisfied by the fetched values (transformers and constraints included).
+// Meant to run at boot, before accepting traffic: all the checks are run and reported together.
+// The returned error is set only when ctx is done before the checks complete.
+func Preflight(
+	ctx context.Context,
+	service ISsmParameterService,
+	parameterReferences []string,
+	templates []string,
+	options ResolveOptions) (PreflightReport, error) {
+
+	report := PreflightReport{
+		References:     []PreflightResult{},
+		TemplateErrors: make([]error, len(templates)),
+	}
+
+	allReferences := append([]string{}, parameterReferences...)
+	translatedTemplates := make([]string, len(templates))
+	for i, template := range templates {
+		translatedTemplates[i], report.TemplateErrors[i] = prepareTemplate(ctx, service, template, options)
+		if report.TemplateErrors[i] != nil {
+			continue
+		}
+
+		templateReferences, err := parseAndValidatePlaceholders(translatedTemplates[i], options)
+		if err != nil {
+			report.TemplateErrors[i] = err
+			continue
+		}
+		allReferences = append(allReferences, templateReferences...)
+	}
+	allReferences = dedupSlice(allReferences)
+	sort.Strings(allReferences)
+
+	results, resolvedParametersMap, err := preflightReferences(ctx, service, allReferences, options, false)
+	report.References = append(report.References, results...)
+	if err != nil {
+		return report, err
+	}
+
+	for i, template := range translatedTemplates {
+		if report.TemplateErrors[i] == nil {
+			_, report.TemplateErrors[i] = replaceParameterPlaceholders(template, resolvedParametersMap)
+		}
+	}
+
+	return report, nil
+}
+
+// checks the sorted references by batches of maxParametersRetrievedFromSsm, each fetched from the source of its prefix.
+// Only the metadata of SSM parameters is fetched when metadataOnly is set, their values are then left unchecked.
+// It returns the results and the parameters of the references that passed, the error is set when ctx is done.
+func preflightReferences(
+	ctx context.Context,
+	service ISsmParameterService,
+	allReferences []string,
+	options ResolveOptions,
+	metadataOnly bool) ([]PreflightResult, map[string]SsmParameterInfo, error) {
+
+	if metadataOnly {
+		service = metadataOnlyService{service}
+	}
+
+	results := []PreflightResult{}
+	resolvedParametersMap := map[string]SsmParameterInfo{}
+	for start := 0; start < len(allReferences); start += maxParametersRetrievedFromSsm {
+		if err := ctx.Err(); err != nil {
+			return results, resolvedParametersMap, err
+		}
+
+		end := start + maxParametersRetrievedFromSsm
+		if end > len(allReferences) {
+			end = len(allReferences)
+		}
+
+		batchResults, batchParameters := preflightBatch(ctx, service, allReferences[start:end], options, metadataOnly)
+		results = append(results, batchResults...)
+		for ref, param := range batchParameters {
+			resolvedParametersMap[ref] = param
+		}
+	}
+
+	return results, resolvedParametersMap, nil
+}
+
+// checks a batch of references with one request, falling back to one request per reference when the batch fails
+// to tell which references fail. It returns the results and the parameters of the references that passed.
+func preflightBatch(
+	ctx context.Context,
+	service ISsmParameterService,
+	batch []string,
+	options ResolveOptions,
+	metadataOnly bool) ([]PreflightResult, map[string]SsmParameterInfo) {
+
+	results := []PreflightResult{}
+	passedParameters := map[string]SsmParameterInfo{}
+
+	resolvedParametersMap, err := getParametersFromServices(ctx, service, append([]string{}, batch...), options)
+	if err != nil && len(batch) > 1 {
+		for _, ref := range batch {
+			refResults, refParameters := preflightBatch(ctx, service, []string{ref}, options, metadataOnly)
+			results = append(results, refResults...)
+			for passedRef, param := range refParameters {
+				passedParameters[passedRef] = param
+			}
+		}
+		return results, passedParameters
+	}
+
+	for _, ref := range batch {
+		result := PreflightResult{Reference: ref, Err: err}
+		if param, found := resolvedParametersMap[ref]; found {
+			single := map[string]SsmParameterInfo{ref: param}
+			result.Type = param.Type
+			result.DataType = param.DataType
+			result.Err = validateParameterReferencePrefix(&single)
+			if result.Err == nil && !metadataOnly {
+				result.Err = validateParameterDataTypes(single)
+			}
+			if result.Err == nil {
+				passedParameters[ref] = param
+			}
+		} else if result.Err == nil {
+			result.Err = newMissingParametersError([]string{extractParameterNameFromReference(ref)})
+		}
+		results = append(results, result)
+	}
+
+	return results, passedParameters
+}
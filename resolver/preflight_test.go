@@ -0,0 +1,56 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreflight(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host":          {Name: "/app/host", Type: stringType, Value: "db.internal"},
+		"ssm:/app/port":          {Name: "/app/port", Type: stringType, Value: "5432"},
+		"ssm:/app/password":      {Name: "/app/password", Type: secureStringType, Value: "s3cr3t"},
+		"ssm-secure:/app/apiKey": {Name: "/app/apiKey", Type: secureStringType, Value: "key"},
+	})
+
+	report, err := Preflight(context.Background(), &serviceObject,
+		[]string{"ssm:/app/host", "ssm:/app/password", "ssm:/app/missing"},
+		[]string{"{{ssm:/app/port | type=int}} {{ssm-secure:/app/apiKey}}", "{{ssm:/app/host | type=int}}"},
+		ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.False(t, report.Passed())
+
+	failed := map[string]bool{}
+	for _, result := range report.References {
+		failed[result.Reference] = result.Err != nil
+	}
+	assert.Equal(t, map[string]bool{
+		"ssm-secure:/app/apiKey": false,
+		"ssm:/app/host":          false,
+		"ssm:/app/missing":       true,
+		"ssm:/app/password":      true,
+		"ssm:/app/port":          false,
+	}, failed)
+
+	assert.Len(t, report.TemplateErrors, 2)
+	assert.Nil(t, report.TemplateErrors[0])
+	assert.NotNil(t, report.TemplateErrors[1])
+}
+
+func TestPreflightPassed(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	report, err := Preflight(context.Background(), &serviceObject, []string{"ssm:/app/host"}, nil, ResolveOptions{})
+	assert.Nil(t, err)
+	assert.True(t, report.Passed())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = Preflight(ctx, &serviceObject, []string{"ssm:/app/host"}, nil, ResolveOptions{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,20 @@
+package resolver
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+//
+// Values of the phase pprof label
+const parsePhase = "parse"
+const fetchPhase = "fetch"
+const substitutePhase = "substitute"
+
+//
+// Runs f with the documentID and phase pprof labels added to the labels of ctx, set on the current goroutine
+// (and inherited by the goroutines it starts), so that CPU and goroutine profiles of embedding services attribute
+// time to renders. f gets ctx carrying the labels, for the work it hands over to other goroutines.
+func doWithProfilerLabels(ctx context.Context, documentID string, phase string, f func(ctx context.Context)) {
+	pprof.Do(ctx, pprof.Labels("documentID", documentID, "phase", phase), f)
+}
@@ -0,0 +1,24 @@
+package resolver
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfilerLabelsKeepTheLabelsOfTheCaller(t *testing.T) {
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("request", "42"))
+
+	called := false
+	doWithProfilerLabels(ctx, "app.conf", fetchPhase, func(ctx context.Context) {
+		called = true
+		for key, expected := range map[string]string{"request": "42", "documentID": "app.conf", "phase": fetchPhase} {
+			value, found := pprof.Label(ctx, key)
+			assert.True(t, found, key)
+			assert.Equal(t, expected, value)
+		}
+	})
+	assert.True(t, called)
+}
@@ -0,0 +1,63 @@
+package resolver
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// escapes value the way java.util.Properties.store does: backslashes, control characters, the = : # ! separators
+// and comment characters are escaped with a backslash and characters outside printable ASCII become \uXXXX.
+// A leading space is escaped when the value starts right after the key separator, where it would be trimmed.
+func escapePropertiesValue(template string, offset int, value string) (string, error) {
+	var escaped strings.Builder
+
+	linePrefix := strings.TrimRight(template[strings.LastIndex(template[:offset], "\n")+1:offset], " \t")
+	atValueStart := len(linePrefix) == 0 || strings.HasSuffix(linePrefix, "=") || strings.HasSuffix(linePrefix, ":")
+
+	for i, r := range value {
+		switch {
+		case r == ' ' && i == 0 && atValueStart:
+			escaped.WriteString("\\ ")
+		case r == '\\':
+			escaped.WriteString("\\\\")
+		case r == '\t':
+			escaped.WriteString("\\t")
+		case r == '\n':
+			escaped.WriteString("\\n")
+		case r == '\r':
+			escaped.WriteString("\\r")
+		case r == '\f':
+			escaped.WriteString("\\f")
+		case r == '=' || r == ':' || r == '#' || r == '!':
+			escaped.WriteRune('\\')
+			escaped.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			for _, unit := range utf16.Encode([]rune{r}) {
+				escaped.WriteString(fmt.Sprintf("\\u%04X", unit))
+			}
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+
+	return escaped.String(), nil
+}
+
+//
+// Escapes of INI values: backslashes, quotes, line breaks and tabs, the = key separator
+// and the ; and # comment characters are escaped with a backslash
+var iniEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"\"", "\\\"",
+	"\n", "\\n",
+	"\r", "\\r",
+	"\t", "\\t",
+	"=", "\\=",
+	";", "\\;",
+	"#", "\\#",
+)
+
+func escapeIniValue(template string, offset int, value string) (string, error) {
+	return iniEscaper.Replace(value), nil
+}
@@ -0,0 +1,59 @@
+package resolver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapePropertiesValue(t *testing.T) {
+	testCases := []struct {
+		template string
+		value    string
+		expected string
+	}{
+		{"jaas={{ssm:x}}", `org.Module required password="a=b:c";`, `org.Module required password\="a\=b\:c";`},
+		{"path={{ssm:x}}", `C:\kafka\ssl`, `C\:\\kafka\\ssl`},
+		{"name = {{ssm:x}}", " Zoë 😀", `\ Zo\u00EB \uD83D\uDE00`},
+		{"motd=Hello{{ssm:x}}", " line1\nline2", ` line1\nline2`},
+	}
+
+	for _, testCase := range testCases {
+		escaped, err := escapePropertiesValue(testCase.template, len(testCase.template)-len("{{ssm:x}}"), testCase.value)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expected, escaped, testCase.template)
+	}
+}
+
+func TestResolveParametersInPropertiesAndIniFiles(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm-secure:/kafka/jaas": {Name: "/kafka/jaas", Type: secureStringType, Value: `required username="app" password="p=w;#1";`},
+	})
+
+	dir, err := ioutil.TempDir("", "formats")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "client.template")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("sasl.jaas.config={{ssm-secure:/kafka/jaas}}"), 0644))
+
+	propertiesFileName := filepath.Join(dir, "client.properties")
+	assert.Nil(t, ResolveParametersInFile(&serviceObject, inputFileName, propertiesFileName, ResolveOptions{}))
+	output, err := ioutil.ReadFile(propertiesFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, `sasl.jaas.config=required username\="app" password\="p\=w;\#1";`, string(output))
+
+	iniFileName := filepath.Join(dir, "client.ini")
+	assert.Nil(t, ResolveParametersInFile(&serviceObject, inputFileName, iniFileName, ResolveOptions{}))
+	output, err = ioutil.ReadFile(iniFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, `sasl.jaas.config=required username\=\"app\" password\=\"p\=w\;\#1\"\;`, string(output))
+
+	text, err := ResolveParametersInText(&serviceObject, "{{ssm-secure:/kafka/jaas}}", ResolveOptions{Format: "hcl"})
+	assert.NotNil(t, err)
+	assert.Equal(t, StatusParseError, StatusOf(err))
+	assert.Equal(t, "{{ssm-secure:/kafka/jaas}}", text)
+}
@@ -0,0 +1,84 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+//
+// Path of the public parameters AWS publishes to every account, e.g. /aws/service/ami-amazon-linux-latest/...
+const publicParameterPath = "/aws/service/"
+
+//
+// IPublicParameterService is implemented by the services fetching public parameters with GetParameter
+type IPublicParameterService interface {
+	callGetPublicParameter(ctx context.Context, parameterReference string) (SsmParameterInfo, error)
+}
+
+//
+// Retrieves the public parameter of parameterReference with GetParameter, which is not restricted to the parameters
+// of the account like GetParameters batches are checked to be.
+func (s *Service) callGetPublicParameter(ctx context.Context, parameterReference string) (SsmParameterInfo, error) {
+	name := extractParameterNameFromReference(parameterReference)
+	output, err := s.SSMClient.GetParameterWithContext(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		var awsError interface{ Code() string }
+		if errors.As(err, &awsError) && awsError.Code() == ssm.ErrCodeParameterNotFound {
+			return SsmParameterInfo{}, newMissingParametersError([]string{name})
+		}
+		return SsmParameterInfo{}, withStatus(StatusAwsError, err)
+	}
+
+	return newSsmParameterInfo(output.Parameter), nil
+}
+
+// tells whether parameterReference refers to a public parameter published by AWS
+func isPublicParameterReference(parameterReference string) bool {
+	return isSsmReference(parameterReference) &&
+		strings.HasPrefix(extractParameterNameFromReference(parameterReference), publicParameterPath)
+}
+
+// fails for the public parameter references unless AllowPublicParameters is set
+func checkPublicParameterReferences(publicReferences []string, options ResolveOptions) error {
+	if len(publicReferences) == 0 || options.AllowPublicParameters {
+		return nil
+	}
+
+	placeholders := make([]string, len(publicReferences))
+	for i, ref := range publicReferences {
+		placeholders[i] = "{{" + ref + "}}"
+	}
+	sort.Strings(placeholders)
+
+	return withStatus(StatusPolicyViolation, errors.New("the following parameter reference(s) are public parameters "+
+		"published by AWS, set AllowPublicParameters to resolve them: "+strings.Join(placeholders, ",")))
+}
+
+// retrieves the public parameter of parameterReference with service, with a GetParameters request of its own when
+// service does not implement IPublicParameterService. Services wrapping another service delegate their
+// callGetPublicParameter to it with this function.
+func getPublicParameter(ctx context.Context, service ISsmParameterService, parameterReference string) (SsmParameterInfo, error) {
+	if publicService, ok := service.(IPublicParameterService); ok {
+		return publicService.callGetPublicParameter(ctx, parameterReference)
+	}
+
+	parameters, err := service.callGetParameters(ctx, []string{parameterReference})
+	if err != nil {
+		return SsmParameterInfo{}, err
+	}
+
+	param, found := parameters[parameterReference]
+	if !found {
+		return SsmParameterInfo{}, newMissingParametersError([]string{extractParameterNameFromReference(parameterReference)})
+	}
+
+	return param, nil
+}
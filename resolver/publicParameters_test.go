@@ -0,0 +1,42 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicParameters(t *testing.T) {
+	const ami = "/aws/service/ami-amazon-linux-latest/al2023-ami-kernel-6.1-x86_64"
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:" + ami: {Name: ami, Type: "String", Value: "ami-0123456789abcdef0"},
+	})
+
+	text := "image_id={{ssm:" + ami + "}}"
+	_, err := ResolveParametersInText(&serviceObject, text, ResolveOptions{})
+	assert.NotNil(t, err)
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+
+	output, err := ResolveParametersInText(&serviceObject, text, ResolveOptions{AllowPublicParameters: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "image_id=ami-0123456789abcdef0", output)
+}
+
+func TestMissingPublicParameters(t *testing.T) {
+	serviceObject := newMissingParametersService()
+
+	text := "{{ssm:/aws/service/global-infrastructure/regions/xx-nowhere-1/longName}}"
+	_, err := ResolveParametersInText(serviceObject, text, ResolveOptions{AllowPublicParameters: true})
+	assert.NotNil(t, err)
+
+	output, err := ResolveParametersInText(serviceObject, text,
+		ResolveOptions{AllowPublicParameters: true, IgnoreMissingParameters: true})
+	assert.Nil(t, err)
+	assert.Equal(t, text, output)
+}
+
+func TestIsPublicParameterReference(t *testing.T) {
+	assert.True(t, isPublicParameterReference("ssm:/aws/service/bottlerocket/aws-k8s-1.29/x86_64/latest/image_id"))
+	assert.False(t, isPublicParameterReference("ssm:/app/aws/service/key"))
+	assert.False(t, isPublicParameterReference("env:AWS_REGION"))
+}
@@ -0,0 +1,140 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//
+// Maximum depth of placeholders nested in parameter values resolved in recursive mode
+const maxRecursiveResolutionDepth = 10
+
+//
+// Fetches the parameters referenced by placeholders in the values of resolvedParametersMap (and so on, up to
+// maxRecursiveResolutionDepth levels) and substitutes them into the values.
+// It returns a map of all (parameter reference) to SsmParameterInfo with fully resolved values, including
+// the references found in values only.
+func resolveNestedParameters(
+	ctx context.Context,
+	service ISsmParameterService,
+	resolvedParametersMap map[string]SsmParameterInfo,
+	options ResolveOptions) (map[string]SsmParameterInfo, error) {
+
+	dependencies := map[string][]string{}
+	pending := resolvedParametersMap
+
+	for depth := 0; len(pending) > 0; depth++ {
+		if depth >= maxRecursiveResolutionDepth {
+			return nil, errors.New("parameter values are nested deeper than " + strconv.Itoa(maxRecursiveResolutionDepth) + " levels")
+		}
+
+		missingReferences := []string{}
+		for ref, param := range pending {
+			nestedReferences, err := parseAndValidatePlaceholders(param.Value, options)
+			if err != nil {
+				return nil, newParameterReferenceError(ref, "invalid placeholder in the value of parameter reference {{%s}}: %w", err)
+			}
+
+			dependencies[ref] = nestedReferences
+			for _, nestedRef := range nestedReferences {
+				if _, contains := resolvedParametersMap[nestedRef]; !contains {
+					missingReferences = append(missingReferences, nestedRef)
+				}
+			}
+		}
+
+		nestedParameters, err := fetchParameters(ctx, service, dedupSlice(missingReferences), options, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		err = validateParameterReferencePrefix(&nestedParameters)
+		if err != nil {
+			return nil, err
+		}
+
+		err = validateParameterDataTypes(nestedParameters)
+		if err != nil {
+			return nil, err
+		}
+
+		for ref, param := range nestedParameters {
+			resolvedParametersMap[ref] = param
+		}
+
+		refetchedReferences, err := reconcileParameterVersions(ctx, service, resolvedParametersMap, options)
+		if err != nil {
+			return nil, err
+		}
+
+		// values fetched again can reference other parameters than before
+		pending = nestedParameters
+		for _, ref := range refetchedReferences {
+			pending[ref] = resolvedParametersMap[ref]
+		}
+	}
+
+	// values nesting placeholders cannot grow beyond the limit relative to the values they are built from
+	sourceSize := 0
+	for _, param := range resolvedParametersMap {
+		sourceSize += len(param.Value)
+	}
+	maxValueSize := maxExpandedSize(effectiveLimits(options), sourceSize)
+
+	expanded := map[string]SsmParameterInfo{}
+	for ref := range resolvedParametersMap {
+		if _, err := expandParameterValue(ref, resolvedParametersMap, dependencies, expanded, []string{}, maxValueSize); err != nil {
+			return nil, err
+		}
+	}
+
+	return expanded, nil
+}
+
+// substitutes the (expanded) values of its dependencies into the value of ref, failing on cycles
+// and on values larger than maxValueSize when it is not 0
+func expandParameterValue(
+	ref string,
+	resolvedParametersMap map[string]SsmParameterInfo,
+	dependencies map[string][]string,
+	expanded map[string]SsmParameterInfo,
+	path []string,
+	maxValueSize int) (SsmParameterInfo, error) {
+
+	if param, done := expanded[ref]; done {
+		return param, nil
+	}
+
+	for i, visited := range path {
+		if visited == ref {
+			return SsmParameterInfo{}, errors.New("cyclic parameter references: " + strings.Join(append(path[i:], ref), " -> "))
+		}
+	}
+	path = append(path, ref)
+
+	dependencyValues := map[string]SsmParameterInfo{}
+	for _, dependency := range dependencies[ref] {
+		param, err := expandParameterValue(dependency, resolvedParametersMap, dependencies, expanded, path, maxValueSize)
+		if err != nil {
+			return SsmParameterInfo{}, err
+		}
+		dependencyValues[dependency] = param
+	}
+
+	param := resolvedParametersMap[ref]
+	value, err := replaceParameterPlaceholders(param.Value, dependencyValues)
+	if err != nil {
+		return SsmParameterInfo{}, err
+	}
+
+	err = checkExpandedSize(maxValueSize, len(value), "value of parameter reference {{"+ref+"}}")
+	if err != nil {
+		return SsmParameterInfo{}, err
+	}
+	param.Value = value
+
+	expanded[ref] = param
+	return param, nil
+}
@@ -0,0 +1,58 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersInTextRecursive(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/url":     {Name: "/app/url", Type: stringType, Value: "https://{{ssm:/app/host}}:{{ssm:/app/port}}"},
+		"ssm:/app/host":    {Name: "/app/host", Type: stringType, Value: "{{ssm:/env/domain}}"},
+		"ssm:/app/port":    {Name: "/app/port", Type: stringType, Value: "443"},
+		"ssm:/env/domain":  {Name: "/env/domain", Type: stringType, Value: "example.com"},
+		"ssm:/unreachable": {Name: "/unreachable", Type: stringType, Value: "{{ssm:/unreachable}}"},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject, "url={{ssm:/app/url}} port={{ssm:/app/port}}", ResolveOptions{
+		Recursive: true,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "url=https://example.com:443 port=443", output)
+}
+
+func TestResolveParametersInTextNotRecursiveByDefault(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "{{ssm:/env/domain}}"},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/host}}", ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "{{ssm:/env/domain}}", output)
+}
+
+func TestResolveParametersInTextRecursiveSelfReference(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/loop": {Name: "/app/loop", Type: stringType, Value: "again {{ssm:/app/loop}}"},
+	})
+
+	_, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/loop}}", ResolveOptions{Recursive: true})
+
+	assert.EqualError(t, err, "cyclic parameter references: ssm:/app/loop -> ssm:/app/loop")
+}
+
+func TestResolveParametersInTextRecursiveCycle(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/a": {Name: "/a", Type: stringType, Value: "{{ssm:/b}}"},
+		"ssm:/b": {Name: "/b", Type: stringType, Value: "{{ssm:/c}}"},
+		"ssm:/c": {Name: "/c", Type: stringType, Value: "{{ssm:/a}}"},
+	})
+
+	_, err := ResolveParametersInText(&serviceObject, "{{ssm:/a}}", ResolveOptions{Recursive: true})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cyclic parameter references")
+}
@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"container/list"
+	"regexp"
+	"sync"
+)
+
+//
+// Maximum number of compiled per-reference placeholder patterns kept in memory
+const maxCachedPlaceholderPatterns = 1024
+
+//
+// Compiled per-reference placeholder patterns shared by all resolve calls
+var placeholderPatterns = newRegexpCache(maxCachedPlaceholderPatterns)
+
+//
+// Concurrent-safe LRU cache of compiled regular expressions
+type regexpCache struct {
+	mutex    sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type regexpCacheEntry struct {
+	pattern string
+	regexp  *regexp.Regexp
+}
+
+func newRegexpCache(capacity int) *regexpCache {
+	return &regexpCache{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// returns the compiled pattern, compiling it and evicting the least recently used one on a cache miss
+func (c *regexpCache) get(pattern string) *regexp.Regexp {
+	compiled, err := c.compile(pattern)
+	if err != nil {
+		panic("regexp: Compile(" + pattern + "): " + err.Error())
+	}
+
+	return compiled
+}
+
+// same as get, but returns an error instead of panicking when the pattern does not compile
+func (c *regexpCache) compile(pattern string) (*regexp.Regexp, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, contains := c.entries[pattern]; contains {
+		c.order.MoveToFront(element)
+		return element.Value.(*regexpCacheEntry).regexp, nil
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	c.entries[pattern] = c.order.PushFront(&regexpCacheEntry{pattern: pattern, regexp: compiled})
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*regexpCacheEntry).pattern)
+	}
+
+	return compiled, nil
+}
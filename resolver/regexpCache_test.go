@@ -0,0 +1,47 @@
+package resolver
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegexpCacheReusesCompiledPatterns(t *testing.T) {
+	cache := newRegexpCache(2)
+
+	first := cache.get("a+")
+	assert.True(t, first == cache.get("a+"))
+	assert.True(t, first.MatchString("aaa"))
+}
+
+func TestRegexpCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newRegexpCache(2)
+
+	a := cache.get("a")
+	b := cache.get("b")
+	cache.get("a")
+	cache.get("c")
+
+	assert.Len(t, cache.entries, 2)
+	assert.True(t, a == cache.get("a"))
+	assert.False(t, b == cache.get("b"))
+}
+
+func TestRegexpCacheConcurrentAccess(t *testing.T) {
+	cache := newRegexpCache(4)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for _, pattern := range []string{"a", "b", "c", "d", "e"} {
+				assert.True(t, cache.get(pattern).MatchString(pattern))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, cache.entries, 4)
+}
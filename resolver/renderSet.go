@@ -0,0 +1,527 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const renderPrefix = "render:"
+
+//
+// Placeholder including the resolved output of another document of a RenderSet, e.g. {{render:base.conf}}
+var renderPlaceholder = regexp.MustCompile("{{\\s*" + renderPrefix + "([\\w./-]+)\\s*}}")
+
+//
+// RenderSet resolves a set of documents where a document can include the resolved output of the documents
+// it depends on with {{render:name}} placeholders. The parameters referenced by all documents are fetched once
+// and shared, then documents are rendered in dependency order, independent documents concurrently.
+// All documents of one execution observe the same version of every parameter, nested references included.
+type RenderSet struct {
+	// Maximum number of documents rendered at the same time, 1 when not positive
+	MaxConcurrency int
+
+	service   ISsmParameterService
+	options   ResolveOptions
+	documents map[string]*RenderSetDocument
+}
+
+//
+// Document registered in a RenderSet
+type RenderSetDocument struct {
+	Name     string
+	Template string
+
+	// Names of the documents whose output this document includes with {{render:name}} placeholders
+	Dependencies []string
+
+	// Options of this document, the options of the RenderSet are used when nil
+	Options *ResolveOptions
+}
+
+//
+// Consolidated result of RenderSet.Execute
+type RenderReport struct {
+	Documents map[string]*DocumentRenderReport
+
+	// Number of distinct parameter references fetched for the whole set
+	FetchedReferences int
+
+	// Version of every parameter observed by the documents, keyed by parameter name, with the selector
+	// for references selecting a version or a label, e.g. /app/key:3 or /app/key:prod.
+	// Secrets and environment variables are left out.
+	ParameterVersions map[string]int64
+}
+
+//
+// Result of rendering one document of a RenderSet
+type DocumentRenderReport struct {
+	Output string
+
+	// Parameter references used by the document template
+	References []string
+
+	Duration time.Duration
+
+	// Why the document could not be rendered, nil on success
+	Err error
+}
+
+func NewRenderSet(service ISsmParameterService, options ResolveOptions) *RenderSet {
+	return &RenderSet{
+		service:   service,
+		options:   options,
+		documents: map[string]*RenderSetDocument{},
+	}
+}
+
+//
+// Registers a document with the options of the RenderSet.
+func (r *RenderSet) Add(name string, template string, dependencies ...string) error {
+	return r.Register(RenderSetDocument{
+		Name:         name,
+		Template:     template,
+		Dependencies: dependencies,
+	})
+}
+
+//
+// Registers a document. Every {{render:name}} placeholder in its template has to name one of its dependencies,
+// render placeholders use the delimiters of ResolveOptions.PlaceholderSyntax too.
+func (r *RenderSet) Register(document RenderSetDocument) error {
+	if len(document.Name) == 0 {
+		return errors.New("document name is not provided")
+	}
+
+	if _, exists := r.documents[document.Name]; exists {
+		return errors.New("document " + document.Name + " is already registered")
+	}
+
+	syntax := r.options.PlaceholderSyntax
+	if document.Options != nil {
+		syntax = document.Options.PlaceholderSyntax
+	}
+
+	var err error
+	document.Template, err = translatePlaceholderSyntax(document.Template, syntax)
+	if err != nil {
+		return fmt.Errorf("cannot register document %s: %w", document.Name, err)
+	}
+
+	for _, match := range renderPlaceholder.FindAllStringSubmatch(document.Template, -1) {
+		if !containsString(document.Dependencies, match[1]) {
+			return errors.New("document " + document.Name + " includes " + match[1] + " which is not declared as its dependency")
+		}
+	}
+
+	r.documents[document.Name] = &document
+	return nil
+}
+
+//
+// Resolves all documents and returns a map of (document name) to resolved document.
+func (r *RenderSet) Render() (map[string]string, error) {
+	report, err := r.Execute(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := map[string]string{}
+	for name, documentReport := range report.Documents {
+		rendered[name] = documentReport.Output
+	}
+
+	return rendered, nil
+}
+
+//
+// Fetches the parameters of all documents once, then renders the documents in dependency order running at most
+// MaxConcurrency of them at the same time. A document whose dependency failed is not rendered.
+// The report covers every document; the returned error is the first failure in dependency order, if any,
+// with StatusPartial when other documents were rendered.
+func (r *RenderSet) Execute(ctx context.Context) (*RenderReport, error) {
+	order, err := r.dependencyOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RenderReport{
+		Documents:         map[string]*DocumentRenderReport{},
+		ParameterVersions: map[string]int64{},
+	}
+	snapshot := &renderSetSnapshot{service: r.service, parameters: map[string]SsmParameterInfo{}}
+
+	// documents with the placeholders nested in parameter names substituted, the parameters of the names are part
+	// of the snapshot too
+	documents := map[string]*RenderSetDocument{}
+	allTemplates := []string{}
+	allReferences := []string{}
+	for _, name := range order {
+		document := *r.documents[name]
+		document.Template, err = expandParameterNameTemplates(ctx, snapshot, document.Template, r.documentOptions(name))
+		if err != nil {
+			return nil, fmt.Errorf("cannot render document %s: %w", name, err)
+		}
+		documents[name] = &document
+
+		references, err := parseAndValidatePlaceholders(document.Template, r.documentOptions(name))
+		if err != nil {
+			return nil, fmt.Errorf("cannot render document %s: %w", name, err)
+		}
+		report.Documents[name] = &DocumentRenderReport{References: references}
+		allTemplates = append(allTemplates, document.Template)
+		allReferences = append(allReferences, references...)
+	}
+
+	uniqueReferences := dedupSlice(allReferences)
+	report.FetchedReferences = len(uniqueReferences)
+
+	fetchedParameters, err := fetchParametersOrDefaults(ctx, r.service, uniqueReferences, r.options, placeholderMaxAges(allTemplates...),
+		placeholderDefaults(allTemplates...))
+	if err != nil {
+		return report, err
+	}
+
+	_, err = reconcileParameterVersions(ctx, r.service, fetchedParameters, r.options)
+	if err != nil {
+		return report, err
+	}
+
+	sinks, err := placeholderSinks(allTemplates...)
+	if err != nil {
+		return report, err
+	}
+	fetchedParameters, err = deliverSecrets(ctx, fetchedParameters, sinks, r.options)
+	if err != nil {
+		return report, err
+	}
+	snapshot.add(fetchedParameters)
+
+	err = validateParameterReferencePrefix(&fetchedParameters)
+	if err != nil {
+		return report, err
+	}
+
+	err = validateParameterDataTypes(fetchedParameters)
+	if err != nil {
+		return report, err
+	}
+
+	maxConcurrency := r.MaxConcurrency
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	semaphore := make(chan struct{}, maxConcurrency)
+
+	done := map[string]chan struct{}{}
+	for _, name := range order {
+		done[name] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range order {
+		wg.Add(1)
+		go func(document *RenderSetDocument, documentReport *DocumentRenderReport) {
+			defer wg.Done()
+			defer close(done[document.Name])
+
+			// outputs and errors of dependencies are written before their done channel is closed
+			for _, dependency := range document.Dependencies {
+				<-done[dependency]
+				if report.Documents[dependency].Err != nil {
+					documentReport.Err = errors.New("dependency " + dependency + " failed")
+					return
+				}
+			}
+
+			if documentReport.Err = ctx.Err(); documentReport.Err != nil {
+				return
+			}
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				documentReport.Err = ctx.Err()
+				return
+			}
+
+			start := time.Now()
+			documentReport.Output, documentReport.Err = r.renderDocument(ctx, document, snapshot, fetchedParameters, report)
+			documentReport.Duration = time.Since(start)
+		}(documents[name], report.Documents[name])
+	}
+	wg.Wait()
+
+	for ref, param := range snapshot.parameters {
+		if !isSsmReference(ref) {
+			continue
+		}
+
+		name := extractParameterNameFromReference(ref)
+		if parameterName, _, _ := splitParameterSelector(name); parameterName == name {
+			name = param.Name
+		}
+		report.ParameterVersions[name] = param.Version
+	}
+
+	failed := 0
+	var firstFailure error
+	for _, name := range order {
+		if documentErr := report.Documents[name].Err; documentErr != nil {
+			failed++
+			if firstFailure == nil {
+				firstFailure = fmt.Errorf("cannot render document %s: %w", name, documentErr)
+			}
+		}
+	}
+
+	// some documents are rendered
+	if failed > 0 && failed < len(order) {
+		return report, withStatus(StatusPartial, firstFailure)
+	}
+
+	return report, firstFailure
+}
+
+func (r *RenderSet) documentOptions(name string) ResolveOptions {
+	options := r.options
+	if r.documents[name].Options != nil {
+		options = *r.documents[name].Options
+	}
+
+	if len(options.DocumentID) == 0 {
+		options.DocumentID = name
+	}
+
+	return options
+}
+
+// resolves the template of document and replaces its {{render:name}} placeholders with the rendered dependencies.
+// Included outputs are already resolved and are not resolved again, nor are {{render:name}} placeholders
+// coming from parameter values replaced.
+func (r *RenderSet) renderDocument(
+	ctx context.Context,
+	document *RenderSetDocument,
+	snapshot *renderSetSnapshot,
+	fetchedParameters map[string]SsmParameterInfo,
+	report *RenderReport) (string, error) {
+
+	options := r.documentOptions(document.Name)
+
+	// placeholders of the parameters left missing by IgnoreMissingParameters are kept, or emptied
+	resolvedParametersMap := map[string]SsmParameterInfo{}
+	for _, ref := range report.Documents[document.Name].References {
+		if param, found := fetchedParameters[ref]; found {
+			resolvedParametersMap[ref] = param
+		} else if options.EmptyMissingParameters {
+			resolvedParametersMap[ref] = missingParameter(ref, "")
+		}
+	}
+
+	if options.Recursive {
+		var err error
+		resolvedParametersMap, err = resolveNestedParameters(ctx, snapshot, resolvedParametersMap, options)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	resolvedParametersMap, err := sanitizeResolvedValues(document.Template, resolvedParametersMap, options.SanitizeValues)
+	if err != nil {
+		return "", err
+	}
+	warnAboutResolvedValues(document.Template, "", resolvedParametersMap, options)
+
+	buffer := getSubstitutionBuffer()
+	defer putSubstitutionBuffer(buffer)
+
+	// checked for leftover placeholders without the outputs of the included documents, which are checked on their own
+	substitutedSegments := []string{}
+	doWithProfilerLabels(ctx, options.DocumentID, substitutePhase, func(context.Context) {
+		last := 0
+		for _, match := range renderPlaceholder.FindAllStringSubmatchIndex(document.Template, -1) {
+			var segment string
+			segment, err = replaceParameterPlaceholders(document.Template[last:match[0]], resolvedParametersMap)
+			if err != nil {
+				return
+			}
+			substitutedSegments = append(substitutedSegments, segment)
+
+			buffer.WriteString(segment)
+			buffer.WriteString(report.Documents[document.Template[match[2]:match[3]]].Output)
+			last = match[1]
+		}
+
+		var segment string
+		segment, err = replaceParameterPlaceholders(document.Template[last:], resolvedParametersMap)
+		buffer.WriteString(segment)
+		substitutedSegments = append(substitutedSegments, segment)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkUnresolvedPlaceholders(strings.Join(substitutedSegments, "\n"), options); err != nil {
+		return "", err
+	}
+
+	// included documents count once however many times they are included
+	sourceSize := len(document.Template)
+	for _, param := range resolvedParametersMap {
+		sourceSize += len(param.Value)
+	}
+	for _, dependency := range document.Dependencies {
+		sourceSize += len(report.Documents[dependency].Output)
+	}
+
+	err = checkExpandedSize(maxExpandedSize(effectiveLimits(options), sourceSize), buffer.Len(), "document "+document.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return restorePlaceholderDelimiters(buffer.String(), options.PlaceholderSyntax), nil
+}
+
+// returns the document names ordered so that every document follows its dependencies (Kahn's algorithm)
+func (r *RenderSet) dependencyOrder() ([]string, error) {
+	remainingDependencies := map[string]int{}
+	dependents := map[string][]string{}
+
+	for name, document := range r.documents {
+		for _, dependency := range document.Dependencies {
+			if _, exists := r.documents[dependency]; !exists {
+				return nil, errors.New("document " + name + " depends on unknown document " + dependency)
+			}
+			dependents[dependency] = append(dependents[dependency], name)
+		}
+		remainingDependencies[name] = len(document.Dependencies)
+	}
+
+	ready := []string{}
+	for name, count := range remainingDependencies {
+		if count == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	order := []string{}
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+
+		for _, dependent := range dependents[name] {
+			remainingDependencies[dependent]--
+			if remainingDependencies[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) < len(r.documents) {
+		cyclic := []string{}
+		for name, count := range remainingDependencies {
+			if count > 0 {
+				cyclic = append(cyclic, name)
+			}
+		}
+		sort.Strings(cyclic)
+		return nil, errors.New("cyclic dependencies between documents: " + strings.Join(cyclic, ","))
+	}
+
+	return order, nil
+}
+
+func containsString(slice []string, value string) bool {
+	for _, element := range slice {
+		if element == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// Parameters fetched by one RenderSet execution. Nested references fetched while rendering a document are fetched
+// once too: every document gets the value fetched first, whatever its version at the time of the later requests.
+type renderSetSnapshot struct {
+	service ISsmParameterService
+
+	mutex      sync.Mutex
+	parameters map[string]SsmParameterInfo
+}
+
+// keeps the parameters not in the snapshot yet and returns the snapshot value of every one of them
+func (s *renderSetSnapshot) add(parameters map[string]SsmParameterInfo) map[string]SsmParameterInfo {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	result := map[string]SsmParameterInfo{}
+	for ref, param := range parameters {
+		if _, contains := s.parameters[ref]; !contains {
+			s.parameters[ref] = param
+		}
+		result[ref] = s.parameters[ref]
+	}
+
+	return result
+}
+
+func (s *renderSetSnapshot) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	s.mutex.Lock()
+	missingReferences := []string{}
+	result := map[string]SsmParameterInfo{}
+	for _, ref := range parameterReferences {
+		if param, contains := s.parameters[ref]; contains {
+			result[ref] = param
+		} else {
+			missingReferences = append(missingReferences, ref)
+		}
+	}
+	s.mutex.Unlock()
+
+	if len(missingReferences) == 0 {
+		return result, nil
+	}
+
+	fetchedParameters, err := s.service.callGetParameters(ctx, missingReferences)
+	if err != nil {
+		return nil, err
+	}
+
+	for ref, param := range s.add(fetchedParameters) {
+		result[ref] = param
+	}
+
+	return result, nil
+}
+
+func (s *renderSetSnapshot) callGetSecretValue(ctx context.Context, secretId string) (SsmParameterInfo, error) {
+	ref := secretsManagerPrefix + secretId
+
+	s.mutex.Lock()
+	secret, contains := s.parameters[ref]
+	s.mutex.Unlock()
+	if contains {
+		return secret, nil
+	}
+
+	secret, err := getSecretValue(ctx, s.service, secretId)
+	if err != nil {
+		return SsmParameterInfo{}, err
+	}
+
+	return s.add(map[string]SsmParameterInfo{ref: secret})[ref], nil
+}
+
+func (s *renderSetSnapshot) callGetParametersByPath(ctx context.Context, path string, recursive bool) ([]SsmParameterInfo, error) {
+	return s.service.callGetParametersByPath(ctx, path, recursive)
+}
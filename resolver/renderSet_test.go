@@ -0,0 +1,210 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderSetChainedRenders(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/upstream": {Name: "/app/upstream", Type: stringType, Value: "10.0.0.1:8080"},
+		"ssm:/app/host":     {Name: "/app/host", Type: stringType, Value: "example.com"},
+		"ssm:/app/literal":  {Name: "/app/literal", Type: stringType, Value: "{{render:upstream.conf}}"},
+	})
+
+	renderSet := NewRenderSet(&serviceObject, ResolveOptions{})
+	assert.Nil(t, renderSet.Add("site.conf", "server {{ssm:/app/host}} {\n{{render:upstream.conf}}\n}", "upstream.conf"))
+	assert.Nil(t, renderSet.Add("upstream.conf", "upstream {{ssm:/app/upstream}}; # {{ssm:/app/literal}}"))
+
+	rendered, err := renderSet.Render()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "upstream 10.0.0.1:8080; # {{render:upstream.conf}}", rendered["upstream.conf"])
+	assert.Equal(t, "server example.com {\nupstream 10.0.0.1:8080; # {{render:upstream.conf}}\n}", rendered["site.conf"])
+}
+
+func TestRenderSetUndeclaredDependency(t *testing.T) {
+	renderSet := NewRenderSet(nil, ResolveOptions{})
+
+	err := renderSet.Add("a", "{{render:b}}")
+
+	assert.NotNil(t, err)
+}
+
+func TestRenderSetCycle(t *testing.T) {
+	renderSet := NewRenderSet(nil, ResolveOptions{})
+	assert.Nil(t, renderSet.Add("a", "{{render:b}}", "b"))
+	assert.Nil(t, renderSet.Add("b", "{{render:c}}", "c"))
+	assert.Nil(t, renderSet.Add("c", "{{render:a}}", "a"))
+	assert.Nil(t, renderSet.Add("d", "independent"))
+
+	_, err := renderSet.Render()
+
+	assert.EqualError(t, err, "cyclic dependencies between documents: a,b,c")
+}
+
+func TestRenderSetUnknownDependency(t *testing.T) {
+	renderSet := NewRenderSet(nil, ResolveOptions{})
+	assert.Nil(t, renderSet.Add("a", "text", "missing"))
+
+	_, err := renderSet.Render()
+
+	assert.NotNil(t, err)
+}
+
+//
+// Counts the calls made to the mocked SSM service
+type countingService struct {
+	ServiceMockedObjectWithRecords
+	mutex sync.Mutex
+	calls int
+}
+
+func (m *countingService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	m.mutex.Lock()
+	m.calls++
+	m.mutex.Unlock()
+	return m.ServiceMockedObjectWithRecords.callGetParameters(ctx, parameterReferences)
+}
+
+func TestRenderSetExecuteSharesFetches(t *testing.T) {
+	serviceObject := &countingService{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+			"ssm:/app/host":     {Name: "/app/host", Type: stringType, Value: "example.com"},
+			"ssm-secure:/app/p": {Name: "/app/p", Type: secureStringType, Value: "s3cr3t"},
+		}),
+	}
+
+	renderSet := NewRenderSet(serviceObject, ResolveOptions{})
+	renderSet.MaxConcurrency = 4
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		assert.Nil(t, renderSet.Add(name, name+"={{ssm:/app/host}}"))
+	}
+	assert.Nil(t, renderSet.Register(RenderSetDocument{
+		Name:     "public",
+		Template: "{{ssm:/app/host}} {{ssm-secure:/app/p}}",
+		Options:  &ResolveOptions{IgnoreSecureParameters: true},
+	}))
+	assert.Nil(t, renderSet.Add("all", "{{render:a}} {{ssm-secure:/app/p}}", "a"))
+
+	report, err := renderSet.Execute(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, serviceObject.calls)
+	assert.Equal(t, 2, report.FetchedReferences)
+	assert.Equal(t, "e=example.com", report.Documents["e"].Output)
+	assert.Equal(t, "example.com {{ssm-secure:/app/p}}", report.Documents["public"].Output)
+	assert.Equal(t, "a=example.com s3cr3t", report.Documents["all"].Output)
+	assert.Equal(t, []string{"ssm:/app/host"}, report.Documents["public"].References)
+}
+
+func TestRenderSetExecuteFailedDependency(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/key": {Name: "/app/key", Type: stringType, Value: "not a certificate"},
+	})
+
+	renderSet := NewRenderSet(&serviceObject, ResolveOptions{})
+	assert.Nil(t, renderSet.Add("cert.pem", "{{ssm:/app/key | pem-leaf}}"))
+	assert.Nil(t, renderSet.Add("bundle.pem", "{{render:cert.pem}}", "cert.pem"))
+	assert.Nil(t, renderSet.Add("other", "ok"))
+
+	report, err := renderSet.Execute(context.Background())
+
+	assert.NotNil(t, err)
+	assert.Equal(t, StatusPartial, StatusOf(err))
+	assert.NotNil(t, report.Documents["cert.pem"].Err)
+	assert.EqualError(t, report.Documents["bundle.pem"].Err, "dependency cert.pem failed")
+	assert.Nil(t, report.Documents["other"].Err)
+	assert.Equal(t, "ok", report.Documents["other"].Output)
+}
+
+func TestRenderSetExecuteCanceled(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+
+	renderSet := NewRenderSet(&serviceObject, ResolveOptions{})
+	assert.Nil(t, renderSet.Add("a", "text"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := renderSet.Execute(ctx)
+
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+//
+// Mocked service rotating /app/nested on every GetParameters request fetching it
+type rotatingNestedService struct {
+	ServiceMockedObjectWithRecords
+	mutex     sync.Mutex
+	rotations int64
+}
+
+func (m *rotatingNestedService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	parameters, err := m.ServiceMockedObjectWithRecords.callGetParameters(ctx, parameterReferences)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for ref, param := range parameters {
+		if param.Name == "/app/nested" {
+			m.rotations++
+			param.Version = m.rotations
+			param.Value = "v" + strconv.FormatInt(m.rotations, 10)
+			parameters[ref] = param
+		}
+	}
+
+	return parameters, err
+}
+
+func TestRenderSetExecuteConsistentVersions(t *testing.T) {
+	serviceObject := &rotatingNestedService{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+			"ssm:/app/a":      {Name: "/app/a", Type: stringType, Value: "a-{{ssm:/app/nested}}", Version: 7},
+			"ssm:/app/b":      {Name: "/app/b", Type: stringType, Value: "b-{{ssm:/app/nested}}", Version: 2},
+			"ssm:/app/nested": {Name: "/app/nested", Type: stringType},
+		}),
+	}
+
+	renderSet := NewRenderSet(serviceObject, ResolveOptions{Recursive: true})
+	renderSet.MaxConcurrency = 2
+	assert.Nil(t, renderSet.Add("a", "{{ssm:/app/a}}"))
+	assert.Nil(t, renderSet.Add("b", "{{ssm:/app/b}}"))
+
+	report, err := renderSet.Execute(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "a-v1", report.Documents["a"].Output)
+	assert.Equal(t, "b-v1", report.Documents["b"].Output)
+	assert.Equal(t, map[string]int64{"/app/a": 7, "/app/b": 2, "/app/nested": 1}, report.ParameterVersions)
+}
+
+func TestRenderSetKeepsPlaceholdersOfMissingParameters(t *testing.T) {
+	serviceObject := newMissingParametersService()
+
+	renderSet := NewRenderSet(serviceObject, ResolveOptions{IgnoreMissingParameters: true})
+	assert.Nil(t, renderSet.Add("app.conf", "a={{ssm:/app/host}}\nb={{ssm:/missing}}"))
+	assert.Nil(t, renderSet.Register(RenderSetDocument{
+		Name:     "empty.conf",
+		Template: "b={{ssm:/missing}}",
+		Options:  &ResolveOptions{IgnoreMissingParameters: true, EmptyMissingParameters: true},
+	}))
+	assert.Nil(t, renderSet.Register(RenderSetDocument{
+		Name:     "strict.conf",
+		Template: "b={{ssm:/missing}}",
+		Options:  &ResolveOptions{IgnoreMissingParameters: true, FailOnUnresolvedPlaceholders: true},
+	}))
+
+	report, err := renderSet.Execute(context.Background())
+
+	assert.NotNil(t, err)
+	assert.Equal(t, StatusPartial, StatusOf(err))
+	assert.Equal(t, "a=example.com\nb={{ssm:/missing}}", report.Documents["app.conf"].Output)
+	assert.Equal(t, "b=", report.Documents["empty.conf"].Output)
+
+	var unresolvedError *UnresolvedPlaceholdersError
+	assert.True(t, errors.As(report.Documents["strict.conf"].Err, &unresolvedError))
+}
@@ -1,9 +1,15 @@
 package resolver
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 )
 
 //
@@ -14,12 +20,84 @@ func ExtractParametersFromText(
 	input string,
 	options ResolveOptions) (map[string]SsmParameterInfo, error) {
 
-	uniqueParameterReferences, err := parseParametersFromTextIntoDedupedSlice(input, options.IgnoreSecureParameters)
+	return ExtractParametersFromTextWithContext(context.Background(), service, input, options)
+}
+
+//
+// Same as ExtractParametersFromText, but the SSM requests are canceled when ctx is done.
+func ExtractParametersFromTextWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (map[string]SsmParameterInfo, error) {
+
+	return extractParametersFromTexts(ctx, service, []string{input}, options)
+}
+
+// resolves the parameters of several documents with a single set of SSM lookups; each document is parsed and
+// validated on its own, so placeholders never span two documents and Limits apply to every document
+func extractParametersFromTexts(
+	ctx context.Context,
+	service ISsmParameterService,
+	inputs []string,
+	options ResolveOptions) (map[string]SsmParameterInfo, error) {
+
+	translatedInputs := make([]string, 0, len(inputs))
+	allReferences := []string{}
+	for _, input := range inputs {
+		input, err := translatePlaceholderSyntax(input, options.PlaceholderSyntax)
+		if err != nil {
+			return nil, err
+		}
+
+		var references []string
+		doWithProfilerLabels(ctx, options.DocumentID, parsePhase, func(context.Context) {
+			references, err = parseAndValidatePlaceholders(input, options)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		translatedInputs = append(translatedInputs, input)
+		allReferences = append(allReferences, references...)
+	}
+
+	parametersWithValues, err := resolveParsedReferences(ctx, service, dedupSlice(allReferences), options,
+		placeholderMaxAges(translatedInputs...), placeholderDefaults(translatedInputs...))
 	if err != nil {
 		return nil, err
 	}
 
-	parametersWithValues, err := getParametersFromSsmParameterStore(service, uniqueParameterReferences)
+	for _, input := range translatedInputs {
+		parametersWithValues, err = sanitizeResolvedValues(input, parametersWithValues, options.SanitizeValues)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sinks, err := placeholderSinks(translatedInputs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliverSecrets(ctx, parametersWithValues, sinks, options)
+}
+
+// fetches the parameter references parsed from a document, falling back to their defaults when they are missing,
+// validates them and resolves their nested references
+func resolveParsedReferences(
+	ctx context.Context,
+	service ISsmParameterService,
+	uniqueParameterReferences []string,
+	options ResolveOptions,
+	maxAges map[string]time.Duration,
+	defaults map[string]string) (map[string]SsmParameterInfo, error) {
+
+	var parametersWithValues map[string]SsmParameterInfo
+	var err error
+	doWithProfilerLabels(ctx, options.DocumentID, fetchPhase, func(ctx context.Context) {
+		parametersWithValues, err = fetchParametersOrDefaults(ctx, service, uniqueParameterReferences, options, maxAges, defaults)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -29,23 +107,132 @@ func ExtractParametersFromText(
 		return nil, prefixValidationError
 	}
 
+	dataTypeValidationError := validateParameterDataTypes(parametersWithValues)
+	if dataTypeValidationError != nil {
+		return nil, dataTypeValidationError
+	}
+
+	_, err = reconcileParameterVersions(ctx, service, parametersWithValues, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Recursive {
+		return resolveNestedParameters(ctx, service, parametersWithValues, options)
+	}
+
 	return parametersWithValues, nil
 }
 
+//
+// Same as ExtractParametersFromText, but returns a map of (parameter reference) to parameter value
+// for callers that do not need the rest of SsmParameterInfo.
+func ExtractValuesFromText(
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (map[string]string, error) {
+
+	return ExtractValuesFromTextWithContext(context.Background(), service, input, options)
+}
+
+//
+// Same as ExtractValuesFromText, but the SSM requests are canceled when ctx is done.
+func ExtractValuesFromTextWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (map[string]string, error) {
+
+	resolvedParametersMap, err := ExtractParametersFromTextWithContext(ctx, service, input, options)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(resolvedParametersMap))
+	for ref, param := range resolvedParametersMap {
+		values[ref] = param.Value
+	}
+
+	return values, nil
+}
+
+// returns the deduped parameter references of text after validating its placeholders according to ResolveOptions
+func parseAndValidatePlaceholders(input string, options ResolveOptions) ([]string, error) {
+	start := time.Now()
+	limits := effectiveLimits(options)
+
+	err := checkPlaceholderCount(limits, input)
+	if err != nil {
+		return nil, err
+	}
+
+	uniqueParameterReferences, err := parseParametersFromTextIntoDedupedSlice(input, options.IgnoreSecureParameters)
+	if err != nil {
+		return nil, withStatus(StatusParseError, err)
+	}
+
+	if err := checkParseTime(limits, start); err != nil {
+		return nil, err
+	}
+
+	err = validatePlaceholderModifiers(input, options)
+	if err != nil {
+		return nil, withStatus(StatusParseError, err)
+	}
+
+	if err := checkParseTime(limits, start); err != nil {
+		return nil, err
+	}
+
+	err = checkSecurityProfile(input, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if checksShellContexts(options) {
+		unsafePlaceholders := FindUnquotedPlaceholdersInShellContexts(input)
+		if len(unsafePlaceholders) > 0 {
+			return nil, withStatus(StatusPolicyViolation, errors.New("the following placeholder(s) are used in a shell command context without the "+
+				shellQuoteTransformer+" transformer: "+strings.Join(unsafePlaceholders, ",")))
+		}
+
+		if err := checkParseTime(limits, start); err != nil {
+			return nil, err
+		}
+	}
+
+	return uniqueParameterReferences, nil
+}
+
 //
 // Takes a list of references to SSM parameters, resolves them according to ResolveOptions and
 // returns a map of (parameter reference) to SsmParameterInfo.
+//
+// Deprecated: use Resolver.ResolveReferences of github.com/parameterResolver/resolver/v2.
 func ResolveParameterReferenceList(
 	service ISsmParameterService,
 	parameterReferences []string,
 	options ResolveOptions) (map[string]SsmParameterInfo, error) {
 
+	return ResolveParameterReferenceListWithContext(context.Background(), service, parameterReferences, options)
+}
+
+//
+// Same as ResolveParameterReferenceList, but the SSM requests are canceled when ctx is done.
+//
+// Deprecated: use Resolver.ResolveReferences of github.com/parameterResolver/resolver/v2.
+func ResolveParameterReferenceListWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	parameterReferences []string,
+	options ResolveOptions) (map[string]SsmParameterInfo, error) {
+
 	uniqueParameterReferences := dedupSlice(parameterReferences)
 
 	parameterReferencesToResolve := []string{}
 	if options.IgnoreSecureParameters {
 		for _, ref := range uniqueParameterReferences {
-			if strings.HasPrefix(ref, ssmNonSecurePrefix) {
+			if source, isSource := lookupSource(ref); strings.HasPrefix(ref, ssmNonSecurePrefix) || (isSource && !source.Secure) {
 				parameterReferencesToResolve = append(parameterReferencesToResolve, ref)
 			}
 		}
@@ -53,7 +240,7 @@ func ResolveParameterReferenceList(
 		parameterReferencesToResolve = append(parameterReferencesToResolve, uniqueParameterReferences...)
 	}
 
-	parametersWithValues, err := getParametersFromSsmParameterStore(service, parameterReferencesToResolve)
+	parametersWithValues, err := fetchParametersOrDefaults(ctx, service, parameterReferencesToResolve, options, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -63,39 +250,284 @@ func ResolveParameterReferenceList(
 		return nil, prefixValidationError
 	}
 
-	return parametersWithValues, nil
+	dataTypeValidationError := validateParameterDataTypes(parametersWithValues)
+	if dataTypeValidationError != nil {
+		return nil, dataTypeValidationError
+	}
+
+	// references outside of a document have no single-line context
+	return sanitizeResolvedValues("", parametersWithValues, options.SanitizeValues)
+}
+
+//
+// Takes a map of (key) to SSM parameter reference, e.g. {"dbHost": "ssm:/app/db/host"}, resolves the references
+// according to ResolveOptions and returns a map of (key) to parameter value.
+// Keys of secure references are left out when IgnoreSecureParameters is set.
+//
+// Deprecated: use Resolver.ResolveMap of github.com/parameterResolver/resolver/v2.
+func ResolveMap(
+	service ISsmParameterService,
+	refsByKey map[string]string,
+	options ResolveOptions) (map[string]string, error) {
+
+	return ResolveMapWithContext(context.Background(), service, refsByKey, options)
+}
+
+//
+// Same as ResolveMap, but the SSM requests are canceled when ctx is done.
+//
+// Deprecated: use Resolver.ResolveMap of github.com/parameterResolver/resolver/v2.
+func ResolveMapWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	refsByKey map[string]string,
+	options ResolveOptions) (map[string]string, error) {
+
+	parameterReferences := make([]string, 0, len(refsByKey))
+	for _, ref := range refsByKey {
+		parameterReferences = append(parameterReferences, ref)
+	}
+
+	resolvedParametersMap, err := ResolveParameterReferenceListWithContext(ctx, service, parameterReferences, options)
+	if err != nil {
+		return nil, err
+	}
+
+	valuesByKey := map[string]string{}
+	for key, ref := range refsByKey {
+		if param, found := resolvedParametersMap[ref]; found {
+			valuesByKey[key] = param.Value
+		}
+	}
+
+	return valuesByKey, nil
+}
+
+//
+// Fetches all parameters under path, e.g. /app/prod/, following the pages of GetParametersByPath, and returns
+// a map of (parameter name) to SsmParameterInfo. Parameters nested deeper than the children of path are
+// included when recursive is set, SecureString parameters are left out when IgnoreSecureParameters is set.
+//
+// Deprecated: use Resolver.ResolvePath of github.com/parameterResolver/resolver/v2.
+func ResolveParametersByPath(
+	service ISsmParameterService,
+	path string,
+	recursive bool,
+	options ResolveOptions) (map[string]SsmParameterInfo, error) {
+
+	return ResolveParametersByPathWithContext(context.Background(), service, path, recursive, options)
+}
+
+//
+// Same as ResolveParametersByPath, but the SSM requests are canceled when ctx is done.
+//
+// Deprecated: use Resolver.ResolvePath of github.com/parameterResolver/resolver/v2.
+func ResolveParametersByPathWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	path string,
+	recursive bool,
+	options ResolveOptions) (map[string]SsmParameterInfo, error) {
+
+	if !strings.HasPrefix(path, "/") {
+		return nil, errors.New("parameter path " + path + " does not start with /")
+	}
+
+	parameters, err := service.callGetParametersByPath(ctx, path, recursive)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get parameters under %s: %w", path, err)
+	}
+
+	parametersByName := make(map[string]SsmParameterInfo, len(parameters))
+	for _, param := range parameters {
+		if options.IgnoreSecureParameters && param.Type == secureStringType {
+			continue
+		}
+
+		parametersByName[param.Name] = param
+		if options.Cache != nil {
+			options.Cache.Set(param.Name, param)
+		}
+	}
+
+	return parametersByName, nil
 }
 
 //
 // Takes text document, resolves all parameters in it according to ResolveOptions
-// and returns resolved document.
+// and returns resolved document. Values are substituted literally: $1, ${name} and $$ in a value are kept as they are,
+// they are not expanded as regexp replacement templates as they were by early versions.
+//
+// Deprecated: use Resolver.ResolveText of github.com/parameterResolver/resolver/v2.
 func ResolveParametersInText(
 	service ISsmParameterService,
 	input string,
 	options ResolveOptions) (string, error) {
 
-	resolvedParametersMap, err := ExtractParametersFromText(service, input, options)
+	return ResolveParametersInTextWithContext(context.Background(), service, input, options)
+}
+
+//
+// Same as ResolveParametersInText, but the SSM requests are canceled when ctx is done.
+//
+// Deprecated: use Resolver.ResolveText of github.com/parameterResolver/resolver/v2.
+func ResolveParametersInTextWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, error) {
+
+	return resolveText(ctx, service, input, options, nil)
+}
+
+// resolves input like ResolveParametersInTextWithContext, calling onSubstitute, when not nil, for every placeholder
+// substituted. Lines of the placeholders are these of input for the default placeholder syntax.
+func resolveText(
+	ctx context.Context,
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions,
+	onSubstitute func(substitution)) (string, error) {
+
+	format, err := lookupFormat(options.Format, "")
+	if err != nil {
+		return input, err
+	}
+
+	text, err := prepareTemplate(ctx, service, input, options)
+	if err != nil {
+		return input, err
+	}
+
+	resolvedParametersMap, err := ExtractParametersFromTextWithContext(ctx, service, text, withDefaultPlaceholderSyntax(options))
 	if err != nil || resolvedParametersMap == nil || len(resolvedParametersMap) == 0 {
+		if err == nil {
+			err = checkUnresolvedPlaceholders(text, options)
+		}
 		return input, err
 	}
 
-	for ref, param := range resolvedParametersMap {
-		var placeholder = regexp.MustCompile("{{\\s*" + ref + "\\s*}}")
-		input = placeholder.ReplaceAllString(input, param.Value)
+	warnAboutResolvedValues(text, "", resolvedParametersMap, options)
+
+	var resolvedText string
+	doWithProfilerLabels(ctx, options.DocumentID, substitutePhase, func(context.Context) {
+		resolvedText, err = replaceParameterPlaceholdersInFormat(text, resolvedParametersMap, format, options.Format,
+			lookupWatermark(options, ""), onSubstitute)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkUnresolvedPlaceholders(resolvedText, options); err != nil {
+		return "", err
+	}
+
+	return restorePlaceholderDelimiters(resolvedText, options.PlaceholderSyntax), nil
+}
+
+//
+// Same as ResolveParametersInTextWithContext with ResolveOptions.IgnoreMissingParameters set: returns the partially
+// resolved document and the sorted references of the missing parameters whose placeholders were skipped.
+//
+// Deprecated: use Resolver.ResolveTextPartially of github.com/parameterResolver/resolver/v2.
+func ResolveParametersInTextPartially(
+	ctx context.Context,
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (string, []string, error) {
+
+	skippedReferences := []string{}
+	options.IgnoreMissingParameters = true
+	options.skipMissingParameter = func(parameterReference string) {
+		skippedReferences = append(skippedReferences, parameterReference)
 	}
 
-	return input, nil
+	resolvedText, err := ResolveParametersInTextWithContext(ctx, service, input, options)
+	if err != nil {
+		return resolvedText, nil, err
+	}
+
+	skippedReferences = dedupSlice(skippedReferences)
+	sort.Strings(skippedReferences)
+
+	return resolvedText, skippedReferences, nil
+}
+
+//
+// Reads the document from input, resolves SSM parameters in it according to ResolveOptions and
+// writes resolved document to output. Documents over MaxFileSizeInBytes are rejected before anything is written.
+//
+// Deprecated: use Resolver.Resolve of github.com/parameterResolver/resolver/v2.
+func ResolveParameters(
+	service ISsmParameterService,
+	input io.Reader,
+	output io.Writer,
+	options ResolveOptions) error {
+
+	return ResolveParametersWithContext(context.Background(), service, input, output, options)
+}
+
+//
+// Same as ResolveParameters, but the SSM requests are canceled when ctx is done.
+// Nothing is written to output once ctx is done.
+//
+// Deprecated: use Resolver.Resolve of github.com/parameterResolver/resolver/v2.
+func ResolveParametersWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	input io.Reader,
+	output io.Writer,
+	options ResolveOptions) error {
+
+	unresolvedText, err := ioutil.ReadAll(io.LimitReader(input, MaxFileSizeInBytes+1))
+	if err != nil {
+		return fmt.Errorf("cannot read document: %w", err)
+	}
+
+	if len(unresolvedText) > MaxFileSizeInBytes {
+		return errors.New("document is too large")
+	}
+
+	resolvedText, err := ResolveParametersInTextWithContext(ctx, service, string(unresolvedText), options)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(output, resolvedText)
+	return err
 }
 
 //
 // Reads inputFileName, resolves SSM parameters in it according to ResolveOptions and
-// stores resolved document in the outputFileName file.
+// stores resolved document in the outputFileName file. An outputFileName referring to inputFileName, e.g. through
+// a symlink, is replaced atomically, so that the template is never left truncated.
+//
+// Deprecated: use Resolver.ResolveFile of github.com/parameterResolver/resolver/v2.
 func ResolveParametersInFile(
 	service ISsmParameterService,
 	inputFileName string,
 	outputFileName string,
 	options ResolveOptions) error {
 
+	return ResolveParametersInFileWithContext(context.Background(), service, inputFileName, outputFileName, options)
+}
+
+//
+// Same as ResolveParametersInFile, but the SSM requests are canceled when ctx is done.
+// The output file is not written once ctx is done.
+//
+// Deprecated: use Resolver.ResolveFile of github.com/parameterResolver/resolver/v2.
+func ResolveParametersInFileWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	inputFileName string,
+	outputFileName string,
+	options ResolveOptions) error {
+
 	if len(inputFileName) == 0 {
 		return errors.New("input file name is not provided")
 	}
@@ -104,6 +536,9 @@ func ResolveParametersInFile(
 		return errors.New("output file name is not provided")
 	}
 
+	// resolving a template into itself replaces it atomically, it is never left truncated
+	inPlace := isSameFile(inputFileName, outputFileName)
+
 	errorInFileOrSize := validateFileAndSize(inputFileName)
 	if errorInFileOrSize != nil {
 		return errorInFileOrSize
@@ -114,73 +549,207 @@ func ResolveParametersInFile(
 		return err
 	}
 
-	resolvedParametersMap, err := ExtractParametersFromText(service, unresolvedText, options)
-	if err != nil || resolvedParametersMap == nil || len(resolvedParametersMap) == 0 {
+	unresolvedText, err = prepareTemplate(ctx, service, unresolvedText, options)
+	if err != nil {
 		return err
 	}
 
-	for ref, param := range resolvedParametersMap {
-		var placeholder = regexp.MustCompile("{{\\s*" + ref + "\\s*}}")
-		unresolvedText = placeholder.ReplaceAllString(unresolvedText, param.Value)
+	_, err = lookupFormat(options.Format, outputFileName)
+	if err != nil {
+		return err
 	}
 
-	err = writeToFile(unresolvedText, outputFileName)
+	options.allowBinaryValues = true
+	resolvedParametersMap, err := ExtractParametersFromTextWithContext(ctx, service, unresolvedText, withDefaultPlaceholderSyntax(options))
 	if err != nil {
 		return err
 	}
 
-	return nil
+	resolvedText, err := renderOutputFile(ctx, unresolvedText, outputFileName, resolvedParametersMap, options)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// a single file is a batch of its own
+	if options.SyncPolicy == SyncPerBatch {
+		options.SyncPolicy = SyncPerFile
+	}
+
+	return writeOutputFile(resolvedText, outputFileName, inPlace, options)
 }
 
-func validateParameterReferencePrefix(resolvedParametersMap *map[string]SsmParameterInfo) error {
-	for key, value := range *resolvedParametersMap {
-		if strings.HasPrefix(key, ssmSecurePrefix) && value.Type != secureStringType {
-			return errors.New("for parameter reference {{" + key + "}} secure prefix " + ssmSecurePrefix + " is used for a non-secure type " + value.Type)
+//
+// Resolves SSM parameters in the file fileName according to ResolveOptions and replaces it with the resolved document
+// atomically: the document is written to a temporary file in the same directory, flushed to disk and renamed over
+// fileName, which is never left partially written, even after a crash. Its previous content is kept in the file
+// named after it with ResolveOptions.BackupSuffix, e.g. app.conf.bak, when the suffix is set.
+//
+// Deprecated: use Resolver.ResolveFileInPlace of github.com/parameterResolver/resolver/v2.
+func ResolveParametersInPlace(
+	service ISsmParameterService,
+	fileName string,
+	options ResolveOptions) error {
+
+	return ResolveParametersInPlaceWithContext(context.Background(), service, fileName, options)
+}
+
+//
+// Same as ResolveParametersInPlace, but the SSM requests are canceled when ctx is done.
+// The file is not replaced once ctx is done.
+//
+// Deprecated: use Resolver.ResolveFileInPlace of github.com/parameterResolver/resolver/v2.
+func ResolveParametersInPlaceWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	fileName string,
+	options ResolveOptions) error {
+
+	if len(fileName) == 0 {
+		return errors.New("file name is not provided")
+	}
+
+	return ResolveParametersInFileWithContext(ctx, service, fileName, fileName, options)
+}
+
+// substitutes the resolved parameters into the text of outputFileName in the format of the file
+// and applies the post-render filters. The text is prepared by prepareTemplate.
+func renderOutputFile(
+	ctx context.Context,
+	unresolvedText string,
+	outputFileName string,
+	resolvedParametersMap map[string]SsmParameterInfo,
+	options ResolveOptions) (string, error) {
+
+	format, err := lookupFormat(options.Format, outputFileName)
+	if err != nil {
+		return "", err
+	}
+
+	warnAboutResolvedValues(unresolvedText, outputFileName, resolvedParametersMap, options)
+
+	var resolvedText string
+	doWithProfilerLabels(ctx, options.DocumentID, substitutePhase, func(context.Context) {
+		resolvedText, err = replaceParameterPlaceholdersInFormat(unresolvedText, resolvedParametersMap, format,
+			selectFormatName(options.Format, outputFileName), lookupWatermark(options, outputFileName), nil)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkUnresolvedPlaceholders(resolvedText, options); err != nil {
+		return "", err
+	}
+	resolvedText = restorePlaceholderDelimiters(resolvedText, options.PlaceholderSyntax)
+
+	return applyPostRenderFilters(outputFileName, resolvedText, options.PostRenderFilters)
+}
+
+// writes the resolved text to outputFileName retrying transient errors, and syncs it when SyncPerFile is set.
+// An output file that is also an input, inPlace, is replaced atomically.
+func writeOutputFile(resolvedText string, outputFileName string, inPlace bool, options ResolveOptions) error {
+	return retryTransientWrite(options, func() error {
+		var err error
+		if inPlace {
+			err = writeToFileAtomically(resolvedText, outputFileName, options.BackupSuffix)
+		} else {
+			err = writeToFile(resolvedText, outputFileName)
+		}
+		if err != nil || options.SyncPolicy != SyncPerFile {
+			return err
 		}
 
-		if strings.HasPrefix(key, ssmNonSecurePrefix) && value.Type == secureStringType {
-			return errors.New("for parameter reference {{" + key + "}} non-secure prefix " + ssmNonSecurePrefix + " is used for a secure type " + value.Type)
+		return syncFile(outputFileName)
+	})
+}
+
+// replaces every placeholder of a resolved parameter reference in text with the parameter value
+// passed through the transformers listed in the placeholder. Values are never expanded as regexp templates.
+func replaceParameterPlaceholders(text string, resolvedParametersMap map[string]SsmParameterInfo) (string, error) {
+	buffer := getSubstitutionBuffer()
+	defer putSubstitutionBuffer(buffer)
+
+	for ref, param := range resolvedParametersMap {
+		var placeholder = placeholderPatterns.get("{{\\s*" + regexp.QuoteMeta(ref) + "\\s*" + placeholderModifiers + "}}")
+
+		matches := placeholder.FindAllStringSubmatchIndex(text, -1)
+		if len(matches) == 0 {
+			continue
 		}
+
+		buffer.Reset()
+		last := 0
+		for _, match := range matches {
+			value, err := applyTransformers(param.Value, parsePlaceholderModifiers(text[match[2]:match[3]]))
+			if err != nil {
+				return "", withStatus(StatusPolicyViolation, newParameterReferenceError(ref, "cannot transform value of parameter reference {{%s}}: %w", err))
+			}
+
+			buffer.WriteString(text[last:match[0]])
+			buffer.WriteString(value)
+			last = match[1]
+		}
+		buffer.WriteString(text[last:])
+
+		text = buffer.String()
 	}
 
-	return nil
+	return text, nil
 }
 
-func dedupSlice(slice []string) []string {
-	ht := map[string]bool{}
+// checks that secure parameters are referenced with the secure prefix and only them. References of secure parameters
+// with the non-secure prefix are all reported by a SecureParametersNotAllowedError.
+func validateParameterReferencePrefix(resolvedParametersMap *map[string]SsmParameterInfo) error {
+	secureReferences := []string{}
+	for key, value := range *resolvedParametersMap {
+		if strings.HasPrefix(key, ssmSecurePrefix) && value.Type != secureStringType {
+			return withStatus(StatusPolicyViolation, newParameterReferenceError(key, "for parameter reference {{%s}} secure prefix %s is used for a non-secure type %s",
+				ssmSecurePrefix, value.Type))
+		}
 
-	for _, element := range slice {
-		ht[element] = true
+		if strings.HasPrefix(key, ssmNonSecurePrefix) && value.Type == secureStringType {
+			secureReferences = append(secureReferences, key)
+		}
 	}
 
-	keys := make([]string, len(ht))
-
-	i := 0
-	for k := range ht {
-		keys[i] = k
-		i++
+	if len(secureReferences) > 0 {
+		sort.Strings(secureReferences)
+		return withStatus(StatusPolicyViolation, &SecureParametersNotAllowedError{References: secureReferences})
 	}
 
-	return keys
+	return nil
 }
 
+// returns the distinct parameter references of the placeholders in text. Matches are streamed, so the memory used
+// besides text is bounded by the number of distinct references, not by the number of placeholders.
 func parseParametersFromTextIntoDedupedSlice(text string, ignoreSecureParameters bool) ([]string, error) {
 
-	matchedPhrases := parameterPlaceholder.FindAllStringSubmatch(text, -1)
+	parameterNamesDeduped := getDedupScratchMap()
+	defer putDedupScratchMap(parameterNamesDeduped)
 
-	parameterNamesDeduped := make(map[string]bool)
-	for i := 0; i < len(matchedPhrases); i++ {
-		parameterNamesDeduped[matchedPhrases[i][1]] = true
-	}
+	forEachMatch(parameterPlaceholder, text, func(match []int) {
+		parameterNamesDeduped[text[match[2]:match[3]]] = true
+	})
 
 	if !ignoreSecureParameters {
-		matchedSecurePhrases := secureParameterPlaceholder.FindAllStringSubmatch(text, -1)
-		for i := 0; i < len(matchedSecurePhrases); i++ {
-			parameterNamesDeduped[matchedSecurePhrases[i][1]] = true
-		}
+		forEachMatch(environmentPlaceholder, text, func(match []int) {
+			parameterNamesDeduped[text[match[2]:match[3]]] = true
+		})
+		forEachMatch(secureParameterPlaceholder, text, func(match []int) {
+			parameterNamesDeduped[text[match[2]:match[3]]] = true
+		})
+		forEachMatch(secretPlaceholder, text, func(match []int) {
+			parameterNamesDeduped[text[match[2]:match[3]]] = true
+		})
 	}
+	forEachSourcePlaceholder(text, ignoreSecureParameters, func(match []int) {
+		parameterNamesDeduped[text[match[2]:match[3]]] = true
+	})
 
-	result := []string{}
+	result := make([]string, 0, len(parameterNamesDeduped))
 	for key := range parameterNamesDeduped {
 		result = append(result, key)
 	}
@@ -1,9 +1,12 @@
 package resolver
 
 import (
+	"bytes"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -62,6 +65,22 @@ func TestExtractParametersFromTextWrongPrefix(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestExtractValuesFromText(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/a/b/c/param1": {Name: "/a/b/c/param1", Type: stringType, Value: "value_/a/b/c/param1"},
+		"ssm-secure:param2": {Name: "param2", Type: secureStringType, Value: "value_param2"},
+	})
+
+	text := "Some text {{ ssm:/a/b/c/param1}}, some more text {{ssm-secure:param2}}."
+	values, err := ExtractValuesFromText(&serviceObject, text, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"ssm:/a/b/c/param1": "value_/a/b/c/param1",
+		"ssm-secure:param2": "value_param2",
+	}, values)
+}
+
 func TestResolveParameterReferenceList(t *testing.T) {
 	expectedResult := map[string]SsmParameterInfo{
 		"ssm:param1":             {Name: "param1", Type: stringType, Value: "value_param1"},
@@ -114,6 +133,27 @@ func TestResolveParameterReferenceListIgnoreSecureParams(t *testing.T) {
 	assert.True(t, reflect.DeepEqual(resolvedParameters, expectedResult))
 }
 
+func TestResolveMap(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/db/host":          {Name: "/app/db/host", Type: stringType, Value: "db.internal"},
+		"ssm-secure:/app/db/passwd": {Name: "/app/db/passwd", Type: secureStringType, Value: "secret"},
+	})
+
+	refsByKey := map[string]string{
+		"host":        "ssm:/app/db/host",
+		"replicaHost": "ssm:/app/db/host",
+		"password":    "ssm-secure:/app/db/passwd",
+	}
+
+	values, err := ResolveMap(&serviceObject, refsByKey, ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"host": "db.internal", "replicaHost": "db.internal", "password": "secret"}, values)
+
+	values, err = ResolveMap(&serviceObject, refsByKey, ResolveOptions{IgnoreSecureParameters: true})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"host": "db.internal", "replicaHost": "db.internal"}, values)
+}
+
 func TestParseParametersFromTextIntoDedupedSliceSecureNotAllowed(t *testing.T) {
 	text := "Some text {{ ssm:/a/b/c/param1}}, some more text {{ssm-secure:param2}}, {{ ssm-secure:/a/b/c/param1  }}."
 	expectedList := []string{"ssm:/a/b/c/param1"}
@@ -160,6 +200,81 @@ func TestResolveParametersInText(t *testing.T) {
 	assert.True(t, expectedOutput == output)
 }
 
+func TestResolveParameters(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/a/b/c/param1": {Name: "/a/b/c/param1", Type: stringType, Value: "value_/a/b/c/param1"},
+	})
+
+	var output bytes.Buffer
+	err := ResolveParameters(&serviceObject, strings.NewReader("Some text {{ ssm:/a/b/c/param1}}."), &output, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Some text value_/a/b/c/param1.", output.String())
+
+	output.Reset()
+	err = ResolveParameters(&serviceObject, strings.NewReader("{{ssm:/missing}}"), &output, ResolveOptions{})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, output.Len())
+}
+
+func TestResolveParametersInTextKeepsDollarSignsOfValues(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/pattern": {Name: "/app/pattern", Type: stringType, Value: "s/(a)(b)/$2$1/ ${name} $$ \\1"},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject, "sed '{{ssm:/app/pattern}}' {{ ssm:/app/pattern | shellquote }}", ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "sed 's/(a)(b)/$2$1/ ${name} $$ \\1' 's/(a)(b)/$2$1/ ${name} $$ \\1'", output)
+}
+
+func TestResolveParametersInTextWithSelectors(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/key":      {Name: "/app/key", Type: stringType, Value: "current", Version: 5},
+		"ssm:/app/key:3":    {Name: "/app/key", Type: stringType, Value: "previous", Version: 3},
+		"ssm:/app/key:prod": {Name: "/app/key", Type: stringType, Value: "stable", Version: 4, Label: "prod"},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/key}} {{ ssm:/app/key:3 | urlencode }} {{ssm:/app/key:prod}}",
+		ResolveOptions{FailOnVersionChange: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "current previous stable", output)
+
+	parameters, err := ExtractParametersFromText(&serviceObject, "{{ssm:/app/key:prod}}", ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "prod", parameters["ssm:/app/key:prod"].Label)
+}
+
+func TestResolveParametersByPath(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/prod/host":            {Name: "/app/prod/host", Type: stringType, Value: "example.com"},
+		"ssm-secure:/app/prod/password": {Name: "/app/prod/password", Type: secureStringType, Value: "s3cr3t"},
+		"ssm:/app/prod/db/port":         {Name: "/app/prod/db/port", Type: stringType, Value: "5432"},
+		"ssm:/app/test/host":            {Name: "/app/test/host", Type: stringType, Value: "test.example.com"},
+	})
+
+	parameters, err := ResolveParametersByPath(&serviceObject, "/app/prod/", false, ResolveOptions{IgnoreSecureParameters: true})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]SsmParameterInfo{
+		"/app/prod/host": {Name: "/app/prod/host", Type: stringType, Value: "example.com"},
+	}, parameters)
+
+	cache := NewMemoryCache(time.Minute)
+	parameters, err = ResolveParametersByPath(&serviceObject, "/app/prod", true, ResolveOptions{Cache: cache})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(parameters))
+	assert.Equal(t, "5432", parameters["/app/prod/db/port"].Value)
+
+	cached, found := cache.Get("/app/prod/password", 0)
+	assert.True(t, found)
+	assert.Equal(t, "s3cr3t", cached.Value)
+
+	_, err = ResolveParametersByPath(&serviceObject, "app/prod", true, ResolveOptions{})
+	assert.NotNil(t, err)
+}
+
 func TestResolveParametersInTextIgnoreSecureParams(t *testing.T) {
 	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
 		"ssm:/a/b/c/param1": {Name: "/a/b/c/param1", Type: stringType, Value: "value_/a/b/c/param1"},
@@ -177,3 +292,27 @@ func TestResolveParametersInTextIgnoreSecureParams(t *testing.T) {
 	assert.NotNil(t, output)
 	assert.True(t, expectedOutput == output)
 }
+
+func TestResolveParametersInTextWithInlineComments(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/db/host": {Name: "/app/db/host", Type: stringType, Value: "db host"},
+	})
+
+	text := "host={{ssm:/app/db/host <!-- primary endpoint -->}} quoted={{ ssm:/app/db/host | shellquote <!-- for sh --> }}"
+	output, err := ResolveParametersInText(&serviceObject, text, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "host=db host quoted='db host'", output)
+}
+
+func TestResolveParametersInTextWithCommentsBetweenModifiers(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/db/host": {Name: "/app/db/host", Type: stringType, Value: "db host"},
+	})
+
+	text := "{{ssm:/app/db/host | shellquote <!-- for sh | not a modifier --> | urlencode}}"
+	output, err := ResolveParametersInText(&serviceObject, text, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "%27db+host%27", output)
+}
@@ -0,0 +1,52 @@
+package resolver
+
+import (
+	"errors"
+	"syscall"
+	"time"
+)
+
+//
+// Delay before the first retry of a failed write when ResolveOptions.WriteRetryBackoff is not set
+const defaultWriteRetryBackoff = 100 * time.Millisecond
+
+//
+// Errors of file writes worth retrying, typical for NFS and EBS hiccups
+var transientWriteErrors = []error{
+	syscall.EINTR,
+	syscall.EAGAIN,
+	syscall.ESTALE,
+	syscall.EIO,
+}
+
+// runs write and retries it with exponential backoff according to ResolveOptions while it fails with a transient error
+func retryTransientWrite(options ResolveOptions, write func() error) error {
+	backoff := options.WriteRetryBackoff
+	if backoff <= 0 {
+		backoff = defaultWriteRetryBackoff
+	}
+
+	err := write()
+	for retry := 0; retry < options.WriteRetries && isTransientWriteError(err); retry++ {
+		time.Sleep(backoff)
+		backoff *= 2
+		err = write()
+	}
+
+	return err
+}
+
+func isTransientWriteError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, transient := range transientWriteErrors {
+		if errors.Is(err, transient) {
+			return true
+		}
+	}
+
+	var temporary interface{ Temporary() bool }
+	return errors.As(err, &temporary) && temporary.Temporary()
+}
@@ -0,0 +1,47 @@
+package resolver
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryTransientWrite(t *testing.T) {
+	attempts := 0
+	err := retryTransientWrite(ResolveOptions{WriteRetries: 3, WriteRetryBackoff: time.Millisecond}, func() error {
+		attempts++
+		if attempts < 3 {
+			return &os.PathError{Op: "write", Path: "/mnt/nfs/app.conf", Err: syscall.ESTALE}
+		}
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryTransientWriteGivesUp(t *testing.T) {
+	attempts := 0
+	err := retryTransientWrite(ResolveOptions{WriteRetries: 2, WriteRetryBackoff: time.Millisecond}, func() error {
+		attempts++
+		return syscall.EINTR
+	})
+
+	assert.Equal(t, syscall.EINTR, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryTransientWriteDoesNotRetryPermanentErrors(t *testing.T) {
+	attempts := 0
+	err := retryTransientWrite(ResolveOptions{WriteRetries: 5}, func() error {
+		attempts++
+		return errors.New("permission denied")
+	})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,97 @@
+package resolver
+
+import (
+	"errors"
+	"strings"
+)
+
+//
+// ValueSanitization selects what happens to resolved values holding characters that can break a document:
+// byte order marks, control characters other than tab and line breaks, and line breaks in a single-line context.
+// A placeholder is in a single-line context when its line holds other text, e.g. KEY={{ssm:/app/key}}.
+type ValueSanitization int
+
+const (
+	// Values are substituted as they are
+	SanitizeNone ValueSanitization = iota
+
+	// Byte order marks and control characters are removed and trailing line breaks are trimmed from values
+	// in single-line contexts; values still holding line breaks in single-line contexts fail
+	SanitizeStrip
+
+	// Values holding any of these characters fail
+	SanitizeReject
+)
+
+//
+// Byte order mark, left at the start of values pasted from files saved by some editors
+const byteOrderMark = "\uFEFF"
+
+// returns resolvedParametersMap with the values sanitized for the placeholders of text according to sanitization.
+// The values are not part of the errors, they may be secrets.
+func sanitizeResolvedValues(
+	text string,
+	resolvedParametersMap map[string]SsmParameterInfo,
+	sanitization ValueSanitization) (map[string]SsmParameterInfo, error) {
+
+	if sanitization == SanitizeNone {
+		return resolvedParametersMap, nil
+	}
+
+	singleLine := map[string]bool{}
+	for _, placeholder := range parameterPlaceholders() {
+		for _, match := range placeholder.FindAllStringSubmatchIndex(text, -1) {
+			lineStart := strings.LastIndex(text[:match[0]], "\n") + 1
+			lineEnd := len(text)
+			if end := strings.Index(text[match[1]:], "\n"); end >= 0 {
+				lineEnd = match[1] + end
+			}
+
+			if len(strings.TrimSpace(text[lineStart:match[0]]+text[match[1]:lineEnd])) > 0 {
+				singleLine[text[match[2]:match[3]]] = true
+			}
+		}
+	}
+
+	sanitizedParametersMap := make(map[string]SsmParameterInfo, len(resolvedParametersMap))
+	for ref, param := range resolvedParametersMap {
+		value, err := sanitizeValue(param.Value, singleLine[ref], sanitization)
+		if err != nil {
+			return nil, withStatus(StatusPolicyViolation, newParameterReferenceError(ref, "value of parameter reference {{%s}} %w", err))
+		}
+
+		param.Value = value
+		sanitizedParametersMap[ref] = param
+	}
+
+	return sanitizedParametersMap, nil
+}
+
+func sanitizeValue(value string, singleLine bool, sanitization ValueSanitization) (string, error) {
+	if sanitization == SanitizeStrip {
+		value = strings.Map(func(r rune) rune {
+			if r == '\uFEFF' || ((r < 0x20 || r == 0x7f) && !strings.ContainsRune("\t\n\r", r)) {
+				return -1
+			}
+			return r
+		}, value)
+
+		if singleLine {
+			value = strings.TrimRight(value, "\r\n")
+		}
+	}
+
+	if strings.Contains(value, byteOrderMark) {
+		return "", errors.New("holds a byte order mark")
+	}
+
+	if containsControlCharacter(value, "\t\n\r") {
+		return "", errors.New("holds control characters")
+	}
+
+	if singleLine && strings.ContainsAny(value, "\r\n") {
+		return "", errors.New("holds line breaks but is substituted into a single line")
+	}
+
+	return value, nil
+}
@@ -0,0 +1,34 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeValues(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/token": {Name: "/app/token", Type: stringType, Value: "\uFEFFs3cr3t\x00\n"},
+		"ssm:/app/cert":  {Name: "/app/cert", Type: stringType, Value: "line1\nline2\n"},
+	})
+	text := "TOKEN={{ssm:/app/token}}\n{{ssm:/app/cert}}\n"
+
+	output, err := ResolveParametersInText(&serviceObject, text, ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "TOKEN=\uFEFFs3cr3t\x00\n\nline1\nline2\n\n", output)
+
+	output, err = ResolveParametersInText(&serviceObject, text, ResolveOptions{SanitizeValues: SanitizeStrip})
+	assert.Nil(t, err)
+	assert.Equal(t, "TOKEN=s3cr3t\nline1\nline2\n\n", output)
+
+	_, err = ResolveParametersInText(&serviceObject, text, ResolveOptions{SanitizeValues: SanitizeReject})
+	assert.EqualError(t, err, "value of parameter reference {{ssm:/app/token}} holds a byte order mark")
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+
+	_, err = ResolveParametersInText(&serviceObject, "CERT={{ssm:/app/cert}}", ResolveOptions{SanitizeValues: SanitizeStrip})
+	assert.EqualError(t, err, "value of parameter reference {{ssm:/app/cert}} holds line breaks but is substituted into a single line")
+
+	output, err = ResolveParametersInText(&serviceObject, "  {{ssm:/app/cert}}", ResolveOptions{SanitizeValues: SanitizeReject})
+	assert.Nil(t, err)
+	assert.Equal(t, "  line1\nline2\n", output)
+}
@@ -0,0 +1,132 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+)
+
+//
+// SecretSink receives secure values instead of the documents, e.g. to store them in an OS keyring, as Docker secrets
+// or behind a credential process. The document gets the text returned by Deliver instead of the value, e.g. the name
+// of the keyring item or the path of the secret file.
+type SecretSink interface {
+	// Delivers the value of the secure parameter reference, e.g. ssm-secure:/app/db/password, and returns the text
+	// substituted for its placeholders
+	Deliver(ctx context.Context, parameterReference string, value string) (string, error)
+}
+
+//
+// Routes the secure parameters whose name matches Pattern (see path.Match), e.g. /app/*/password, to the sink named
+// Sink in ResolveOptions.SecretSinks
+type SecretSinkRoute struct {
+	Pattern string
+	Sink    string
+}
+
+//
+// Modifier selecting the sink of a secure placeholder by name, e.g. {{ssm-secure:/app/token | sink=keyring}}.
+// It takes precedence over ResolveOptions.SecretSinkRoutes.
+const sinkConstraint = "sink"
+
+// the sink is checked when the value is delivered, against the sinks of the resolve call
+func checkSink(value string, sink string) error {
+	if len(sink) == 0 {
+		return errors.New("sink name is empty")
+	}
+
+	return nil
+}
+
+// returns the sink selected by the placeholders of every parameter reference of texts that selects one
+func placeholderSinks(texts ...string) (map[string]string, error) {
+	sinks := map[string]string{}
+	for _, text := range texts {
+		if err := addPlaceholderSinks(sinks, text); err != nil {
+			return nil, err
+		}
+	}
+
+	return sinks, nil
+}
+
+// adds the sinks selected by the placeholders of text to sinks. Placeholders of one reference selecting different
+// sinks fail.
+func addPlaceholderSinks(sinks map[string]string, text string) error {
+	for _, placeholder := range parameterPlaceholders() {
+		for _, match := range placeholder.FindAllStringSubmatch(text, -1) {
+			for _, modifier := range parsePlaceholderModifiers(match[2]) {
+				name, argument, isConstraint := parseConstraintModifier(modifier)
+				if !isConstraint || name != sinkConstraint {
+					continue
+				}
+
+				if current, contains := sinks[match[1]]; contains && current != argument {
+					return withStatus(StatusParseError, errors.New("placeholders of parameter reference {{"+match[1]+
+						"}} select different sinks "+current+" and "+argument))
+				}
+				sinks[match[1]] = argument
+			}
+		}
+	}
+
+	return nil
+}
+
+// delivers the secure values of resolvedParametersMap selected by the placeholder sinks, or by SecretSinkRoutes,
+// to their SecretSink and returns the parameters with the texts returned by the sinks as values.
+// A value delivered to a sink is substituted into none of the placeholders of its reference.
+func deliverSecrets(
+	ctx context.Context,
+	resolvedParametersMap map[string]SsmParameterInfo,
+	sinks map[string]string,
+	options ResolveOptions) (map[string]SsmParameterInfo, error) {
+
+	if len(sinks) == 0 && len(options.SecretSinkRoutes) == 0 {
+		return resolvedParametersMap, nil
+	}
+
+	deliveredParametersMap := make(map[string]SsmParameterInfo, len(resolvedParametersMap))
+	for ref, param := range resolvedParametersMap {
+		deliveredParametersMap[ref] = param
+
+		sinkName, selected := sinks[ref]
+		if selected && param.Type != secureStringType {
+			return nil, withStatus(StatusPolicyViolation, errors.New("parameter reference {{"+ref+"}} is not secure, sink "+
+				sinkName+" only receives secure values"))
+		}
+
+		if !selected && param.Type == secureStringType {
+			for _, route := range options.SecretSinkRoutes {
+				matched, err := path.Match(route.Pattern, extractParameterNameFromReference(ref))
+				if err != nil {
+					return nil, fmt.Errorf("invalid secret sink route pattern %s: %w", route.Pattern, err)
+				}
+				if matched {
+					sinkName, selected = route.Sink, true
+					break
+				}
+			}
+		}
+
+		if !selected {
+			continue
+		}
+
+		sink, contains := lookupSecretSink(sinkName, options)
+		if !contains {
+			return nil, withStatus(StatusPolicyViolation, errors.New("unknown sink "+sinkName+" for parameter reference {{"+ref+"}}"))
+		}
+
+		delivered, err := sink.Deliver(ctx, ref, param.Value)
+		if err != nil {
+			return nil, newParameterReferenceError(ref, "cannot deliver parameter reference {{%s}} to sink %s: %w", sinkName, err)
+		}
+
+		param.Value = delivered
+		deliveredParametersMap[ref] = param
+	}
+
+	return deliveredParametersMap, nil
+}
@@ -0,0 +1,94 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//
+// Sink keeping the delivered values in memory, returning the item name for the documents
+type memorySink struct {
+	mutex  sync.Mutex
+	values map[string]string
+}
+
+func (s *memorySink) Deliver(ctx context.Context, parameterReference string, value string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	item := "item" + extractParameterNameFromReference(parameterReference)
+	s.values[item] = value
+	return item, nil
+}
+
+type failingSink struct{}
+
+func (failingSink) Deliver(ctx context.Context, parameterReference string, value string) (string, error) {
+	return "", errors.New("keyring is locked")
+}
+
+func newSinkServiceMock() ServiceMockedObjectWithRecords {
+	return NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host":               {Name: "/app/host", Type: stringType, Value: "example.com"},
+		"ssm-secure:/app/db/password": {Name: "/app/db/password", Type: secureStringType, Value: "s3cr3t"},
+		"ssm-secure:/app/api/token":   {Name: "/app/api/token", Type: secureStringType, Value: "t0ken"},
+		"ssm-secure:/app/tls/key":     {Name: "/app/tls/key", Type: secureStringType, Value: "k3y"},
+	})
+}
+
+func TestResolveParametersInTextWithSecretSinks(t *testing.T) {
+	serviceObject := newSinkServiceMock()
+	keyring := &memorySink{values: map[string]string{}}
+	files := &memorySink{values: map[string]string{}}
+
+	input := "host={{ssm:/app/host}}\npassword={{ssm-secure:/app/db/password | sink=keyring}}\n" +
+		"token={{ssm-secure:/app/api/token}}\nkey={{ ssm-secure:/app/tls/key | sink=files | shellquote }}"
+	resolved, err := ResolveParametersInText(&serviceObject, input, ResolveOptions{
+		SecretSinks:      map[string]SecretSink{"keyring": keyring, "files": files},
+		SecretSinkRoutes: []SecretSinkRoute{{Pattern: "/app/*/*", Sink: "keyring"}},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "host=example.com\npassword=item/app/db/password\ntoken=item/app/api/token\nkey='item/app/tls/key'", resolved)
+	assert.Equal(t, map[string]string{"item/app/db/password": "s3cr3t", "item/app/api/token": "t0ken"}, keyring.values)
+	assert.Equal(t, map[string]string{"item/app/tls/key": "k3y"}, files.values)
+}
+
+func TestSecretSinkFailures(t *testing.T) {
+	serviceObject := newSinkServiceMock()
+	options := ResolveOptions{SecretSinks: map[string]SecretSink{"keyring": failingSink{}}}
+
+	for input, status := range map[string]Status{
+		"{{ssm:/app/host | sink=keyring}}":                                                        StatusPolicyViolation,
+		"{{ssm-secure:/app/db/password | sink=vault}}":                                            StatusPolicyViolation,
+		"{{ssm-secure:/app/db/password | sink=keyring}} {{ssm-secure:/app/db/password | sink=x}}": StatusParseError,
+		"{{ssm-secure:/app/db/password | sink=keyring}}":                                          StatusGenericError,
+	} {
+		resolved, err := ResolveParametersInText(&serviceObject, input, options)
+
+		assert.NotNil(t, err, input)
+		assert.Equal(t, status, StatusOf(err), input)
+		assert.False(t, strings.Contains(resolved, "s3cr3t"), input)
+	}
+}
+
+func TestRenderSetWithSecretSinks(t *testing.T) {
+	serviceObject := newSinkServiceMock()
+	keyring := &memorySink{values: map[string]string{}}
+
+	set := NewRenderSet(&serviceObject, ResolveOptions{
+		SecretSinks:      map[string]SecretSink{"keyring": keyring},
+		SecretSinkRoutes: []SecretSinkRoute{{Pattern: "/app/db/*", Sink: "keyring"}},
+	})
+	assert.Nil(t, set.Add("a", "{{ssm-secure:/app/db/password}} {{ssm-secure:/app/api/token}}"))
+	rendered, err := set.Render()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "item/app/db/password t0ken", rendered["a"])
+	assert.Equal(t, map[string]string{"item/app/db/password": "s3cr3t"}, keyring.values)
+}
@@ -0,0 +1,65 @@
+package resolver
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+//
+// Retrieves secrets from Secrets Manager for {{secretsmanager:secret-id}} placeholders. A service implementing it
+// besides ISsmParameterService, e.g. Service, resolves documents mixing SSM and Secrets Manager references.
+type ISecretsManagerService interface {
+	callGetSecretValue(ctx context.Context, secretId string) (SsmParameterInfo, error)
+}
+
+//
+// This function retrieves the current version of the secret secretId, a name or an ARN.
+// Secrets are reported with the SecureString type, binary secrets with their value encoded in base64.
+func (s *Service) callGetSecretValue(ctx context.Context, secretId string) (SsmParameterInfo, error) {
+	if s.SecretsManagerClient == nil {
+		return SsmParameterInfo{}, errors.New("Secrets Manager client is not configured")
+	}
+
+	output, err := s.SecretsManagerClient.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretId),
+	})
+	if err != nil {
+		var awsError interface{ Code() string }
+		if errors.As(err, &awsError) && awsError.Code() == secretsmanager.ErrCodeResourceNotFoundException {
+			return SsmParameterInfo{}, newMissingParametersError([]string{secretsManagerPrefix + secretId})
+		}
+		return SsmParameterInfo{}, withStatus(StatusAwsError, err)
+	}
+
+	value := aws.StringValue(output.SecretString)
+	if output.SecretString == nil {
+		value = base64.StdEncoding.EncodeToString(output.SecretBinary)
+	}
+
+	return SsmParameterInfo{
+		Name:  aws.StringValue(output.Name),
+		Type:  secureStringType,
+		Value: value,
+	}, nil
+}
+
+// retrieves the secret secretId with service, which fails when it does not implement ISecretsManagerService.
+// Services wrapping another service delegate their callGetSecretValue to it with this function.
+func getSecretValue(ctx context.Context, service ISsmParameterService, secretId string) (SsmParameterInfo, error) {
+	secretsService, ok := service.(ISecretsManagerService)
+	if !ok {
+		return SsmParameterInfo{}, errors.New("service does not support Secrets Manager references")
+	}
+
+	return secretsService.callGetSecretValue(ctx, secretId)
+}
+
+// tells whether parameterReference refers to a Secrets Manager secret rather than an SSM parameter
+func isSecretReference(parameterReference string) bool {
+	return strings.HasPrefix(parameterReference, secretsManagerPrefix)
+}
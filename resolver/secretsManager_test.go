@@ -0,0 +1,131 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type secretsServiceMock struct {
+	ServiceMockedObjectWithRecords
+	secrets map[string]SsmParameterInfo
+
+	// secret ids requested with callGetSecretValue
+	requested []string
+}
+
+func (m *secretsServiceMock) callGetSecretValue(ctx context.Context, secretId string) (SsmParameterInfo, error) {
+	m.requested = append(m.requested, secretId)
+
+	secret, contains := m.secrets[secretId]
+	if !contains {
+		return SsmParameterInfo{}, newMissingParametersError([]string{secretsManagerPrefix + secretId})
+	}
+
+	return secret, nil
+}
+
+func newSecretsServiceMock() *secretsServiceMock {
+	return &secretsServiceMock{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+			"ssm:/app/db/host":         {Name: "/app/db/host", Type: stringType, Value: "db.example.com"},
+			"ssm-secure:/app/db/token": {Name: "/app/db/token", Type: secureStringType, Value: "token"},
+		}),
+		secrets: map[string]SsmParameterInfo{
+			"prod/app/db": {Name: "prod/app/db", Type: secureStringType, Value: "s3cr3t"},
+			"arn:aws:secretsmanager:us-east-1:123456789012:secret:prod/app/api-AbCdEf": {
+				Name: "prod/app/api", Type: secureStringType, Value: "api-key"},
+		},
+	}
+}
+
+func TestResolveParametersInTextWithSecrets(t *testing.T) {
+	serviceObject := newSecretsServiceMock()
+
+	input := "host={{ssm:/app/db/host}}\npassword={{ secretsmanager:prod/app/db | shellquote }}\n" +
+		"token={{ssm-secure:/app/db/token}}\napi={{secretsmanager:arn:aws:secretsmanager:us-east-1:123456789012:secret:prod/app/api-AbCdEf}}"
+	resolved, err := ResolveParametersInText(serviceObject, input, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "host=db.example.com\npassword='s3cr3t'\ntoken=token\napi=api-key", resolved)
+}
+
+func TestResolveParametersInTextIgnoresSecretsWithSecureParameters(t *testing.T) {
+	serviceObject := newSecretsServiceMock()
+
+	input := "host={{ssm:/app/db/host}}\npassword={{secretsmanager:prod/app/db}}"
+	resolved, err := ResolveParametersInText(serviceObject, input, ResolveOptions{IgnoreSecureParameters: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "host=db.example.com\npassword={{secretsmanager:prod/app/db}}", resolved)
+	assert.Empty(t, serviceObject.requested)
+}
+
+func TestResolveParametersInTextReportsMissingSecrets(t *testing.T) {
+	serviceObject := &secretsServiceMock{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{}),
+		secrets: map[string]SsmParameterInfo{
+			"prod/app/db": {Name: "prod/app/db", Type: secureStringType, Value: "s3cr3t"},
+		},
+	}
+
+	_, err := ResolveParametersInText(serviceObject, "{{secretsmanager:prod/app/db}} {{secretsmanager:prod/app/gone}}", ResolveOptions{})
+
+	var missingParametersError *MissingParametersError
+	assert.True(t, errors.As(err, &missingParametersError))
+	assert.Equal(t, []string{"secretsmanager:prod/app/gone"}, missingParametersError.Names)
+	assert.Equal(t, StatusNotFound, StatusOf(err))
+}
+
+func TestResolveParametersInTextWithSecretsNotSupported(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+
+	_, err := ResolveParametersInText(&serviceObject, "{{secretsmanager:prod/app/db}}", ResolveOptions{})
+
+	assert.NotNil(t, err)
+}
+
+func TestSecretsAreCachedApartFromParameters(t *testing.T) {
+	serviceObject := newSecretsServiceMock()
+	serviceObject.secrets["/app/db/host"] = SsmParameterInfo{Name: "/app/db/host", Type: secureStringType, Value: "secret-host"}
+	options := ResolveOptions{Cache: NewMemoryCache(time.Minute)}
+
+	for i := 0; i < 2; i++ {
+		resolved, err := ResolveParametersInText(serviceObject, "{{ssm:/app/db/host}} {{secretsmanager:/app/db/host}}", options)
+		assert.Nil(t, err)
+		assert.Equal(t, "db.example.com secret-host", resolved)
+	}
+	assert.Equal(t, []string{"/app/db/host"}, serviceObject.requested)
+}
+
+func TestRenderSetWithSecrets(t *testing.T) {
+	serviceObject := newSecretsServiceMock()
+	serviceObject.records["ssm:/app/dsn"] = SsmParameterInfo{Name: "/app/dsn", Type: stringType, Value: "postgres://app:{{secretsmanager:prod/app/db}}@db"}
+
+	set := NewRenderSet(NewAdaptiveService(serviceObject, AdaptiveConcurrencyOptions{}), ResolveOptions{Recursive: true})
+	assert.Nil(t, set.Add("a", "dsn={{ssm:/app/dsn}}"))
+	assert.Nil(t, set.Add("b", "dsn={{ssm:/app/dsn}} password={{secretsmanager:prod/app/db}}"))
+	rendered, err := set.Render()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "dsn=postgres://app:s3cr3t@db", rendered["a"])
+	assert.Equal(t, "dsn=postgres://app:s3cr3t@db password=s3cr3t", rendered["b"])
+	assert.Equal(t, []string{"prod/app/db"}, serviceObject.requested)
+}
+
+func TestPreflightWithSecrets(t *testing.T) {
+	serviceObject := newSecretsServiceMock()
+
+	report, err := Preflight(context.Background(), serviceObject, nil,
+		[]string{"host={{ssm:/app/db/host}} password={{secretsmanager:prod/app/db}}"}, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(report.References))
+	for _, result := range report.References {
+		assert.Nil(t, result.Err)
+	}
+	assert.Nil(t, report.TemplateErrors[0])
+}
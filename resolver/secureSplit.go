@@ -0,0 +1,159 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//
+// Permissions of the output file holding lines with secure parameters
+const secureOutputFilePermissions = 0600
+
+//
+// Reads inputFileName and resolves SSM parameters in it according to ResolveOptions, splitting the result in two:
+// every line referencing a secure parameter or a secret is stored in secureOutputFileName (readable by the owner only)
+// and all the other lines are stored in outputFileName. In place of the first secure line the outputFileName gets
+// includeDirective formatted with secureOutputFileName, e.g. "include %s;" for nginx or "include '%s'" for postgres.
+// includeDirective must hold exactly one %s verb, a literal % is written %%. The secure lines are the ones holding
+// the placeholders ResolveOptions.IgnoreSecureParameters leaves unresolved.
+func ResolveParametersInFileWithSecureSplit(
+	service ISsmParameterService,
+	inputFileName string,
+	outputFileName string,
+	secureOutputFileName string,
+	includeDirective string,
+	options ResolveOptions) error {
+
+	return ResolveParametersInFileWithSecureSplitWithContext(context.Background(), service, inputFileName, outputFileName, secureOutputFileName, includeDirective, options)
+}
+
+//
+// Same as ResolveParametersInFileWithSecureSplit, but the SSM requests are canceled when ctx is done.
+func ResolveParametersInFileWithSecureSplitWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	inputFileName string,
+	outputFileName string,
+	secureOutputFileName string,
+	includeDirective string,
+	options ResolveOptions) error {
+
+	if len(outputFileName) == 0 {
+		return errors.New("output file name is not provided")
+	}
+
+	if len(secureOutputFileName) == 0 {
+		return errors.New("secure output file name is not provided")
+	}
+
+	if err := validateIncludeDirective(includeDirective); err != nil {
+		return err
+	}
+
+	if isSameFile(secureOutputFileName, inputFileName) || isSameFile(secureOutputFileName, outputFileName) {
+		return errors.New("secure output file " + secureOutputFileName + " is the same file as the input or the output file")
+	}
+
+	// resolving a template into itself replaces it atomically, it is never left truncated
+	inPlace := isSameFile(inputFileName, outputFileName)
+
+	unresolvedText, err := readValidatedTextFromFile(inputFileName)
+	if err != nil {
+		return err
+	}
+
+	unresolvedText, err = prepareTemplate(ctx, service, unresolvedText, options)
+	if err != nil {
+		return err
+	}
+
+	options.allowBinaryValues = true
+	resolvedParametersMap, err := ExtractParametersFromTextWithContext(ctx, service, unresolvedText, withDefaultPlaceholderSyntax(options))
+	if err != nil {
+		return err
+	}
+
+	warnAboutResolvedValues(unresolvedText, outputFileName, resolvedParametersMap, options)
+
+	publicLines := []string{}
+	secureLines := []string{}
+	for _, line := range strings.Split(unresolvedText, "\n") {
+		resolvedLine, err := replaceParameterPlaceholders(line, resolvedParametersMap)
+		if err != nil {
+			return err
+		}
+		if err := checkUnresolvedPlaceholders(resolvedLine, options); err != nil {
+			return err
+		}
+		resolvedLine = restorePlaceholderDelimiters(resolvedLine, options.PlaceholderSyntax)
+
+		if !hasSecurePlaceholders(line) {
+			publicLines = append(publicLines, resolvedLine)
+			continue
+		}
+
+		if len(secureLines) == 0 {
+			publicLines = append(publicLines, fmt.Sprintf(includeDirective, secureOutputFileName))
+		}
+		secureLines = append(secureLines, resolvedLine)
+	}
+
+	secureText, err := applyPostRenderFilters(secureOutputFileName, strings.Join(secureLines, "\n"), options.PostRenderFilters)
+	if err != nil {
+		return err
+	}
+
+	publicText, err := applyPostRenderFilters(outputFileName, strings.Join(publicLines, "\n"), options.PostRenderFilters)
+	if err != nil {
+		return err
+	}
+
+	err = retryTransientWrite(options, func() error {
+		return writeToFileWithPermissions(secureText, secureOutputFileName, secureOutputFilePermissions)
+	})
+	if err != nil {
+		return err
+	}
+
+	return retryTransientWrite(options, func() error {
+		if inPlace {
+			return writeToFileAtomically(publicText, outputFileName, options.BackupSuffix)
+		}
+		return writeToFile(publicText, outputFileName)
+	})
+}
+
+// checks that the include directive formats the secure output file name only: one %s verb, any other % escaped as %%
+func validateIncludeDirective(includeDirective string) error {
+	verbs := 0
+	for i := 0; i < len(includeDirective); i++ {
+		if includeDirective[i] != '%' {
+			continue
+		}
+
+		i++
+		switch {
+		case i < len(includeDirective) && includeDirective[i] == '%':
+		case i < len(includeDirective) && includeDirective[i] == 's':
+			verbs++
+		default:
+			return withStatus(StatusParseError, errors.New("include directive "+includeDirective+" can only hold the %s and %% verbs"))
+		}
+	}
+
+	if verbs != 1 {
+		return withStatus(StatusParseError, errors.New("include directive "+includeDirective+" must hold exactly one %s verb"))
+	}
+
+	return nil
+}
+
+// reports whether line holds placeholders left unresolved with ResolveOptions.IgnoreSecureParameters
+func hasSecurePlaceholders(line string) bool {
+	allReferences, _ := parseParametersFromTextIntoDedupedSlice(line, false)
+	nonSecureReferences, _ := parseParametersFromTextIntoDedupedSlice(line, true)
+
+	return len(allReferences) > len(nonSecureReferences)
+}
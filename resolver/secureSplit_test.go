@@ -0,0 +1,105 @@
+package resolver
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersInFileWithSecureSplit(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host":           {Name: "/app/host", Type: stringType, Value: "example.com"},
+		"ssm-secure:/app/db/pass": {Name: "/app/db/pass", Type: secureStringType, Value: "s3cr3t"},
+	})
+
+	dir, err := ioutil.TempDir("", "secure-split")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "app.conf")
+	outputFileName := filepath.Join(dir, "app.resolved.conf")
+	secureOutputFileName := filepath.Join(dir, "app.secrets.conf")
+
+	input := "server_name {{ssm:/app/host}};\npassword {{ssm-secure:/app/db/pass}};\nlisten 443;"
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte(input), 0644))
+
+	err = ResolveParametersInFileWithSecureSplit(&serviceObject, inputFileName, outputFileName, secureOutputFileName, "include %s;", ResolveOptions{})
+	assert.Nil(t, err)
+
+	output, err := ioutil.ReadFile(outputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, "server_name example.com;\ninclude "+secureOutputFileName+";\nlisten 443;", string(output))
+
+	secureOutput, err := ioutil.ReadFile(secureOutputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, "password s3cr3t;", string(secureOutput))
+
+	stats, err := os.Stat(secureOutputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(secureOutputFilePermissions), stats.Mode().Perm())
+}
+
+func TestResolveParametersInFileWithSecureSplitOverInput(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm-secure:/app/db/pass": {Name: "/app/db/pass", Type: secureStringType, Value: "s3cr3t"},
+	})
+
+	dir, err := ioutil.TempDir("", "secure-split")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "app.conf")
+	input := "password {{ssm-secure:/app/db/pass}};"
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte(input), 0644))
+
+	err = ResolveParametersInFileWithSecureSplit(&serviceObject, inputFileName, filepath.Join(dir, "app.resolved.conf"), inputFileName,
+		"include %s;", ResolveOptions{})
+	assert.NotNil(t, err)
+
+	output, err := ioutil.ReadFile(inputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, input, string(output))
+}
+
+func TestResolveParametersInFileWithSecureSplitSecureSources(t *testing.T) {
+	fetch := func(ctx context.Context, name string) (string, error) { return "value of " + name, nil }
+	assert.Nil(t, RegisterSource("splitvault:", Source{Fetch: fetch}))
+	assert.Nil(t, RegisterSource("splitvault-secure:", Source{Fetch: fetch, Secure: true}))
+	defer unregisterSource("splitvault:")
+	defer unregisterSource("splitvault-secure:")
+
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+
+	dir, err := ioutil.TempDir("", "secure-split")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "app.conf")
+	outputFileName := filepath.Join(dir, "app.resolved.conf")
+	secureOutputFileName := filepath.Join(dir, "app.secrets.conf")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("user {{splitvault:user}};\npassword {{splitvault-secure:password}};"), 0644))
+
+	err = ResolveParametersInFileWithSecureSplit(&serviceObject, inputFileName, outputFileName, secureOutputFileName, "include %s; # 100%%", ResolveOptions{})
+	assert.Nil(t, err)
+
+	output, err := ioutil.ReadFile(outputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, "user value of user;\ninclude "+secureOutputFileName+"; # 100%", string(output))
+
+	secureOutput, err := ioutil.ReadFile(secureOutputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, "password value of password;", string(secureOutput))
+}
+
+func TestResolveParametersInFileWithSecureSplitIncludeDirective(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+
+	for _, includeDirective := range []string{"include;", "include %s %s;", "include %v;", "include %d%s;", "include %s %"} {
+		err := ResolveParametersInFileWithSecureSplit(&serviceObject, "input", "output", "secrets", includeDirective, ResolveOptions{})
+		assert.NotNil(t, err, includeDirective)
+	}
+}
@@ -0,0 +1,94 @@
+package resolver
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+//
+// SecurityProfile bundles the guardrails against hostile templates and parameter values into one option,
+// so that a policy can mandate a profile instead of a combination of options. A profile only tightens
+// the other options: settings stricter than the profile are kept.
+type SecurityProfile int
+
+const (
+	// Nothing beyond the other options is enforced
+	SecurityPermissive SecurityProfile = iota
+
+	// Placeholders in shell command contexts must use the shellquote transformer (like StrictShellContexts)
+	// and templates are bounded by the standard limits
+	SecurityStandard
+
+	// Standard, with tighter limits, only the escaping transformers and constraints in placeholders,
+	// no recursive resolution and failure when a parameter changes version while being resolved
+	SecurityStrict
+)
+
+//
+// Limits enforced by the profiles, the limits of ResolveOptions apply where they are stricter
+var securityProfileLimits = map[SecurityProfile]ResolveLimits{
+	SecurityStandard: {MaxParseTime: 10 * time.Second, MaxPlaceholders: 10000, MaxExpansionFactor: 100},
+	SecurityStrict:   {MaxParseTime: time.Second, MaxPlaceholders: 1000, MaxExpansionFactor: 10},
+}
+
+//
+// Transformers allowed in placeholders by SecurityStrict, constraints are always allowed
+var strictProfileTransformers = escapingTransformers
+
+// returns the limits of options tightened by the limits of their security profile
+func effectiveLimits(options ResolveOptions) ResolveLimits {
+	limits := options.Limits
+	profileLimits, limited := securityProfileLimits[options.SecurityProfile]
+	if !limited {
+		return limits
+	}
+
+	if limits.MaxParseTime <= 0 || limits.MaxParseTime > profileLimits.MaxParseTime {
+		limits.MaxParseTime = profileLimits.MaxParseTime
+	}
+	if limits.MaxPlaceholders <= 0 || limits.MaxPlaceholders > profileLimits.MaxPlaceholders {
+		limits.MaxPlaceholders = profileLimits.MaxPlaceholders
+	}
+	if limits.MaxExpansionFactor <= 0 || limits.MaxExpansionFactor > profileLimits.MaxExpansionFactor {
+		limits.MaxExpansionFactor = profileLimits.MaxExpansionFactor
+	}
+
+	return limits
+}
+
+// reports whether placeholders in shell command contexts must use the shellquote transformer
+func checksShellContexts(options ResolveOptions) bool {
+	return options.StrictShellContexts || options.SecurityProfile >= SecurityStandard
+}
+
+// reports whether a parameter changing version while being resolved fails the resolution
+func failsOnVersionChange(options ResolveOptions) bool {
+	return options.FailOnVersionChange || options.SecurityProfile >= SecurityStrict
+}
+
+// fails when options or the placeholders of text use what the security profile of options does not allow
+func checkSecurityProfile(text string, options ResolveOptions) error {
+	if options.SecurityProfile < SecurityStrict {
+		return nil
+	}
+
+	if options.Recursive {
+		return withStatus(StatusPolicyViolation, errors.New("recursive resolution is not allowed by the strict security profile"))
+	}
+
+	for _, placeholder := range parameterPlaceholders() {
+		for _, match := range placeholder.FindAllStringSubmatch(text, -1) {
+			for _, name := range parsePlaceholderModifiers(match[2]) {
+				constraintName, _, isConstraint := parseConstraintModifier(name)
+				if _, isCheck := constraints[constraintName]; (isConstraint && isCheck) || containsString(strictProfileTransformers, name) {
+					continue
+				}
+				return withStatus(StatusPolicyViolation, errors.New("transformer "+name+" in placeholder "+match[0]+
+					" is not allowed by the strict security profile, only "+strings.Join(strictProfileTransformers, ",")+" are"))
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package resolver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityProfileStandard(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/dir": {Name: "/app/dir", Type: stringType, Value: "/tmp"},
+	})
+
+	_, err := ResolveParametersInText(&serviceObject, "ls $(echo {{ssm:/app/dir}})", ResolveOptions{})
+	assert.Nil(t, err)
+
+	_, err = ResolveParametersInText(&serviceObject, "ls $(echo {{ssm:/app/dir}})", ResolveOptions{SecurityProfile: SecurityStandard})
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+
+	output, err := ResolveParametersInText(&serviceObject, "ls $(echo {{ssm:/app/dir | shellquote}})", ResolveOptions{SecurityProfile: SecurityStandard})
+	assert.Nil(t, err)
+	assert.Equal(t, "ls $(echo '/tmp')", output)
+}
+
+func TestSecurityProfileStrict(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+	})
+	options := ResolveOptions{SecurityProfile: SecurityStrict}
+
+	output, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/host | urlencode | max-age=5m}}", options)
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com", output)
+
+	_, err = ResolveParametersInText(&serviceObject, "{{ssm:/app/host | oneline}}", options)
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+
+	options.Recursive = true
+	_, err = ResolveParametersInText(&serviceObject, "{{ssm:/app/host}}", options)
+	assert.Equal(t, StatusPolicyViolation, StatusOf(err))
+}
+
+func TestEffectiveLimits(t *testing.T) {
+	assert.Equal(t, ResolveLimits{MaxPlaceholders: 5}, effectiveLimits(ResolveOptions{Limits: ResolveLimits{MaxPlaceholders: 5}}))
+
+	assert.Equal(t, ResolveLimits{MaxParseTime: time.Second, MaxPlaceholders: 5, MaxExpansionFactor: 10}, effectiveLimits(ResolveOptions{
+		Limits:          ResolveLimits{MaxPlaceholders: 5, MaxExpansionFactor: 50},
+		SecurityProfile: SecurityStrict,
+	}))
+}
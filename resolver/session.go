@@ -0,0 +1,114 @@
+package resolver
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+//
+// SessionService remembers the non-secure parameters fetched through it for a short window and serves them again to
+// the calls made within it, so that bursts of resolve calls referencing the same parameters, e.g. the templates of
+// a deployment rendered one by one, do not request them again. Unlike a Cache it needs no options, keeps no secure
+// values and does not honour max-age constraints: keep the window to seconds.
+type SessionService struct {
+	service ISsmParameterService
+	window  time.Duration
+	now     func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+//
+// Wraps service into a SessionService remembering the non-secure parameters it fetches for window.
+func NewSessionService(service ISsmParameterService, window time.Duration) *SessionService {
+	return &SessionService{
+		service: service,
+		window:  window,
+		now:     time.Now,
+		entries: map[string]memoryCacheEntry{},
+	}
+}
+
+func (s *SessionService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	parameters := map[string]SsmParameterInfo{}
+	referencesToFetch := []string{}
+	for _, ref := range parameterReferences {
+		if param, found := s.get(ref); found {
+			parameters[ref] = param
+		} else {
+			referencesToFetch = append(referencesToFetch, ref)
+		}
+	}
+
+	if len(referencesToFetch) == 0 {
+		return parameters, nil
+	}
+
+	fetched, err := s.service.callGetParameters(ctx, referencesToFetch)
+	for ref, param := range fetched {
+		parameters[ref] = param
+		s.set(ref, param)
+	}
+
+	return parameters, err
+}
+
+func (s *SessionService) callGetParametersByPath(ctx context.Context, path string, recursive bool) ([]SsmParameterInfo, error) {
+	return s.service.callGetParametersByPath(ctx, path, recursive)
+}
+
+func (s *SessionService) callGetParameterMetadata(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	return getParameterMetadata(ctx, s.service, parameterReferences)
+}
+
+func (s *SessionService) callGetPublicParameter(ctx context.Context, parameterReference string) (SsmParameterInfo, error) {
+	if param, found := s.get(parameterReference); found {
+		return param, nil
+	}
+
+	param, err := getPublicParameter(ctx, s.service, parameterReference)
+	if err == nil {
+		s.set(parameterReference, param)
+	}
+
+	return param, err
+}
+
+func (s *SessionService) callGetSecretValue(ctx context.Context, secretId string) (SsmParameterInfo, error) {
+	return getSecretValue(ctx, s.service, secretId)
+}
+
+// returns the parameter of parameterReference when it was fetched less than the window ago
+func (s *SessionService) get(parameterReference string) (SsmParameterInfo, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, found := s.entries[parameterReference]
+	if !found || s.now().Sub(entry.fetchedAt) >= s.window {
+		return SsmParameterInfo{}, false
+	}
+
+	return entry.param, true
+}
+
+// remembers param unless it is secure, dropping the parameters fetched more than the window ago
+func (s *SessionService) set(parameterReference string, param SsmParameterInfo) {
+	if param.Type == secureStringType || strings.HasPrefix(parameterReference, ssmSecurePrefix) {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	for ref, entry := range s.entries {
+		if now.Sub(entry.fetchedAt) >= s.window {
+			delete(s.entries, ref)
+		}
+	}
+
+	s.entries[parameterReference] = memoryCacheEntry{param: param, fetchedAt: now}
+}
@@ -0,0 +1,43 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionServiceServesRecentParameters(t *testing.T) {
+	serviceObject := &countingService{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+			"ssm:/app/host":     {Name: "/app/host", Type: stringType, Value: "example.com"},
+			"ssm-secure:/app/p": {Name: "/app/p", Type: secureStringType, Value: "s3cr3t"},
+		}),
+	}
+
+	now := time.Now()
+	session := NewSessionService(serviceObject, 5*time.Second)
+	session.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		resolved, err := ResolveParametersInTextWithContext(context.Background(), session, "{{ssm:/app/host}}", ResolveOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, "example.com", resolved)
+	}
+	assert.Equal(t, 1, serviceObject.calls)
+
+	// secure parameters are fetched every time
+	for i := 0; i < 2; i++ {
+		resolved, err := ResolveParametersInTextWithContext(context.Background(), session, "{{ssm-secure:/app/p}}", ResolveOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, "s3cr3t", resolved)
+	}
+	assert.Equal(t, 3, serviceObject.calls)
+	assert.Equal(t, 1, len(session.entries))
+
+	now = now.Add(5 * time.Second)
+	_, err := ResolveParametersInTextWithContext(context.Background(), session, "{{ssm:/app/host}}", ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, serviceObject.calls)
+}
@@ -0,0 +1,62 @@
+package resolver
+
+import "strings"
+
+//
+// Returns every placeholder in text that appears inside a shell command context - backticks, $() or
+// an eval line - and does not use the shellquote transformer. Substituting a raw parameter value
+// into such a context allows command injection through the value.
+func FindUnquotedPlaceholdersInShellContexts(text string) []string {
+	result := []string{}
+
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		isEvalLine := len(fields) > 0 && fields[0] == "eval"
+
+		for _, placeholder := range parameterPlaceholders() {
+			for _, match := range placeholder.FindAllStringSubmatchIndex(line, -1) {
+				if hasTransformer(line[match[4]:match[5]], shellQuoteTransformer) {
+					continue
+				}
+
+				start := match[0]
+				if isEvalLine || isInsideBackticks(line, start) || isInsideCommandSubstitution(line, start) {
+					result = append(result, line[match[0]:match[1]])
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func hasTransformer(modifiers string, name string) bool {
+	for _, modifier := range parsePlaceholderModifiers(modifiers) {
+		if modifier == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checks if position pos of line is between a pair of backticks
+func isInsideBackticks(line string, pos int) bool {
+	return strings.Count(line[:pos], "`")%2 == 1
+}
+
+// checks if position pos of line is inside an unclosed $( ... )
+func isInsideCommandSubstitution(line string, pos int) bool {
+	depth := 0
+	for i := 0; i < pos; i++ {
+		switch {
+		case strings.HasPrefix(line[i:], "$("):
+			depth++
+			i++
+		case line[i] == ')' && depth > 0:
+			depth--
+		}
+	}
+
+	return depth > 0
+}
@@ -0,0 +1,47 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindUnquotedPlaceholdersInShellContexts(t *testing.T) {
+	text := "echo {{ssm:plain}}\n" +
+		"x=`cat {{ssm:backticks}}`\n" +
+		"y=$(echo $(id) {{ssm-secure:substitution}})\n" +
+		"z=$(echo {{ssm:quoted | shellquote}})\n" +
+		"eval {{ssm:evaluated}}\n" +
+		"after=$(date) {{ssm:outside}}"
+
+	placeholders := FindUnquotedPlaceholdersInShellContexts(text)
+
+	assert.ElementsMatch(t, []string{"{{ssm:backticks}}", "{{ssm-secure:substitution}}", "{{ssm:evaluated}}"}, placeholders)
+}
+
+func TestResolveParametersInTextStrictShellContexts(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/dir": {Name: "/app/dir", Type: stringType, Value: "it's; rm -rf /"},
+	})
+
+	_, err := ResolveParametersInText(&serviceObject, "ls $(echo {{ssm:/app/dir}})", ResolveOptions{
+		StrictShellContexts: true,
+	})
+	assert.NotNil(t, err)
+
+	output, err := ResolveParametersInText(&serviceObject, "ls $(echo {{ssm:/app/dir | shellquote}})", ResolveOptions{
+		StrictShellContexts: true,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "ls $(echo 'it'\\''s; rm -rf /')", output)
+}
+
+func TestResolveParametersInTextUnknownTransformer(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/dir": {Name: "/app/dir", Type: stringType, Value: "/tmp"},
+	})
+
+	_, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/dir | nosuchtransformer}}", ResolveOptions{})
+
+	assert.NotNil(t, err)
+}
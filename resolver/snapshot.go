@@ -0,0 +1,160 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+//
+// Snapshot of SSM parameters, the format produced by the export API.
+type Snapshot struct {
+	Parameters []SsmParameterInfo
+}
+
+//
+// SnapshotService resolves parameters from a Snapshot instead of SSM Parameter Store so that
+// templates render identically in air-gapped environments.
+type SnapshotService struct {
+	parameters map[string]SsmParameterInfo
+}
+
+//
+// Creates a SnapshotService from a JSON encoded Snapshot read from reader.
+func NewSnapshotService(reader io.Reader) (*SnapshotService, error) {
+	snapshot, err := decodeSnapshot(context.Background(), reader, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSnapshotService(snapshot), nil
+}
+
+//
+// Creates a SnapshotService from a JSON encoded EncryptedSnapshot read from reader,
+// the data key of the snapshot is decrypted by keyService.
+func NewEncryptedSnapshotService(reader io.Reader, keyService IKmsDataKeyService) (*SnapshotService, error) {
+	snapshot, err := decodeSnapshot(context.Background(), reader, keyService)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSnapshotService(snapshot), nil
+}
+
+// decodes a JSON encoded Snapshot, or an EncryptedSnapshot when keyService is provided, decompressing them
+func decodeSnapshot(ctx context.Context, reader io.Reader, keyService IKmsDataKeyService) (*Snapshot, error) {
+	var snapshot Snapshot
+
+	reader, err := decompressingReader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyService == nil {
+		if err := json.NewDecoder(reader).Decode(&snapshot); err != nil {
+			return nil, fmt.Errorf("invalid parameter snapshot: %w", err)
+		}
+		return &snapshot, nil
+	}
+
+	var encrypted EncryptedSnapshot
+	if err := json.NewDecoder(reader).Decode(&encrypted); err != nil {
+		return nil, fmt.Errorf("invalid encrypted parameter snapshot: %w", err)
+	}
+
+	plaintext, err := decryptWithDataKey(ctx, keyService, &encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err = decompress(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(plaintext, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid parameter snapshot: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+func newSnapshotService(snapshot *Snapshot) *SnapshotService {
+	service := &SnapshotService{
+		parameters: map[string]SsmParameterInfo{},
+	}
+	for _, param := range snapshot.Parameters {
+		service.parameters[param.Name] = param
+	}
+
+	return service
+}
+
+//
+// Creates a SnapshotService from a JSON encoded Snapshot stored in fileName.
+func NewSnapshotServiceFromFile(fileName string) (*SnapshotService, error) {
+	err := validateFileAndSize(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return NewSnapshotService(file)
+}
+
+//
+// This function takes a list of ssm parameter name references like (ssm:name) and looks them up in the snapshot.
+// It returns a map<param-ref, SsmParameterInfo>.
+func (s *SnapshotService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	resolvedParametersMap := map[string]SsmParameterInfo{}
+	invalidParameters := []string{}
+
+	for _, ref := range parameterReferences {
+		name := extractParameterNameFromReference(ref)
+		parameterName, version, label := splitParameterSelector(name)
+		param, contains := s.parameters[parameterName]
+		if !contains || (version > 0 && param.Version != version) || (len(label) > 0 && param.Label != label) {
+			invalidParameters = append(invalidParameters, name)
+			continue
+		}
+		resolvedParametersMap[ref] = param
+	}
+
+	if len(invalidParameters) > 0 {
+		return nil, newMissingParametersError(invalidParameters)
+	}
+
+	return resolvedParametersMap, nil
+}
+
+//
+// This function returns all snapshot parameters under path.
+func (s *SnapshotService) callGetParametersByPath(ctx context.Context, path string, recursive bool) ([]SsmParameterInfo, error) {
+	parameters := []SsmParameterInfo{}
+	for name, param := range s.parameters {
+		if isParameterUnderPath(name, path, recursive) {
+			parameters = append(parameters, param)
+		}
+	}
+
+	return parameters, nil
+}
+
+// checks if parameter name is a child of path (or any descendant if recursive)
+func isParameterUnderPath(name string, path string, recursive bool) bool {
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+
+	return recursive || !strings.Contains(name[len(prefix):], "/")
+}
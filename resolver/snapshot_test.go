@@ -0,0 +1,55 @@
+package resolver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSnapshot = `{
+  "Parameters": [
+    {"Name": "/app/host", "Type": "String", "Value": "example.com"},
+    {"Name": "/app/password", "Type": "SecureString", "Value": "s3cr3t"}
+  ]
+}`
+
+func TestSnapshotService(t *testing.T) {
+	service, err := NewSnapshotService(strings.NewReader(testSnapshot))
+	assert.Nil(t, err)
+
+	output, err := ResolveParametersInText(service, "{{ssm:/app/host}}:{{ssm-secure:/app/password}}", ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com:s3cr3t", output)
+}
+
+func TestSnapshotServiceMissingParameter(t *testing.T) {
+	service, err := NewSnapshotService(strings.NewReader(testSnapshot))
+	assert.Nil(t, err)
+
+	_, err = ResolveParametersInText(service, "{{ssm:/app/port}}", ResolveOptions{})
+
+	assert.NotNil(t, err)
+}
+
+func TestSnapshotServiceSelectors(t *testing.T) {
+	service, err := NewSnapshotService(strings.NewReader(`{"Parameters": [{"Name": "/app/host", "Type": "String", "Value": "example.com", "Version": 4, "Label": "stable"}]}`))
+	assert.Nil(t, err)
+
+	output, err := ResolveParametersInText(service, "{{ssm:/app/host:4}} {{ssm:/app/host:stable}}", ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com example.com", output)
+
+	_, err = ResolveParametersInText(service, "{{ssm:/app/host:3}}", ResolveOptions{})
+	assert.EqualError(t, err, "The following parameter(s) cannot be resolved: /app/host:3")
+
+	_, err = ResolveParametersInText(service, "{{ssm:/app/host:prod}}", ResolveOptions{})
+	assert.EqualError(t, err, "The following parameter(s) cannot be resolved: /app/host:prod")
+}
+
+func TestSnapshotServiceInvalidSnapshot(t *testing.T) {
+	_, err := NewSnapshotService(strings.NewReader("not json"))
+
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,147 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type SoakOptions struct {
+	// Number of goroutines rendering the corpus concurrently, 1 when zero
+	Concurrency int
+
+	// The soak test stops after Duration or after MaxRenders renders, whichever comes first.
+	// At least one of them has to be set.
+	Duration   time.Duration
+	MaxRenders int64
+
+	// Options every document of the corpus is rendered with
+	ResolveOptions ResolveOptions
+}
+
+//
+// Result of SoakTest
+type SoakReport struct {
+	Renders int64
+	Errors  int64
+	Elapsed time.Duration
+
+	// Parameter Store consumption: GetParameters requests (the API the TPS quota applies to)
+	// and parameters requested by them
+	GetParametersCalls int64
+	ParametersFetched  int64
+
+	// First error of a failed render
+	FirstError error
+}
+
+func (report SoakReport) RendersPerSecond() float64 {
+	return perSecond(report.Renders, report.Elapsed)
+}
+
+func (report SoakReport) GetParametersCallsPerSecond() float64 {
+	return perSecond(report.GetParametersCalls, report.Elapsed)
+}
+
+func (report SoakReport) ErrorRate() float64 {
+	if report.Renders == 0 {
+		return 0
+	}
+	return float64(report.Errors) / float64(report.Renders)
+}
+
+//
+// Renders the documents of corpus with ResolveParametersInText over and over with SoakOptions.Concurrency
+// goroutines and reports throughput, errors and Parameter Store consumption, to size rate limits before
+// rolling out to a fleet. The returned error is set only for invalid options.
+func SoakTest(
+	ctx context.Context,
+	service ISsmParameterService,
+	corpus []string,
+	options SoakOptions) (SoakReport, error) {
+
+	if len(corpus) == 0 {
+		return SoakReport{}, errors.New("corpus is empty")
+	}
+
+	if options.Duration <= 0 && options.MaxRenders <= 0 {
+		return SoakReport{}, errors.New("either duration or the maximum number of renders has to be set")
+	}
+
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	// the renders in flight when the duration elapses are given the parent context and let finish,
+	// so that they are not reported as errors
+	var stopped int32
+	if options.Duration > 0 {
+		timer := time.AfterFunc(options.Duration, func() { atomic.StoreInt32(&stopped, 1) })
+		defer timer.Stop()
+	}
+
+	metered := &meteredService{service: service}
+	report := SoakReport{}
+	var started int64
+	var firstErrorOnce sync.Once
+
+	start := time.Now()
+	var workers sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for ctx.Err() == nil && atomic.LoadInt32(&stopped) == 0 {
+				render := atomic.AddInt64(&started, 1)
+				if options.MaxRenders > 0 && render > options.MaxRenders {
+					return
+				}
+
+				_, err := ResolveParametersInTextWithContext(ctx, metered, corpus[(render-1)%int64(len(corpus))], options.ResolveOptions)
+				atomic.AddInt64(&report.Renders, 1)
+				if err != nil {
+					atomic.AddInt64(&report.Errors, 1)
+					firstErrorOnce.Do(func() { report.FirstError = err })
+				}
+			}
+		}()
+	}
+	workers.Wait()
+
+	report.Elapsed = time.Since(start)
+	report.GetParametersCalls = atomic.LoadInt64(&metered.getParametersCalls)
+	report.ParametersFetched = atomic.LoadInt64(&metered.parametersFetched)
+
+	return report, nil
+}
+
+// counts the Parameter Store requests of the wrapped service
+type meteredService struct {
+	service            ISsmParameterService
+	getParametersCalls int64
+	parametersFetched  int64
+}
+
+func (m *meteredService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	atomic.AddInt64(&m.getParametersCalls, 1)
+	atomic.AddInt64(&m.parametersFetched, int64(len(parameterReferences)))
+	return m.service.callGetParameters(ctx, parameterReferences)
+}
+
+func (m *meteredService) callGetParametersByPath(ctx context.Context, path string, recursive bool) ([]SsmParameterInfo, error) {
+	return m.service.callGetParametersByPath(ctx, path, recursive)
+}
+
+func (m *meteredService) callGetSecretValue(ctx context.Context, secretId string) (SsmParameterInfo, error) {
+	return getSecretValue(ctx, m.service, secretId)
+}
+
+func perSecond(count int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(count) / elapsed.Seconds()
+}
@@ -0,0 +1,82 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoakTest(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+		"ssm:/app/port": {Name: "/app/port", Type: stringType, Value: "443"},
+	})
+
+	corpus := []string{"{{ssm:/app/host}}:{{ssm:/app/port}}", "{{ssm:/app/missing}}"}
+	report, err := SoakTest(context.Background(), &serviceObject, corpus, SoakOptions{
+		Concurrency: 4,
+		MaxRenders:  100,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), report.Renders)
+	assert.Equal(t, int64(50), report.Errors)
+	assert.Equal(t, 0.5, report.ErrorRate())
+	assert.Equal(t, int64(100), report.GetParametersCalls)
+	assert.Equal(t, int64(150), report.ParametersFetched)
+	assert.NotNil(t, report.FirstError)
+}
+
+func TestSoakTestDuration(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+	})
+
+	report, err := SoakTest(context.Background(), &serviceObject, []string{"{{ssm:/app/host}}"}, SoakOptions{
+		Duration: 50 * time.Millisecond,
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, report.Renders > 0)
+	assert.True(t, report.RendersPerSecond() > 0)
+
+	_, err = SoakTest(context.Background(), &serviceObject, []string{"{{ssm:/app/host}}"}, SoakOptions{})
+	assert.NotNil(t, err)
+}
+
+//
+// Mocked service answering after a delay
+type slowService struct {
+	ServiceMockedObjectWithRecords
+	delay time.Duration
+}
+
+func (m *slowService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	select {
+	case <-time.After(m.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return m.ServiceMockedObjectWithRecords.callGetParameters(ctx, parameterReferences)
+}
+
+func TestSoakTestLetsRendersInFlightFinish(t *testing.T) {
+	serviceObject := &slowService{
+		ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+			"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+		}),
+		delay: 20 * time.Millisecond,
+	}
+
+	report, err := SoakTest(context.Background(), serviceObject, []string{"{{ssm:/app/host}}"}, SoakOptions{
+		Concurrency: 4,
+		Duration:    30 * time.Millisecond,
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, report.Renders > 0)
+	assert.Equal(t, int64(0), report.Errors)
+	assert.Nil(t, report.FirstError)
+}
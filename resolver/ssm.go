@@ -1,16 +1,26 @@
 package resolver
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"time"
 
 	"errors"
 	"strings"
+	"sync"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/aws/aws-sdk-go/service/ssm"
 )
 
@@ -18,50 +28,180 @@ import (
 // Maximum number of parameters that can be requested from SSM Parameter store in one GetParameters request
 const maxParametersRetrievedFromSsm = 10
 
+//
+// Timeout of ec2metadata requests made to discover the region
+const ec2MetadataTimeout = 2 * time.Second
+
+//
+// ISsmParameterService fetches parameters. Implementations stop and return an error when ctx is done,
+// the AWS calls of Service are canceled with it.
 type ISsmParameterService interface {
-	callGetParameters(parameterReferences []string) (map[string]SsmParameterInfo, error)
+	callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error)
+	callGetParametersByPath(ctx context.Context, path string, recursive bool) ([]SsmParameterInfo, error)
 }
 
+//
+// Service resolves parameters with the SSMClient. The client can be created by NewService/NewServiceWithOptions
+// or injected directly, e.g. Service{SSMClient: ssm.New(mySession)}. An injected client uses whatever HTTP client
+// its session was configured with: set aws.Config.HTTPClient to an http.Client whose transport has a Proxy, and
+// session.Options.CustomCABundle to the CA bundle, to reach SSM through an egress proxy.
 type Service struct {
 	SSMClient *ssm.SSM
+
+	// Used to encrypt and decrypt parameter snapshots only, may be nil otherwise
+	KMSClient *kms.KMS
+
+	// Used to describe the images of aws:ec2:image parameters only, may be nil otherwise
+	EC2Client *ec2.EC2
+
+	// Used to retrieve the secrets of secretsmanager: references only, may be nil otherwise
+	SecretsManagerClient *secretsmanager.SecretsManager
+}
+
+//
+// Options of the SSM client created by NewServiceWithOptions
+type ServiceOptions struct {
+	// URL of the HTTP(S) proxy for SSM calls, e.g. http://proxy.internal:3128.
+	// When empty the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables are honored.
+	ProxyURL string
+
+	// PEM file with additional CA certificates trusted when connecting to SSM or the proxy
+	CABundleFileName string
+
+	// AWS region of the clients, e.g. eu-west-1. When empty the region of the environment or the shared config
+	// is used, then the region of the EC2 instance.
+	Region string
+
+	// Profile of the shared AWS config and credentials files, AWS_PROFILE or the default profile when empty
+	Profile string
 }
 
 func NewService() (service *Service, err error) {
-	currentSession, err := session.NewSessionWithOptions(session.Options{
+	return NewServiceWithOptions(ServiceOptions{})
+}
+
+//
+// Creates a Service with a client configured from the shared AWS config, the environment and ServiceOptions.
+func NewServiceWithOptions(options ServiceOptions) (service *Service, err error) {
+	sessionOptions := session.Options{
 		SharedConfigState: session.SharedConfigEnable,
-	})
+		Profile:           options.Profile,
+	}
+
+	if len(options.Region) > 0 {
+		sessionOptions.Config.Region = aws.String(options.Region)
+	}
+
+	if len(options.ProxyURL) > 0 {
+		proxyURL, err := url.Parse(options.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %s: %w", options.ProxyURL, err)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(proxyURL)
+		sessionOptions.Config.HTTPClient = &http.Client{Transport: transport}
+	}
+
+	if len(options.CABundleFileName) > 0 {
+		caBundle, err := os.Open(options.CABundleFileName)
+		if err != nil {
+			return nil, err
+		}
+		defer caBundle.Close()
+		sessionOptions.CustomCABundle = caBundle
+	}
+
+	currentSession, err := session.NewSessionWithOptions(sessionOptions)
 	if err != nil {
 		return
 	}
 
-	if *currentSession.Config.Region == "" {
+	if aws.StringValue(currentSession.Config.Region) == "" {
 		log.Println("There is no explict region configuration, retriving ec2metadata...")
-		region, err := ec2metadata.New(currentSession).Region()
+		// IMDSv2 with hop limit 1 is unreachable from containers, fail fast instead of retrying for a long time
+		metadataClient := ec2metadata.New(currentSession, &aws.Config{
+			HTTPClient: &http.Client{Timeout: ec2MetadataTimeout},
+			MaxRetries: aws.Int(1),
+		})
+		region, err := metadataClient.Region()
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("cannot retrieve region from ec2metadata, set AWS_REGION when running in a container: %w", err)
 		}
 		currentSession.Config.Region = aws.String(region)
 	}
 
-	var client *ssm.SSM
+	clientConfig := &aws.Config{}
 	if arn := os.Getenv("SSM2ENV_ASSUME_ROLE_ARN"); arn != "" {
-		credentials := stscreds.NewCredentials(currentSession, arn)
-		client = ssm.New(currentSession, &aws.Config{Credentials: credentials})
-	} else {
-		client = ssm.New(currentSession)
+		clientConfig.Credentials = stscreds.NewCredentials(currentSession, arn)
 	}
 
 	service = &Service{
-		SSMClient: client,
+		SSMClient:            ssm.New(currentSession, clientConfig),
+		KMSClient:            kms.New(currentSession, clientConfig),
+		EC2Client:            ec2.New(currentSession, clientConfig),
+		SecretsManagerClient: secretsmanager.New(currentSession, clientConfig),
 	}
 
 	return
 }
 
+//
+// Result of Service.CheckHealth
+type HealthStatus struct {
+	Region string
+
+	// Name of the credentials provider, e.g. EnvConfigCredentials, SharedConfigCredentials,
+	// WebIdentityCredentials (EKS), or the remote provider used for ECS task roles and EC2 instance profiles
+	CredentialSource string
+}
+
+//
+// Verifies that the client has a region and can retrieve credentials and reports where they come from.
+func (s *Service) CheckHealth() (HealthStatus, error) {
+	status := HealthStatus{
+		Region: aws.StringValue(s.SSMClient.Config.Region),
+	}
+
+	if len(status.Region) == 0 {
+		return status, errors.New("region is not configured")
+	}
+
+	if s.SSMClient.Config.Credentials == nil {
+		return status, errors.New("credentials are not configured")
+	}
+
+	credentialsValue, err := s.SSMClient.Config.Credentials.Get()
+	if err != nil {
+		return status, fmt.Errorf("cannot retrieve credentials: %w", err)
+	}
+	status.CredentialSource = credentialsValue.ProviderName
+
+	return status, nil
+}
+
 //
 // This function takes a list of at most maxParametersRetrievedFromSsm(=10) ssm parameter name references like (ssm:name).
 // It returns a map<param-ref, SsmParameterInfo>.
-func (s *Service) callGetParameters(parameterReferences []string) (map[string]SsmParameterInfo, error) {
+func (s *Service) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	return s.getParameters(ctx, parameterReferences, true)
+}
+
+// returns the parameters of parameterReferences without their values, SecureString parameters are not decrypted
+func (s *Service) callGetParameterMetadata(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	parameters, err := s.getParameters(ctx, parameterReferences, false)
+	for ref, param := range parameters {
+		param.Value = ""
+		parameters[ref] = param
+	}
+
+	return parameters, err
+}
+
+// requests parameterReferences with one GetParameters request, decrypting SecureString values when withDecryption
+func (s *Service) getParameters(
+	ctx context.Context,
+	parameterReferences []string,
+	withDecryption bool) (map[string]SsmParameterInfo, error) {
 
 	name2RefMap := make(map[string]string)
 
@@ -71,12 +211,12 @@ func (s *Service) callGetParameters(parameterReferences []string) (map[string]Ss
 		parameterReferences[i] = nameWithoutPrefix
 	}
 
-	parametersOutput, err := s.SSMClient.GetParameters(&ssm.GetParametersInput{
+	parametersOutput, err := s.SSMClient.GetParametersWithContext(ctx, &ssm.GetParametersInput{
 		Names:          aws.StringSlice(parameterReferences),
-		WithDecryption: aws.Bool(true),
+		WithDecryption: aws.Bool(withDecryption),
 	})
 	if err != nil {
-		return nil, err
+		return nil, withStatus(StatusAwsError, err)
 	}
 
 	if len(parametersOutput.InvalidParameters) > 0 {
@@ -84,56 +224,163 @@ func (s *Service) callGetParameters(parameterReferences []string) (map[string]Ss
 		for _, p := range parametersOutput.InvalidParameters {
 			invalidParameters = append(invalidParameters, *p)
 		}
-		return nil, errors.New("The following parameter(s) cannot be resolved: " + strings.Join(invalidParameters, ","))
+		return nil, newMissingParametersError(invalidParameters)
 	}
 
+	// parameters requested with a selector are returned with their name and the selector, e.g. :3 or :prod
 	resolvedParametersMap := map[string]SsmParameterInfo{}
 	for i := 0; i < len(parametersOutput.Parameters); i++ {
 		param := parametersOutput.Parameters[i]
-		resolvedParametersMap[name2RefMap[*param.Name]] = SsmParameterInfo{
-			Name:  *param.Name,
-			Type:  *param.Type,
-			Value: *param.Value,
-		}
+		resolvedParametersMap[name2RefMap[*param.Name+aws.StringValue(param.Selector)]] = newSsmParameterInfo(param)
 	}
 
 	return resolvedParametersMap, nil
 }
 
 //
-// This function takes as an input a list of references to the SSMParameterService and return a map <reference, SSMParameterInfo>
-func getParametersFromSsmParameterStore(s ISsmParameterService, parametersToFetch []string) (map[string]SsmParameterInfo, error) {
+// This function returns all parameters under path, following NextToken until the last page.
+func (s *Service) callGetParametersByPath(ctx context.Context, path string, recursive bool) ([]SsmParameterInfo, error) {
+	parameters := []SsmParameterInfo{}
 
-	outputMap := make(map[string]SsmParameterInfo)
+	err := s.SSMClient.GetParametersByPathPagesWithContext(ctx, &ssm.GetParametersByPathInput{
+		Path:           aws.String(path),
+		Recursive:      aws.Bool(recursive),
+		WithDecryption: aws.Bool(true),
+	}, func(page *ssm.GetParametersByPathOutput, lastPage bool) bool {
+		for _, param := range page.Parameters {
+			parameters = append(parameters, newSsmParameterInfo(param))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, withStatus(StatusAwsError, err)
+	}
 
-	var totalParams = len(parametersToFetch)
-	var startPos = 0
-	for totalParams > 0 {
+	return parameters, nil
+}
 
-		var paramsBatch []string
+// converts a parameter returned by SSM into SsmParameterInfo
+func newSsmParameterInfo(param *ssm.Parameter) SsmParameterInfo {
+	// the selector of a parameter requested by label, e.g. :prod, is the only label GetParameters returns
+	_, _, label := splitParameterSelector(aws.StringValue(param.Name) + aws.StringValue(param.Selector))
 
-		var count = 0
-		for i := startPos; i < len(parametersToFetch) && count < maxParametersRetrievedFromSsm; i++ {
-			paramsBatch = append(paramsBatch, parametersToFetch[i])
+	return SsmParameterInfo{
+		Name:     aws.StringValue(param.Name),
+		Type:     aws.StringValue(param.Type),
+		Value:    aws.StringValue(param.Value),
+		DataType: aws.StringValue(param.DataType),
+		Version:  aws.Int64Value(param.Version),
+		Label:    label,
+	}
+}
 
-			totalParams--
-			count++
-			startPos++
-		}
+//
+// This function takes as an input a list of references to the SSMParameterService and return a map <reference, SSMParameterInfo>.
+// Batches of maxParametersRetrievedFromSsm references are requested by up to maxConcurrency goroutines (one when not
+// positive). No further batch is requested once ctx is done or a batch failed, and the first error is returned;
+// the parameters missing from all the batches are reported together.
+func getParametersFromSsmParameterStore(
+	ctx context.Context,
+	s ISsmParameterService,
+	parametersToFetch []string,
+	maxConcurrency int) (map[string]SsmParameterInfo, error) {
 
-		results, err := s.callGetParameters(paramsBatch)
-		if err != nil {
-			return nil, err
+	batches := [][]string{}
+	for start := 0; start < len(parametersToFetch); start += maxParametersRetrievedFromSsm {
+		end := start + maxParametersRetrievedFromSsm
+		if end > len(parametersToFetch) {
+			end = len(parametersToFetch)
 		}
+		batches = append(batches, append([]string{}, parametersToFetch[start:end]...))
+	}
 
-		for name, value := range results {
-			outputMap[name] = value
-		}
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	if maxConcurrency > len(batches) {
+		maxConcurrency = len(batches)
+	}
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mutex sync.Mutex
+	outputMap := make(map[string]SsmParameterInfo)
+	missingNames := []string{}
+	var firstErr error
+
+	pending := make(chan int, len(batches))
+	for i := range batches {
+		pending <- i
+	}
+	close(pending)
+
+	var workers sync.WaitGroup
+	for worker := 0; worker < maxConcurrency; worker++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := range pending {
+				if fetchCtx.Err() != nil {
+					return
+				}
+
+				results, err := s.callGetParameters(fetchCtx, batches[i])
+
+				mutex.Lock()
+				var missingParametersError *MissingParametersError
+				if errors.As(err, &missingParametersError) {
+					missingNames = append(missingNames, missingParametersError.Names...)
+				} else if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				for name, value := range results {
+					outputMap[name] = value
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	workers.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(missingNames) > 0 {
+		return nil, newMissingParametersError(missingNames)
 	}
 
 	return outputMap, nil
 }
 
+// tells whether parameterReference refers to an SSM parameter, with the secure prefix or not
+func isSsmReference(parameterReference string) bool {
+	return strings.HasPrefix(parameterReference, ssmNonSecurePrefix) || strings.HasPrefix(parameterReference, ssmSecurePrefix)
+}
+
+// returns the name of the parameter reference without its prefix, with the selector if any, e.g. /app/key:3
 func extractParameterNameFromReference(parameterReference string) string {
 	return parameterReference[strings.Index(parameterReference, ":")+1:]
 }
+
+// splits a parameter name with an optional selector, e.g. /app/key:3 or /app/key:prod, into the name and
+// the selected version (0 when none) or label (empty when none)
+func splitParameterSelector(name string) (string, int64, string) {
+	separator := strings.LastIndex(name, ":")
+	if separator < 0 {
+		return name, 0, ""
+	}
+
+	selector := name[separator+1:]
+	if version, err := strconv.ParseInt(selector, 10, 64); err == nil {
+		return name[:separator], version, ""
+	}
+
+	return name[:separator], 0, selector
+}
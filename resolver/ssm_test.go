@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"strconv"
@@ -12,6 +13,11 @@ import (
 type ServiceMockedObjectWithRecords struct {
 	ISsmParameterService
 	records map[string]SsmParameterInfo
+
+	// parameters stored with callPutParameter, whether they overwrote existing ones and the KMS key ids they were stored with
+	putParameters []SsmParameterInfo
+	putOverwrites []bool
+	putKmsKeyIds  []string
 }
 
 func NewServiceMockedObjectWithExtraRecords(
@@ -21,7 +27,7 @@ func NewServiceMockedObjectWithExtraRecords(
 	}
 }
 
-func (m *ServiceMockedObjectWithRecords) callGetParameters(parameterReferences []string) (map[string]SsmParameterInfo, error) {
+func (m *ServiceMockedObjectWithRecords) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
 	parameters := make(map[string]SsmParameterInfo)
 
 	for i := 0; i < len(parameterReferences); i++ {
@@ -37,6 +43,84 @@ func (m *ServiceMockedObjectWithRecords) callGetParameters(parameterReferences [
 	return parameters, nil
 }
 
+func (m *ServiceMockedObjectWithRecords) callGetParametersByPath(ctx context.Context, path string, recursive bool) ([]SsmParameterInfo, error) {
+	parameters := []SsmParameterInfo{}
+	seen := map[string]bool{}
+
+	for _, value := range m.records {
+		if isParameterUnderPath(value.Name, path, recursive) && !seen[value.Name] {
+			seen[value.Name] = true
+			parameters = append(parameters, value)
+		}
+	}
+
+	return parameters, nil
+}
+
+func (m *ServiceMockedObjectWithRecords) callGetExistingParameters(ctx context.Context, names []string) (map[string]SsmParameterInfo, error) {
+	existingParameters := map[string]SsmParameterInfo{}
+
+	for _, name := range names {
+		for _, value := range m.records {
+			if value.Name == name {
+				existingParameters[name] = value
+			}
+		}
+	}
+
+	return existingParameters, nil
+}
+
+func (m *ServiceMockedObjectWithRecords) callPutParameter(ctx context.Context, param SsmParameterInfo, overwrite bool, kmsKeyId string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	prefix := ssmNonSecurePrefix
+	if param.Type == secureStringType {
+		prefix = ssmSecurePrefix
+	}
+	if _, exists := m.records[prefix+param.Name]; exists && !overwrite {
+		return errors.New("parameter " + param.Name + " already exists")
+	}
+
+	m.records[prefix+param.Name] = param
+	m.putParameters = append(m.putParameters, param)
+	m.putOverwrites = append(m.putOverwrites, overwrite)
+	m.putKmsKeyIds = append(m.putKmsKeyIds, kmsKeyId)
+	return nil
+}
+
+// fake envelope encryption: the "encrypted" data key is the plaintext data key with a marker prefix
+var mockedDataKey = []byte("0123456789abcdef0123456789abcdef")
+
+func (m *ServiceMockedObjectWithRecords) generateDataKey(ctx context.Context, keyId string) ([]byte, []byte, error) {
+	return mockedDataKey, append([]byte(keyId+":"), mockedDataKey...), nil
+}
+
+func (m *ServiceMockedObjectWithRecords) decryptDataKey(ctx context.Context, encryptedKey []byte) ([]byte, error) {
+	return encryptedKey[len(encryptedKey)-len(mockedDataKey):], nil
+}
+
+func TestSplitParameterSelector(t *testing.T) {
+	for name, expected := range map[string]struct {
+		name    string
+		version int64
+		label   string
+	}{
+		"/app/key":        {"/app/key", 0, ""},
+		"/app/key:3":      {"/app/key", 3, ""},
+		"key:12":          {"key", 12, ""},
+		"/app/key:prod":   {"/app/key", 0, "prod"},
+		"/app/key:v2.1-b": {"/app/key", 0, "v2.1-b"},
+	} {
+		parameterName, version, label := splitParameterSelector(name)
+		assert.Equal(t, expected.name, parameterName)
+		assert.Equal(t, expected.version, version)
+		assert.Equal(t, expected.label, label)
+	}
+}
+
 func TestGetParametersFromSsmParameterStoreWithAllResolvedNoPaging(t *testing.T) {
 	parametersList := []string{}
 	expectedValues := map[string]SsmParameterInfo{}
@@ -56,7 +140,7 @@ func TestGetParametersFromSsmParameterStoreWithAllResolvedNoPaging(t *testing.T)
 	serviceObject := NewServiceMockedObjectWithExtraRecords(expectedValues)
 
 	t.Log("Testing getParametersFromSsmParameterStore API for all parameters present without paging...")
-	retrievedValues, err := getParametersFromSsmParameterStore(&serviceObject, parametersList)
+	retrievedValues, err := getParametersFromSsmParameterStore(context.Background(), &serviceObject, parametersList, 1)
 	assert.Nil(t, err)
 	assert.True(t, reflect.DeepEqual(expectedValues, retrievedValues))
 }
@@ -65,7 +149,7 @@ func TestGetParametersFromSsmParameterStoreWithAllResolvedWithPaging(t *testing.
 	parametersList := []string{}
 	expectedValues := map[string]SsmParameterInfo{}
 
-	for i := 0; i < maxParametersRetrievedFromSsm/5; i++ {
+	for i := 0; i < maxParametersRetrievedFromSsm*5/2; i++ {
 		name := "name_" + strconv.Itoa(i)
 		key := ssmSecurePrefix + name
 		parametersList = append(parametersList, key)
@@ -77,12 +161,25 @@ func TestGetParametersFromSsmParameterStoreWithAllResolvedWithPaging(t *testing.
 		}
 	}
 
-	serviceObject := NewServiceMockedObjectWithExtraRecords(expectedValues)
+	serviceObject := &batchRecordingService{ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(expectedValues)}
 
 	t.Log("Testing getParametersFromSsmParameterStore API for all parameters present with paging...")
-	retrievedValues, err := getParametersFromSsmParameterStore(&serviceObject, parametersList)
+	retrievedValues, err := getParametersFromSsmParameterStore(context.Background(), serviceObject, parametersList, 1)
 	assert.Nil(t, err)
 	assert.True(t, reflect.DeepEqual(expectedValues, retrievedValues))
+	assert.Equal(t, []int{maxParametersRetrievedFromSsm, maxParametersRetrievedFromSsm, maxParametersRetrievedFromSsm / 2}, serviceObject.batchSizes)
+}
+
+//
+// Mocked service recording the number of references of every GetParameters request
+type batchRecordingService struct {
+	ServiceMockedObjectWithRecords
+	batchSizes []int
+}
+
+func (m *batchRecordingService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	m.batchSizes = append(m.batchSizes, len(parameterReferences))
+	return m.ServiceMockedObjectWithRecords.callGetParameters(ctx, parameterReferences)
 }
 
 func TestGetParametersFromSsmParameterStoreWithUnresolvedIgnoreNoPaging(t *testing.T) {
@@ -95,6 +192,62 @@ func TestGetParametersFromSsmParameterStoreWithUnresolvedIgnoreNoPaging(t *testi
 	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
 
 	t.Log("Testing getParametersFromSsmParameterStore API for all unresolved parameters...")
-	_, err := getParametersFromSsmParameterStore(&serviceObject, parametersList)
+	_, err := getParametersFromSsmParameterStore(context.Background(), &serviceObject, parametersList, 1)
 	assert.NotNil(t, err)
 }
+
+func TestGetParametersFromSsmParameterStoreCanceled(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:name": {Name: "name", Type: stringType, Value: "value"},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := getParametersFromSsmParameterStore(ctx, &serviceObject, []string{"ssm:name"}, 1)
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = ResolveParametersInTextWithContext(ctx, &serviceObject, "{{ssm:name}}", ResolveOptions{})
+	assert.Equal(t, context.Canceled, err)
+
+	output, err := ResolveParametersInTextWithContext(context.Background(), &serviceObject, "{{ssm:name}}", ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "value", output)
+}
+
+func TestGetParametersFromSsmParameterStoreConcurrently(t *testing.T) {
+	records := map[string]SsmParameterInfo{}
+	parametersList := []string{}
+	for i := 0; i < maxParametersRetrievedFromSsm*7/2; i++ {
+		name := "/app/name_" + strconv.Itoa(i)
+		records[ssmNonSecurePrefix+name] = SsmParameterInfo{Name: name, Type: stringType, Value: "value_" + name}
+		parametersList = append(parametersList, ssmNonSecurePrefix+name)
+	}
+	serviceObject := &throttlingService{ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(records)}
+
+	retrievedValues, err := getParametersFromSsmParameterStore(context.Background(), serviceObject, parametersList, 3)
+
+	assert.Nil(t, err)
+	assert.Equal(t, records, retrievedValues)
+	assert.True(t, serviceObject.maxInFlight > 1 && serviceObject.maxInFlight <= 3)
+}
+
+func TestGetParametersFromSsmParameterStoreConcurrentErrors(t *testing.T) {
+	records := map[string]SsmParameterInfo{}
+	parametersList := []string{}
+	for i := 0; i < maxParametersRetrievedFromSsm*3; i++ {
+		name := "/app/name_" + strconv.Itoa(i)
+		records[ssmNonSecurePrefix+name] = SsmParameterInfo{Name: name, Type: stringType, Value: "value_" + name}
+		parametersList = append(parametersList, ssmNonSecurePrefix+name)
+	}
+	snapshotService := newSnapshotService(&Snapshot{Parameters: []SsmParameterInfo{records[parametersList[0]]}})
+
+	_, err := getParametersFromSsmParameterStore(context.Background(), snapshotService, parametersList, 2)
+	var missingParametersError *MissingParametersError
+	assert.True(t, errors.As(err, &missingParametersError))
+	assert.Equal(t, len(parametersList)-1, len(missingParametersError.Names))
+
+	throttled := &throttlingService{ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(records), throttle: 1}
+	_, err = getParametersFromSsmParameterStore(context.Background(), throttled, parametersList, 2)
+	assert.Equal(t, throttlingError{}, err)
+}
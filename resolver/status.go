@@ -0,0 +1,101 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+//
+// Kind of failure of a resolve call, usable as the exit code of a command
+type Status int
+
+const (
+	StatusOK Status = iota
+	StatusGenericError
+	StatusParseError
+	StatusPolicyViolation
+	StatusNotFound
+	StatusAwsError
+	StatusPartial
+)
+
+var statusNames = map[Status]string{
+	StatusOK:              "ok",
+	StatusGenericError:    "error",
+	StatusParseError:      "parse_error",
+	StatusPolicyViolation: "policy_violation",
+	StatusNotFound:        "not_found",
+	StatusAwsError:        "aws_error",
+	StatusPartial:         "partial",
+}
+
+func (status Status) String() string {
+	if name, known := statusNames[status]; known {
+		return name
+	}
+	return statusNames[StatusGenericError]
+}
+
+//
+// Error carrying the Status of the failure. Error() is the message of the wrapped error.
+type StatusError struct {
+	Status Status
+	Err    error
+}
+
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// wraps err into a StatusError, nil stays nil
+func withStatus(status Status, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &StatusError{Status: status, Err: err}
+}
+
+//
+// Returns the Status of an error returned by the resolver: StatusOK for nil and StatusGenericError
+// for errors of no specific kind.
+func StatusOf(err error) Status {
+	if err == nil {
+		return StatusOK
+	}
+
+	var statusError *StatusError
+	if errors.As(err, &statusError) {
+		return statusError.Status
+	}
+
+	return StatusGenericError
+}
+
+//
+// JSON status of a resolve call for orchestration systems, e.g. {"status": "not_found", "code": 4, "error": "..."}
+type StatusReport struct {
+	Status string `json:"status"`
+	Code   int    `json:"code"`
+	Error  string `json:"error,omitempty"`
+}
+
+func NewStatusReport(err error) StatusReport {
+	status := StatusOf(err)
+	report := StatusReport{Status: status.String(), Code: int(status)}
+	if err != nil {
+		report.Error = err.Error()
+	}
+
+	return report
+}
+
+//
+// Writes the StatusReport of err to writer as a line of JSON.
+func WriteStatusJSON(writer io.Writer, err error) error {
+	return json.NewEncoder(writer).Encode(NewStatusReport(err))
+}
@@ -0,0 +1,51 @@
+package resolver
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusOf(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host":     {Name: "/app/host", Type: stringType, Value: "example.com"},
+		"ssm:/app/password": {Name: "/app/password", Type: secureStringType, Value: "s3cr3t"},
+	})
+	snapshotService, err := NewSnapshotService(strings.NewReader(`{"Parameters": []}`))
+	assert.Nil(t, err)
+
+	testCases := []struct {
+		service ISsmParameterService
+		text    string
+		options ResolveOptions
+		status  Status
+	}{
+		{&serviceObject, "{{ssm:/app/host}}", ResolveOptions{}, StatusOK},
+		{&serviceObject, "{{ssm:/app/host | nosuchtransformer}}", ResolveOptions{}, StatusParseError},
+		{&serviceObject, "eval {{ssm:/app/host}}", ResolveOptions{StrictShellContexts: true}, StatusPolicyViolation},
+		{&serviceObject, "{{ssm:/app/password}}", ResolveOptions{}, StatusPolicyViolation},
+		{&serviceObject, "{{ssm:/app/host | type=int}}", ResolveOptions{}, StatusPolicyViolation},
+		{snapshotService, "{{ssm:/app/missing}}", ResolveOptions{}, StatusNotFound},
+	}
+
+	for _, testCase := range testCases {
+		_, err := ResolveParametersInText(testCase.service, testCase.text, testCase.options)
+		assert.Equal(t, testCase.status, StatusOf(err), testCase.text)
+	}
+
+	assert.Equal(t, StatusGenericError, StatusOf(errors.New("other")))
+}
+
+func TestWriteStatusJSON(t *testing.T) {
+	var buffer bytes.Buffer
+
+	assert.Nil(t, WriteStatusJSON(&buffer, withStatus(StatusNotFound, errors.New("missing"))))
+	assert.JSONEq(t, `{"status": "not_found", "code": 4, "error": "missing"}`, buffer.String())
+
+	buffer.Reset()
+	assert.Nil(t, WriteStatusJSON(&buffer, nil))
+	assert.JSONEq(t, `{"status": "ok", "code": 0}`, buffer.String())
+}
@@ -0,0 +1,218 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+//
+// Size of the chunks ResolveParametersInStream reads its input in
+const streamChunkSize = 64 * 1024
+
+//
+// Longest unterminated placeholder carried over to the next chunk, longer ones are not taken for placeholders
+const maxStreamedPlaceholderLength = 64 * 1024
+
+//
+// Resolves SSM parameters in a document too large to be held in memory, reading it from input in chunks
+// and writing the resolved document to output as it goes. Input is read twice: once to find the parameter
+// references, then again from the start to substitute them, so it must not change in between.
+// Formats, StrictShellContexts and placeholders nested in parameter names need the whole document and are not
+// supported. Limits apply to the whole document.
+//
+// Deprecated: use Resolver.ResolveStream of github.com/parameterResolver/resolver/v2.
+func ResolveParametersInStream(
+	service ISsmParameterService,
+	input io.ReadSeeker,
+	output io.Writer,
+	options ResolveOptions) error {
+
+	return ResolveParametersInStreamWithContext(context.Background(), service, input, output, options)
+}
+
+//
+// Same as ResolveParametersInStream, but the SSM requests are canceled when ctx is done.
+// Nothing is written to output once ctx is done.
+//
+// Deprecated: use Resolver.ResolveStream of github.com/parameterResolver/resolver/v2.
+func ResolveParametersInStreamWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	input io.ReadSeeker,
+	output io.Writer,
+	options ResolveOptions) error {
+
+	return resolveParametersInStream(ctx, service, input, output, options, streamChunkSize)
+}
+
+func resolveParametersInStream(
+	ctx context.Context,
+	service ISsmParameterService,
+	input io.ReadSeeker,
+	output io.Writer,
+	options ResolveOptions,
+	chunkSize int) error {
+
+	if len(options.Format) > 0 {
+		return errors.New("format " + options.Format + " cannot be applied to a streamed document")
+	}
+
+	if options.PlaceholderSyntax.isCustom() {
+		return errors.New("custom placeholder delimiters cannot be used in a streamed document")
+	}
+
+	if checksShellContexts(options) {
+		return errors.New("shell contexts cannot be checked in a streamed document")
+	}
+
+	start, err := input.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	allReferences := []string{}
+	seenReferences := map[string]bool{}
+	maxAges := map[string]time.Duration{}
+	defaults := map[string]string{}
+	withoutDefault := map[string]bool{}
+	sinks := map[string]string{}
+	limits := effectiveLimits(options)
+	placeholderCount := 0
+	var parseTime time.Duration
+	err = forEachStreamSegment(input, chunkSize, func(segment string) error {
+		parseStart := time.Now()
+		if len(findNestedPlaceholders(segment)) > 0 {
+			return withStatus(StatusParseError, errors.New("placeholders nested in parameter names cannot be used in a streamed document"))
+		}
+
+		references, err := parseAndValidatePlaceholders(segment, options)
+		if err != nil {
+			return err
+		}
+
+		// the limits of the segments add up to the limits of the document
+		placeholderCount += countPlaceholders(limits, segment)
+		if err := checkPlaceholderTotal(limits, placeholderCount); err != nil {
+			return err
+		}
+		parseTime += time.Since(parseStart)
+		if err := checkParseDuration(limits, parseTime); err != nil {
+			return err
+		}
+		for _, ref := range references {
+			if !seenReferences[ref] {
+				seenReferences[ref] = true
+				allReferences = append(allReferences, ref)
+			}
+		}
+
+		for ref, maxAge := range placeholderMaxAges(segment) {
+			if current, contains := maxAges[ref]; !contains || maxAge < current {
+				maxAges[ref] = maxAge
+			}
+		}
+		addPlaceholderDefaults(defaults, withoutDefault, segment)
+		return addPlaceholderSinks(sinks, segment)
+	})
+	if err != nil {
+		return err
+	}
+
+	resolvedParametersMap, err := resolveParsedReferences(ctx, service, allReferences, options, maxAges, defaults)
+	if err != nil {
+		return err
+	}
+
+	resolvedParametersMap, err = deliverSecrets(ctx, resolvedParametersMap, sinks, options)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err = input.Seek(start, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	// warnings report the lines of the whole input, not of the segment
+	linesBefore := 0
+	segmentOptions := options
+	if options.Warn != nil {
+		segmentOptions.Warn = func(warning ValueWarning) {
+			warning.Line += linesBefore
+			options.Warn(warning)
+		}
+	}
+
+	return forEachStreamSegment(input, chunkSize, func(segment string) error {
+		sanitizedParametersMap, err := sanitizeResolvedValues(segment, resolvedParametersMap, options.SanitizeValues)
+		if err != nil {
+			return err
+		}
+		warnAboutResolvedValues(segment, "", sanitizedParametersMap, segmentOptions)
+		linesBefore += strings.Count(segment, "\n")
+
+		resolvedSegment, err := replaceParameterPlaceholders(segment, sanitizedParametersMap)
+		if err != nil {
+			return err
+		}
+
+		if err := checkUnresolvedPlaceholders(resolvedSegment, options); err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(output, resolvedSegment)
+		return err
+	})
+}
+
+// reads input in chunks of chunkSize and calls fn with consecutive segments covering all of it. A segment never ends
+// inside a placeholder: an unterminated {{ at the end of a chunk is carried over to the next one.
+func forEachStreamSegment(input io.Reader, chunkSize int, fn func(segment string) error) error {
+	chunk := make([]byte, chunkSize)
+	carry := ""
+
+	for {
+		n, err := io.ReadFull(input, chunk)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		text := carry + string(chunk[:n])
+
+		if err != nil {
+			if len(text) == 0 {
+				return nil
+			}
+			return fn(text)
+		}
+
+		cut := streamSegmentEnd(text)
+		if cut > 0 {
+			if err := fn(text[:cut]); err != nil {
+				return err
+			}
+		}
+		carry = text[cut:]
+	}
+}
+
+// returns where the segment of text that cannot continue in the next chunk ends
+func streamSegmentEnd(text string) int {
+	if open := strings.LastIndex(text, "{{"); open >= 0 && !strings.Contains(text[open:], "}}") &&
+		len(text)-open <= maxStreamedPlaceholderLength {
+		return open
+	}
+
+	// the opening brace of a placeholder whose second one is in the next chunk
+	if strings.HasSuffix(text, "{") {
+		return len(text) - 1
+	}
+
+	return len(text)
+}
@@ -0,0 +1,70 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersInStream(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host":     {Name: "/app/host", Type: stringType, Value: "db.internal"},
+		"ssm-secure:/app/p": {Name: "/app/p", Type: secureStringType, Value: "s3cr3t"},
+	})
+
+	text := strings.Repeat("host={{ssm:/app/host}} password={{ ssm-secure:/app/p | urlencode }} {not a placeholder} {{\n", 20)
+	expected, err := ResolveParametersInText(&serviceObject, text, ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Contains(t, expected, "host=db.internal password=s3cr3t")
+
+	// every chunk boundary falls somewhere else in the placeholders
+	for _, chunkSize := range []int{1, 7, 16, 64, streamChunkSize} {
+		var output bytes.Buffer
+		err := resolveParametersInStream(context.Background(), &serviceObject, strings.NewReader(text), &output, ResolveOptions{}, chunkSize)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, output.String())
+	}
+}
+
+func TestResolveParametersInStreamFailures(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+
+	var output bytes.Buffer
+	err := ResolveParametersInStream(&serviceObject, strings.NewReader("{{ssm:/missing}}"), &output, ResolveOptions{})
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, output.Len())
+
+	err = ResolveParametersInStream(&serviceObject, strings.NewReader("a=b"), &output, ResolveOptions{Format: propertiesFormat})
+	assert.NotNil(t, err)
+}
+
+func TestResolveParametersInStreamLimitsTheWholeDocument(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	var output bytes.Buffer
+	text := strings.Repeat("host={{ssm:/app/host}}\n", 3)
+	err := resolveParametersInStream(context.Background(), &serviceObject, strings.NewReader(text), &output,
+		ResolveOptions{Limits: ResolveLimits{MaxPlaceholders: 2}}, 24)
+
+	assert.True(t, errors.Is(err, ErrLimitExceeded))
+	assert.Equal(t, 0, output.Len())
+
+	err = resolveParametersInStream(context.Background(), &serviceObject, strings.NewReader("{{ssm:/app/{{ssm:/app/host}}}}"), &output,
+		ResolveOptions{}, streamChunkSize)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, output.Len())
+}
+
+func TestStreamSegmentEnd(t *testing.T) {
+	assert.Equal(t, 4, streamSegmentEnd("abc {{ssm:/a"))
+	assert.Equal(t, 3, streamSegmentEnd("abc{"))
+	assert.Equal(t, 10, streamSegmentEnd("{{ssm:/a}}"))
+	assert.Equal(t, 4, streamSegmentEnd("{{}}{{ssm:/a}"))
+}
@@ -0,0 +1,59 @@
+package resolver
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const jsonArrayTransformer = "jsonarray"
+
+//
+// Element of a StringList value selected right after the parameter reference, e.g. {{ssm:/app/hosts[0]}} for the
+// first host. Elements are counted from 0, the selected element is passed to the modifiers that follow.
+var elementModifier = regexp.MustCompile("^\\[([0-9]+)\\]$")
+
+// parses an element modifier like [1] into its index
+func parseElementModifier(modifier string) (index int, isElement bool) {
+	match := elementModifier.FindStringSubmatch(modifier)
+	if match == nil {
+		return 0, false
+	}
+
+	index, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+
+	return index, true
+}
+
+// returns the element index of the comma separated value
+func selectElement(value string, index int) (string, error) {
+	elements := []string{}
+	if len(value) > 0 {
+		elements = strings.Split(value, ",")
+	}
+
+	if index >= len(elements) {
+		return "", errors.New("value has " + strconv.Itoa(len(elements)) + " element(s), there is no element " +
+			strconv.Itoa(index))
+	}
+
+	return elements[index], nil
+}
+
+// formats the items of a StringList value as a JSON array of strings, e.g. a,b gives ["a","b"]
+func jsonArray(value string) (string, error) {
+	if len(value) == 0 {
+		return "[]", nil
+	}
+
+	items := strings.Split(value, ",")
+	for i, item := range items {
+		items[i] = "\"" + escapeJsonString(item) + "\""
+	}
+
+	return "[" + strings.Join(items, ",") + "]", nil
+}
@@ -0,0 +1,50 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringListElements(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/hosts": {Name: "/app/hosts", Type: "StringList", Value: "web1,web 2,web3"},
+		"ssm:/app/empty": {Name: "/app/empty", Type: "StringList", Value: ""},
+	})
+
+	text := "primary={{ssm:/app/hosts[0]}}\nsecondary={{ ssm:/app/hosts[1] | shellquote }}\nall={{ssm:/app/hosts}}"
+	output, err := ResolveParametersInText(&serviceObject, text, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "primary=web1\nsecondary='web 2'\nall=web1,web 2,web3", output)
+
+	for _, text := range []string{"{{ssm:/app/hosts[3]}}", "{{ssm:/app/empty[0]}}"} {
+		_, err = ResolveParametersInText(&serviceObject, text, ResolveOptions{})
+		assert.NotNil(t, err, text)
+		assert.Equal(t, StatusPolicyViolation, StatusOf(err), text)
+	}
+}
+
+func TestJsonArrayTransformer(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/hosts": {Name: "/app/hosts", Type: "StringList", Value: "web1,\"web2\""},
+		"ssm:/app/empty": {Name: "/app/empty", Type: "StringList", Value: ""},
+	})
+
+	output, err := ResolveParametersInJSON(&serviceObject,
+		`{"hosts": {{ssm:/app/hosts | jsonarray}}, "none": {{ssm:/app/empty | jsonarray}}}`, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{"hosts": ["web1","\"web2\""], "none": []}`, output)
+}
+
+func TestParseElementModifier(t *testing.T) {
+	index, isElement := parseElementModifier("[12]")
+	assert.True(t, isElement)
+	assert.Equal(t, 12, index)
+
+	for _, modifier := range []string{"[-1]", "[]", "[a]", "shellquote"} {
+		_, isElement = parseElementModifier(modifier)
+		assert.False(t, isElement, modifier)
+	}
+}
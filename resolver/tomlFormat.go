@@ -0,0 +1,162 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const tomlFormat = "toml"
+
+// syntactic context of a position in a TOML document
+type tomlContext int
+
+const (
+	tomlBare tomlContext = iota
+	tomlComment
+	tomlBasicString
+	tomlMultiLineBasicString
+	tomlLiteralString
+	tomlMultiLineLiteralString
+)
+
+//
+// Values that can be substituted outside of TOML strings: integers, floats and booleans
+var tomlBareScalar = regexp.MustCompile("^([+-]?(0|[1-9](_?[0-9])*)(\\.[0-9](_?[0-9])*)?([eE][+-]?[0-9](_?[0-9])*)?|[+-]?(inf|nan)|true|false)$")
+
+// escapes value for the TOML string around offset. Literal strings cannot escape anything, so values they cannot
+// hold fail. Outside of strings only integers, floats and booleans can be substituted, to keep the document valid TOML.
+func escapeTomlValue(template string, offset int, value string) (string, error) {
+	switch tomlContextAt(template, offset) {
+	case tomlBasicString:
+		return escapeTomlBasicString(value, false), nil
+
+	case tomlMultiLineBasicString:
+		return escapeTomlBasicString(value, true), nil
+
+	case tomlLiteralString:
+		if strings.ContainsAny(value, "'\n\r") || containsControlCharacter(value, "\t") {
+			return "", errors.New("value cannot be held by a TOML literal string, use a basic \"string\"")
+		}
+		return value, nil
+
+	case tomlMultiLineLiteralString:
+		if strings.Contains(value, "'''") || containsControlCharacter(value, "\t\n\r") {
+			return "", errors.New("value cannot be held by a TOML multi-line literal string, use a basic \"\"\"string\"\"\"")
+		}
+		return value, nil
+
+	case tomlComment:
+		if strings.ContainsAny(value, "\n\r") {
+			return "", errors.New("multi-line value cannot be substituted into a TOML comment")
+		}
+		return value, nil
+
+	default:
+		if !tomlBareScalar.MatchString(value) {
+			return "", errors.New("value is not a TOML integer, float or boolean, put the placeholder inside a \"string\"")
+		}
+		return value, nil
+	}
+}
+
+// returns the context of offset scanning template from the start. Placeholders are skipped so that characters
+// of their modifiers are not taken for TOML syntax.
+func tomlContextAt(template string, offset int) tomlContext {
+	context := tomlBare
+	for i := 0; i < offset; i++ {
+		if strings.HasPrefix(template[i:], "{{") {
+			if end := strings.Index(template[i:], "}}"); end > 0 && i+end < offset {
+				i += end + 1
+				continue
+			}
+		}
+
+		rest := template[i:]
+		switch context {
+		case tomlBare:
+			switch {
+			case rest[0] == '#':
+				context = tomlComment
+			case strings.HasPrefix(rest, `"""`):
+				context = tomlMultiLineBasicString
+				i += 2
+			case rest[0] == '"':
+				context = tomlBasicString
+			case strings.HasPrefix(rest, "'''"):
+				context = tomlMultiLineLiteralString
+				i += 2
+			case rest[0] == '\'':
+				context = tomlLiteralString
+			}
+
+		case tomlComment:
+			if rest[0] == '\n' {
+				context = tomlBare
+			}
+
+		case tomlBasicString, tomlMultiLineBasicString:
+			switch {
+			case rest[0] == '\\':
+				i++
+			case context == tomlMultiLineBasicString && strings.HasPrefix(rest, `"""`):
+				context = tomlBare
+				i += 2
+			case context == tomlBasicString && (rest[0] == '"' || rest[0] == '\n'):
+				context = tomlBare
+			}
+
+		case tomlLiteralString:
+			if rest[0] == '\'' || rest[0] == '\n' {
+				context = tomlBare
+			}
+
+		case tomlMultiLineLiteralString:
+			if strings.HasPrefix(rest, "'''") {
+				context = tomlBare
+				i += 2
+			}
+		}
+	}
+
+	return context
+}
+
+// escapes backslashes, quotes and control characters, line breaks are kept in multi-line strings
+func escapeTomlBasicString(value string, multiLine bool) string {
+	var escaped strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '\\':
+			escaped.WriteString("\\\\")
+		case r == '"':
+			escaped.WriteString("\\\"")
+		case r == '\n' && multiLine:
+			escaped.WriteRune(r)
+		case r == '\n':
+			escaped.WriteString("\\n")
+		case r == '\r':
+			escaped.WriteString("\\r")
+		case r == '\t':
+			escaped.WriteString("\\t")
+		case r < 0x20 || r == 0x7f:
+			escaped.WriteString(fmt.Sprintf("\\u%04X", r))
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+
+	return escaped.String()
+}
+
+// reports whether value has control characters other than the allowed ones
+func containsControlCharacter(value string, allowed string) bool {
+	for _, r := range value {
+		if (r < 0x20 || r == 0x7f) && !strings.ContainsRune(allowed, r) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package resolver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeTomlValue(t *testing.T) {
+	testCases := []struct {
+		template string
+		value    string
+		expected string
+		valid    bool
+	}{
+		{`password = "{{ssm:x}}"`, `a"b\c` + "\n", `a\"b\\c\n`, true},
+		{`key = """` + "\n{{ssm:x}}", "line1\nline \"2\"", "line1\nline \\\"2\\\"", true},
+		{`path = '{{ssm:x}}'`, `C:\dir`, `C:\dir`, true},
+		{`path = '{{ssm:x}}'`, `it's`, ``, false},
+		{`pem = '''{{ssm:x}}`, "-----BEGIN-----\nabc", "-----BEGIN-----\nabc", true},
+		{`port = {{ssm:x}}`, `8080`, `8080`, true},
+		{`enabled = {{ssm:x}}`, `true`, `true`, true},
+		{`host = {{ssm:x}}`, `example.com`, ``, false},
+		{`# owner {{ssm:x}}`, `team`, `team`, true},
+		{`a = "#{{ssm:y | match=^"}}" # "` + "\nb = {{ssm:x}}", `1.5`, `1.5`, true},
+		{`a = "x\"y" b = "{{ssm:x}}"`, `"`, `\"`, true},
+	}
+
+	for _, testCase := range testCases {
+		offset := strings.LastIndex(testCase.template, "{{ssm:x}}")
+		escaped, err := escapeTomlValue(testCase.template, offset, testCase.value)
+		assert.Equal(t, testCase.valid, err == nil, testCase.template)
+		assert.Equal(t, testCase.expected, escaped, testCase.template)
+	}
+}
+
+func TestResolveParametersInTextTomlFormat(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/port":            {Name: "/app/port", Type: stringType, Value: "5432"},
+		"ssm-secure:/app/password": {Name: "/app/password", Type: secureStringType, Value: `p"w\d`},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject, "[database]\nport = {{ssm:/app/port}}\npassword = \"{{ssm-secure:/app/password}}\"", ResolveOptions{
+		Format: tomlFormat,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "[database]\nport = 5432\npassword = \"p\\\"w\\\\d\"", output)
+}
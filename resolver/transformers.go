@@ -0,0 +1,236 @@
+package resolver
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const shellQuoteTransformer = "shellquote"
+const xmlEscapeTransformer = "xmlescape"
+const urlEncodeTransformer = "urlencode"
+const htmlEscapeTransformer = "htmlescape"
+const binaryTransformer = "binary"
+const eachTransformer = "each"
+
+var transformersMutex sync.RWMutex
+
+//
+// Transformers that can be applied to a parameter value in a placeholder, e.g. {{ssm:name | shellquote}}.
+// Transformers are applied left to right.
+var transformers = map[string]func(value string) (string, error){
+	shellQuoteTransformer: shellQuote,
+	xmlEscapeTransformer:  xmlEscape,
+	urlEncodeTransformer:  urlEncode,
+	htmlEscapeTransformer: htmlEscape,
+	pemLeafTransformer:    pemLeaf,
+	pemChainTransformer:   pemChain,
+	oneLineTransformer:    oneLine,
+	pkcs8Transformer:      pkcs8,
+	binaryTransformer:     decodeBinary,
+	jsonArrayTransformer:  jsonArray,
+}
+
+//
+// Transformers escaping values for the syntax of the document around the placeholder
+var escapingTransformers = []string{shellQuoteTransformer, xmlEscapeTransformer, urlEncodeTransformer, htmlEscapeTransformer}
+
+//
+// Transformers taking an argument, listed among the modifiers of a placeholder as name=argument like constraints,
+// e.g. {{ssm:/app/hosts | each=server %s;}}
+var argumentTransformers = map[string]func(value string, argument string) (string, error){
+	eachTransformer: expandEach,
+}
+
+//
+// Escape sequences allowed in the arguments of transformers, which cannot hold line breaks otherwise
+var transformerArgumentUnescaper = strings.NewReplacer("\\n", "\n", "\\t", "\t", "\\\\", "\\")
+
+// splits the modifiers part of a placeholder like "| a | b " into a list of modifiers, dropping inline comments;
+// separators and comments between backquotes are part of the modifier
+func parsePlaceholderModifiers(modifiers string) []string {
+	result := []string{}
+
+	var modifier strings.Builder
+	appendModifier := func() {
+		if trimmed := strings.TrimSpace(modifier.String()); len(trimmed) > 0 {
+			result = append(result, trimmed)
+		}
+		modifier.Reset()
+	}
+
+	quoted := false
+	for i := 0; i < len(modifiers); i++ {
+		switch {
+		case modifiers[i] == '`':
+			quoted = !quoted
+		case quoted:
+		case modifiers[i] == '|':
+			appendModifier()
+			continue
+		case strings.HasPrefix(modifiers[i:], "<!--"):
+			if end := strings.Index(modifiers[i:], "-->"); end >= 0 {
+				i += end + len("-->") - 1
+				continue
+			}
+		}
+		modifier.WriteByte(modifiers[i])
+	}
+	appendModifier()
+
+	return result
+}
+
+// passes value through every transformer in the list and checks it against every constraint
+func applyTransformers(value string, transformerNames []string) (string, error) {
+	for _, name := range transformerNames {
+		if _, isDefault, _ := parseDefaultModifier(name); isDefault {
+			continue
+		}
+
+		if index, isElement := parseElementModifier(name); isElement {
+			var err error
+			value, err = selectElement(value, index)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", name, err)
+			}
+			continue
+		}
+
+		if constraintName, argument, isConstraint := parseConstraintModifier(name); isConstraint {
+			if transform, contains := argumentTransformers[constraintName]; contains {
+				var err error
+				value, err = transform(value, argument)
+				if err != nil {
+					return "", fmt.Errorf("%s: %w", constraintName, err)
+				}
+				continue
+			}
+
+			check, contains := constraints[constraintName]
+			if !contains {
+				return "", errors.New("unknown constraint " + constraintName)
+			}
+			if err := check(value, argument); err != nil {
+				return "", fmt.Errorf("%s: %w", name, err)
+			}
+			continue
+		}
+
+		transform, contains := lookupTransformer(name)
+		if !contains {
+			return "", errors.New("unknown transformer " + name)
+		}
+
+		var err error
+		value, err = transform(value)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return value, nil
+}
+
+// returns the transformer named name, built in or registered
+func lookupTransformer(name string) (func(value string) (string, error), bool) {
+	transformersMutex.RLock()
+	defer transformersMutex.RUnlock()
+
+	transform, contains := transformers[name]
+	return transform, contains
+}
+
+// checks that every placeholder in text uses known transformers and valid constraints only
+func validatePlaceholderModifiers(text string, options ResolveOptions) error {
+	for _, placeholder := range parameterPlaceholders() {
+		for _, match := range placeholder.FindAllStringSubmatch(text, -1) {
+			for _, name := range parsePlaceholderModifiers(match[2]) {
+				if _, isDefault, err := parseDefaultModifier(name); isDefault {
+					if err != nil {
+						return fmt.Errorf("%w in placeholder %s", err, match[0])
+					}
+					continue
+				}
+				if _, isElement := parseElementModifier(name); isElement {
+					continue
+				}
+				if _, _, isConstraint := parseConstraintModifier(name); isConstraint {
+					if err := validateConstraintModifier(name); err != nil {
+						return fmt.Errorf("%w in placeholder %s", err, match[0])
+					}
+					continue
+				}
+				if _, contains := lookupTransformer(name); !contains {
+					return errors.New("unknown transformer " + name + " in placeholder " + match[0])
+				}
+				if name == binaryTransformer && !options.allowBinaryValues {
+					return errors.New("transformer " + binaryTransformer + " in placeholder " + match[0] + " can only be used when resolving into a file")
+				}
+			}
+		}
+	}
+
+	return validatePlaceholderDefaults(text)
+}
+
+// expands every item of a StringList value into the template, replacing %s with the item,
+// e.g. server %s;\n gives a server directive per line
+func expandEach(value string, template string) (string, error) {
+	if len(value) == 0 {
+		return "", nil
+	}
+
+	template = transformerArgumentUnescaper.Replace(template)
+
+	var expanded strings.Builder
+	for _, item := range strings.Split(value, ",") {
+		expanded.WriteString(strings.Replace(template, "%s", item, -1))
+	}
+
+	return expanded.String(), nil
+}
+
+// wraps value in single quotes so that a POSIX shell treats it as one literal word
+func shellQuote(value string) (string, error) {
+	return "'" + strings.Replace(value, "'", "'\\''", -1) + "'", nil
+}
+
+//
+// Replaces characters which cannot appear verbatim in XML text nodes and attribute values
+var xmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"\"", "&quot;",
+	"'", "&apos;",
+)
+
+// escapes value for use in XML text nodes and attribute values
+func xmlEscape(value string) (string, error) {
+	return xmlEscaper.Replace(value), nil
+}
+
+// percent-encodes value for use as a URL query or path component
+func urlEncode(value string) (string, error) {
+	return url.QueryEscape(value), nil
+}
+
+// escapes value for use in HTML text and attribute values
+func htmlEscape(value string) (string, error) {
+	return html.EscapeString(value), nil
+}
+
+// decodes a base64 encoded binary value into its raw bytes
+func decodeBinary(value string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return "", err
+	}
+
+	return string(decoded), nil
+}
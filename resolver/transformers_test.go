@@ -0,0 +1,88 @@
+package resolver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyTransformers(t *testing.T) {
+	testCases := []struct {
+		value        string
+		transformers []string
+		expected     string
+	}{
+		{`it's`, []string{shellQuoteTransformer}, `'it'\''s'`},
+		{`<a href="x">Tom & Jerry's</a>`, []string{xmlEscapeTransformer}, `&lt;a href=&quot;x&quot;&gt;Tom &amp; Jerry&apos;s&lt;/a&gt;`},
+		{`a b&c=d/e`, []string{urlEncodeTransformer}, `a+b%26c%3Dd%2Fe`},
+		{`<b>"x" & 'y'</b>`, []string{htmlEscapeTransformer}, `&lt;b&gt;&#34;x&#34; &amp; &#39;y&#39;&lt;/b&gt;`},
+		{`a&b`, []string{urlEncodeTransformer, shellQuoteTransformer}, `'a%26b'`},
+	}
+
+	for _, testCase := range testCases {
+		output, err := applyTransformers(testCase.value, testCase.transformers)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expected, output)
+	}
+}
+
+func TestApplyUnknownTransformer(t *testing.T) {
+	_, err := applyTransformers("value", []string{"nosuchtransformer"})
+
+	assert.NotNil(t, err)
+}
+
+func TestResolveParametersInTextWithTransformers(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/query": {Name: "/app/query", Type: stringType, Value: "a&b"},
+	})
+
+	text := `<url href="https://example.com/?q={{ssm:/app/query|urlencode}}">{{ ssm:/app/query | xmlescape }}</url>`
+	output, err := ResolveParametersInText(&serviceObject, text, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `<url href="https://example.com/?q=a%26b">a&amp;b</url>`, output)
+}
+
+func TestEachTransformer(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/hosts": {Name: "/app/hosts", Type: "StringList", Value: "10.0.0.1:80,10.0.0.2:80"},
+		"ssm:/app/empty": {Name: "/app/empty", Type: "StringList", Value: ""},
+	})
+
+	text := "upstream app {\n{{ssm:/app/hosts | each=    server %s;\\n}}}\n{{ssm:/app/empty | each=server %s;}}"
+	output, err := ResolveParametersInText(&serviceObject, text, ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "upstream app {\nserver 10.0.0.1:80;\nserver 10.0.0.2:80;\n}\n", output)
+
+	_, err = ResolveParametersInText(&serviceObject, "{{ssm:/app/hosts | each=server;}}", ResolveOptions{})
+	assert.Equal(t, StatusParseError, StatusOf(err))
+}
+
+func TestBinaryTransformerOnlyInFiles(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm-secure:/app/keystore": {Name: "/app/keystore", Type: secureStringType, Value: "AAEC/w=="},
+	})
+
+	_, err := ResolveParametersInText(&serviceObject, "{{ssm-secure:/app/keystore|binary}}", ResolveOptions{})
+	assert.NotNil(t, err)
+
+	dir, err := ioutil.TempDir("", "binary")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "keystore.tmpl")
+	outputFileName := filepath.Join(dir, "keystore.jks")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("{{ssm-secure:/app/keystore|binary}}"), 0644))
+
+	err = ResolveParametersInFile(&serviceObject, inputFileName, outputFileName, ResolveOptions{})
+	assert.Nil(t, err)
+
+	output, err := ioutil.ReadFile(outputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0, 1, 2, 255}, output)
+}
@@ -0,0 +1,38 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+//
+// Lists the parameters under path (recursively when recursive is set) and returns the names of those no template
+// of index references, sorted, e.g. to clean up dead parameters. Parameters are matched by name, whatever the prefix
+// of the references.
+func FindUnreferencedParameters(
+	ctx context.Context,
+	service ISsmParameterService,
+	index *ParameterIndex,
+	path string,
+	recursive bool) ([]string, error) {
+
+	if len(path) == 0 {
+		return nil, errors.New("path is not provided")
+	}
+
+	parameters, err := service.callGetParametersByPath(ctx, path, recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	unreferenced := []string{}
+	for _, param := range parameters {
+		if _, referenced := index.Parameters[param.Name]; !referenced {
+			unreferenced = append(unreferenced, param.Name)
+		}
+	}
+	sort.Strings(unreferenced)
+
+	return unreferenced, nil
+}
@@ -0,0 +1,35 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindUnreferencedParameters(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host":            {Name: "/app/host", Type: stringType, Value: "db.internal"},
+		"ssm-secure:/app/password": {Name: "/app/password", Type: secureStringType, Value: "secret"},
+		"ssm:/app/legacy/flag":     {Name: "/app/legacy/flag", Type: stringType, Value: "true"},
+		"ssm:/app/legacy/mode":     {Name: "/app/legacy/mode", Type: stringType, Value: "old"},
+		"ssm:/other/host":          {Name: "/other/host", Type: stringType, Value: "other.internal"},
+	})
+
+	index, err := BuildIndex(fstest.MapFS{
+		"app.conf": {Data: []byte("host={{ssm:/app/host}}\npassword={{ssm-secure:/app/password}}\nmode={{ssm:/app/legacy/mode}}")},
+	})
+	assert.Nil(t, err)
+
+	unreferenced, err := FindUnreferencedParameters(context.Background(), &serviceObject, index, "/app", true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/app/legacy/flag"}, unreferenced)
+
+	unreferenced, err = FindUnreferencedParameters(context.Background(), &serviceObject, index, "/app", false)
+	assert.Nil(t, err)
+	assert.Empty(t, unreferenced)
+
+	_, err = FindUnreferencedParameters(context.Background(), &serviceObject, index, "", true)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,83 @@
+package resolver
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//
+// Prefixes of the built-in placeholders, which can be left unresolved in a resolved document
+var builtInPlaceholderPrefixes = []string{ssmNonSecurePrefix, ssmSecurePrefix, secretsManagerPrefix, envPrefix}
+
+// fails with an UnresolvedPlaceholdersError when FailOnUnresolvedPlaceholders is set and resolvedText, which is not
+// yet passed to restorePlaceholderDelimiters, still holds placeholders
+func checkUnresolvedPlaceholders(resolvedText string, options ResolveOptions) error {
+	if !options.FailOnUnresolvedPlaceholders {
+		return nil
+	}
+
+	body := unresolvedPlaceholderBody()
+	patterns := []*regexp.Regexp{placeholderPatterns.get(defaultOpenDelimiter + body + defaultCloseDelimiter)}
+	if options.PlaceholderSyntax.isCustom() {
+		patterns = append(patterns, placeholderPatterns.get(regexp.QuoteMeta(options.PlaceholderSyntax.Open)+
+			body+regexp.QuoteMeta(options.PlaceholderSyntax.Close)))
+	}
+
+	matches := [][]int{}
+	for _, pattern := range patterns {
+		matches = append(matches, pattern.FindAllStringSubmatchIndex(resolvedText, -1)...)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i][0] < matches[j][0] })
+
+	placeholders := []string{}
+	seen := map[string]bool{}
+	for _, match := range matches {
+		kind := resolvedText[match[2]:match[3]]
+		if options.IgnoreSecureParameters && isSecurePlaceholderKind(kind) {
+			continue
+		}
+
+		placeholder := strings.ReplaceAll(resolvedText[match[0]:match[1]], escapedOpenDelimiter, defaultOpenDelimiter)
+		if !seen[placeholder] {
+			seen[placeholder] = true
+			placeholders = append(placeholders, placeholder)
+		}
+	}
+
+	if len(placeholders) > 0 {
+		return withStatus(StatusParseError, &UnresolvedPlaceholdersError{Placeholders: placeholders})
+	}
+
+	return nil
+}
+
+// returns the placeholder text left in a resolved document, well-formed or not, between the delimiters of the
+// placeholders. The first group is the kind of reference, e.g. ssm-secure, of a built-in or registered source.
+func unresolvedPlaceholderBody() string {
+	prefixes := append([]string{}, builtInPlaceholderPrefixes...)
+	sourcesMutex.RLock()
+	for prefix := range sources {
+		prefixes = append(prefixes, prefix)
+	}
+	sourcesMutex.RUnlock()
+	sort.Strings(prefixes)
+
+	kinds := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		kinds[i] = regexp.QuoteMeta(strings.TrimSuffix(prefix, ":"))
+	}
+
+	return "\\s*(" + strings.Join(kinds, "|") + ")\\s*:[^{}\\n]*?"
+}
+
+// tells whether the placeholders of kind are left unresolved by IgnoreSecureParameters
+func isSecurePlaceholderKind(kind string) bool {
+	switch kind + ":" {
+	case ssmSecurePrefix, secretsManagerPrefix, envPrefix:
+		return true
+	}
+
+	source, found := lookupSource(kind + ":")
+	return found && source.Secure
+}
@@ -0,0 +1,146 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersInTextFailsOnUnresolvedPlaceholders(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+	})
+	options := ResolveOptions{FailOnUnresolvedPlaceholders: true}
+
+	resolved, err := ResolveParametersInText(&serviceObject, "host={{ssm:/app/host}}", options)
+	assert.Nil(t, err)
+	assert.Equal(t, "host=example.com", resolved)
+
+	for input, expected := range map[string][]string{
+		"host={{ssm:/app/host}} port={{ssm:/app/db port}} {{ ssm : /app/x }}": {"{{ssm:/app/db port}}", "{{ ssm : /app/x }}"},
+		"only {{ssm:/app/db host}} twice {{ssm:/app/db host}}":                {"{{ssm:/app/db host}}"},
+	} {
+		_, err = ResolveParametersInText(&serviceObject, input, options)
+
+		var unresolvedPlaceholdersError *UnresolvedPlaceholdersError
+		assert.True(t, errors.As(err, &unresolvedPlaceholdersError), input)
+		assert.Equal(t, expected, unresolvedPlaceholdersError.Placeholders)
+		assert.Equal(t, StatusParseError, StatusOf(err))
+	}
+
+	// leftovers are fine without the option
+	resolved, err = ResolveParametersInText(&serviceObject, "host={{ssm:/app/host}} port={{ssm:/app/db port}}", ResolveOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "host=example.com port={{ssm:/app/db port}}", resolved)
+}
+
+func TestUnresolvedPlaceholdersWithIgnoredSecureParameters(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+	})
+
+	resolved, err := ResolveParametersInText(&serviceObject, "{{ssm:/app/host}} {{ssm-secure:/app/token}} {{secretsmanager:prod/db}}",
+		ResolveOptions{FailOnUnresolvedPlaceholders: true, IgnoreSecureParameters: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com {{ssm-secure:/app/token}} {{secretsmanager:prod/db}}", resolved)
+}
+
+func TestResolveParametersInFileFailsOnUnresolvedPlaceholders(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+	})
+
+	dir, err := ioutil.TempDir("", "unresolved")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "app.conf.tmpl")
+	outputFileName := filepath.Join(dir, "app.conf")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("host=${ssm:/app/host}\nport=${ssm:/app/db port}\nhelm={{ .Values.port }}"), 0644))
+
+	err = ResolveParametersInFile(&serviceObject, inputFileName, outputFileName,
+		ResolveOptions{FailOnUnresolvedPlaceholders: true, PlaceholderSyntax: PlaceholderSyntax{Open: "${", Close: "}"}})
+
+	var unresolvedPlaceholdersError *UnresolvedPlaceholdersError
+	assert.True(t, errors.As(err, &unresolvedPlaceholdersError))
+	assert.Equal(t, []string{"${ssm:/app/db port}"}, unresolvedPlaceholdersError.Placeholders)
+
+	_, err = os.Stat(outputFileName)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRenderSetFailsOnUnresolvedPlaceholders(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+	})
+
+	set := NewRenderSet(&serviceObject, ResolveOptions{FailOnUnresolvedPlaceholders: true})
+	assert.Nil(t, set.Add("a", "{{ssm:/app/host}}"))
+	assert.Nil(t, set.Add("b", "{{render:a}} {{ssm:/app/db host}}", "a"))
+	report, err := set.Execute(context.Background())
+
+	var unresolvedPlaceholdersError *UnresolvedPlaceholdersError
+	assert.True(t, errors.As(err, &unresolvedPlaceholdersError))
+	assert.Equal(t, []string{"{{ssm:/app/db host}}"}, unresolvedPlaceholdersError.Placeholders)
+	assert.Equal(t, "example.com", report.Documents["a"].Output)
+}
+
+func TestDocumentAPIsFailOnUnresolvedPlaceholders(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "example.com"},
+	})
+	options := ResolveOptions{FailOnUnresolvedPlaceholders: true}
+	expected := []string{"{{ssm:/app/db host}}"}
+
+	_, _, err := ResolveParametersInTextByLine(&serviceObject, "host={{ssm:/app/host}}\nport={{ssm:/app/db host}}", options)
+	var unresolvedPlaceholdersError *UnresolvedPlaceholdersError
+	assert.True(t, errors.As(err, &unresolvedPlaceholdersError))
+	assert.Equal(t, expected, unresolvedPlaceholdersError.Placeholders)
+
+	_, _, err = RenderAnnotated(&serviceObject, "host={{ssm:/app/host}} port={{ssm:/app/db host}}", options)
+	assert.True(t, errors.As(err, &unresolvedPlaceholdersError))
+	assert.Equal(t, expected, unresolvedPlaceholdersError.Placeholders)
+
+	// comments are not resolved, their placeholders are expected
+	resolved, err := ResolveParametersInDockerfile(&serviceObject, "# {{ssm:/app/db host}}\nENV HOST={{ssm:/app/host}}", options)
+	assert.Nil(t, err)
+	assert.Equal(t, "# {{ssm:/app/db host}}\nENV HOST=example.com", resolved)
+
+	_, err = ResolveParametersInDockerfile(&serviceObject, "ENV HOST={{ssm:/app/host}} PORT={{ssm:/app/db host}}", options)
+	assert.True(t, errors.As(err, &unresolvedPlaceholdersError))
+	assert.Equal(t, expected, unresolvedPlaceholdersError.Placeholders)
+
+	_, err = ResolveParametersInEcsTaskDefinition(&serviceObject, `{"family": "web", "containerDefinitions": [
+		{"name": "web", "image": "nginx", "dockerLabels": {"host": "{{ssm:/app/host}}", "port": "{{ssm:/app/db host}}"}}]}`, options)
+	assert.True(t, errors.As(err, &unresolvedPlaceholdersError))
+	assert.Equal(t, expected, unresolvedPlaceholdersError.Placeholders)
+
+	_, err = ResolveParametersWithOverlays(&serviceObject, `{"host": "{{ssm:/app/host}}"}`,
+		map[string]string{"prod": `{"port": "{{ssm:/app/db host}}"}`}, options)
+	assert.True(t, errors.As(err, &unresolvedPlaceholdersError))
+	assert.Equal(t, expected, unresolvedPlaceholdersError.Placeholders)
+}
+
+func TestUnresolvedPlaceholdersOfRegisteredSources(t *testing.T) {
+	fetch := func(ctx context.Context, name string) (string, error) {
+		return "value", nil
+	}
+	assert.Nil(t, RegisterSource("testunresolved:", Source{Fetch: fetch}))
+	assert.Nil(t, RegisterSource("testunresolved-secure:", Source{Fetch: fetch, Secure: true}))
+	defer unregisterSource("testunresolved:")
+	defer unregisterSource("testunresolved-secure:")
+
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+
+	_, err := ResolveParametersInText(&serviceObject, "{{testunresolved:a}} {{testunresolved:a b}} {{testunresolved-secure:c}}",
+		ResolveOptions{FailOnUnresolvedPlaceholders: true, IgnoreSecureParameters: true})
+
+	var unresolvedPlaceholdersError *UnresolvedPlaceholdersError
+	assert.True(t, errors.As(err, &unresolvedPlaceholdersError))
+	assert.Equal(t, []string{"{{testunresolved:a b}}"}, unresolvedPlaceholdersError.Placeholders)
+}
@@ -0,0 +1,32 @@
+package resolver
+
+import (
+	v1 "github.com/parameterResolver/resolver"
+)
+
+//
+// Typed errors of the resolve calls, use errors.As to get their details. They are the errors of the free functions,
+// so that code checking them keeps working during the migration.
+type (
+	MissingParametersError          = v1.MissingParametersError
+	SecureParametersNotAllowedError = v1.SecureParametersNotAllowedError
+	UnresolvedPlaceholdersError     = v1.UnresolvedPlaceholdersError
+	StatusError                     = v1.StatusError
+	Status                          = v1.Status
+)
+
+const (
+	StatusOK              = v1.StatusOK
+	StatusGenericError    = v1.StatusGenericError
+	StatusParseError      = v1.StatusParseError
+	StatusPolicyViolation = v1.StatusPolicyViolation
+	StatusNotFound        = v1.StatusNotFound
+	StatusAwsError        = v1.StatusAwsError
+	StatusPartial         = v1.StatusPartial
+)
+
+//
+// Returns the Status of the failure err of a resolve call, StatusOK for nil.
+func StatusOf(err error) Status {
+	return v1.StatusOf(err)
+}
@@ -0,0 +1,113 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+//
+// Interval at which a started Resolver drops the expired parameters of its cache
+const cacheJanitorInterval = time.Minute
+
+//
+// BackgroundTask is a component of an agent embedding a Resolver, e.g. a watcher re-rendering files when parameters
+// change or a flusher of metrics, run by Start until ctx is done
+type BackgroundTask func(ctx context.Context)
+
+// cache whose expired parameters can be dropped, e.g. MemoryCache of v1
+type expiringCache interface {
+	PurgeExpired()
+}
+
+// background tasks and in-flight calls of a Resolver
+type lifecycle struct {
+	mutex   sync.Mutex
+	tasks   []BackgroundTask
+	cancel  context.CancelFunc
+	running sync.WaitGroup
+
+	// calls are added with the mutex held, so that none starts while Stop waits for them
+	inFlight sync.WaitGroup
+}
+
+//
+// Adds task to the background tasks run by Start, it is run from the next Start on when the Resolver is started.
+func (r *Resolver) AddBackgroundTask(task BackgroundTask) {
+	r.lifecycle.mutex.Lock()
+	defer r.lifecycle.mutex.Unlock()
+
+	r.lifecycle.tasks = append(r.lifecycle.tasks, task)
+}
+
+//
+// Starts the background tasks and, when the cache can drop expired parameters, a cache janitor.
+// They run until Stop is called or ctx is done. A Resolver can be started again after Stop,
+// e.g. when the agent embedding it restarts. Resolving does not need a started Resolver.
+func (r *Resolver) Start(ctx context.Context) error {
+	r.lifecycle.mutex.Lock()
+	defer r.lifecycle.mutex.Unlock()
+
+	if r.lifecycle.cancel != nil {
+		return errors.New("resolver is already started")
+	}
+
+	tasks := append([]BackgroundTask{}, r.lifecycle.tasks...)
+	if cache, ok := r.options.Cache.(expiringCache); ok {
+		tasks = append(tasks, cacheJanitor(cache))
+	}
+
+	ctx, r.lifecycle.cancel = context.WithCancel(ctx)
+	for _, task := range tasks {
+		r.lifecycle.running.Add(1)
+		go func(task BackgroundTask) {
+			defer r.lifecycle.running.Done()
+			task(ctx)
+		}(task)
+	}
+
+	return nil
+}
+
+//
+// Drains the Resolver gracefully: waits for the calls in flight, which calls made meanwhile wait for, then stops
+// the background tasks and waits for them to return. Stopping a Resolver that is not started only waits for the calls.
+func (r *Resolver) Stop() {
+	r.lifecycle.mutex.Lock()
+	defer r.lifecycle.mutex.Unlock()
+
+	r.lifecycle.inFlight.Wait()
+
+	if r.lifecycle.cancel != nil {
+		r.lifecycle.cancel()
+		r.lifecycle.cancel = nil
+	}
+	r.lifecycle.running.Wait()
+}
+
+// registers a call in flight and returns the function ending it
+func (r *Resolver) begin() func() {
+	r.lifecycle.mutex.Lock()
+	defer r.lifecycle.mutex.Unlock()
+
+	r.lifecycle.inFlight.Add(1)
+	return r.lifecycle.inFlight.Done
+}
+
+// returns a task dropping the expired parameters of cache every cacheJanitorInterval
+func cacheJanitor(cache expiringCache) BackgroundTask {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(cacheJanitorInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				cache.PurgeExpired()
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package resolver
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/parameterResolver/resolver"
+)
+
+func TestResolverStartStop(t *testing.T) {
+	resolver := newTestResolver(t, WithCache(v1.NewMemoryCache(time.Minute)))
+
+	var running, stopped int32
+	resolver.AddBackgroundTask(func(ctx context.Context) {
+		atomic.AddInt32(&running, 1)
+		<-ctx.Done()
+		atomic.AddInt32(&stopped, 1)
+	})
+
+	for restart := 1; restart <= 2; restart++ {
+		assert.Nil(t, resolver.Start(context.Background()))
+		assert.NotNil(t, resolver.Start(context.Background()))
+
+		resolved, err := resolver.ResolveText(context.Background(), "{{ssm:/app/host}}")
+		assert.Nil(t, err)
+		assert.Equal(t, "example.com", resolved)
+
+		resolver.Stop()
+		assert.Equal(t, int32(restart), atomic.LoadInt32(&running))
+		assert.Equal(t, int32(restart), atomic.LoadInt32(&stopped))
+	}
+
+	resolver.Stop()
+}
+
+func TestResolverStopDrainsCalls(t *testing.T) {
+	resolver := newTestResolver(t)
+	assert.Nil(t, resolver.Start(context.Background()))
+
+	done := resolver.begin()
+	stopped := make(chan struct{})
+	go func() {
+		resolver.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned with a call in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	done()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return once the call ended")
+	}
+}
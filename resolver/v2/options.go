@@ -0,0 +1,106 @@
+package resolver
+
+import (
+	v1 "github.com/parameterResolver/resolver"
+)
+
+//
+// Option sets up the ResolveOptions of a Resolver, see New
+type Option func(options *v1.ResolveOptions)
+
+//
+// Starts from options instead of the zero ResolveOptions, to migrate the calls of the free functions.
+// Options following it are applied on top.
+func WithOptions(options v1.ResolveOptions) Option {
+	return func(current *v1.ResolveOptions) {
+		*current = options
+	}
+}
+
+//
+// Leaves SecureString parameters unresolved instead of failing, see ResolveOptions.IgnoreSecureParameters
+func WithoutSecureParameters() Option {
+	return func(options *v1.ResolveOptions) {
+		options.IgnoreSecureParameters = true
+	}
+}
+
+//
+// Leaves the placeholders of missing parameters unresolved, see ResolveOptions.IgnoreMissingParameters
+func WithoutMissingParameters() Option {
+	return func(options *v1.ResolveOptions) {
+		options.IgnoreMissingParameters = true
+	}
+}
+
+//
+// Fails when placeholders are left in a resolved document, see ResolveOptions.FailOnUnresolvedPlaceholders
+func WithStrictPlaceholders() Option {
+	return func(options *v1.ResolveOptions) {
+		options.FailOnUnresolvedPlaceholders = true
+	}
+}
+
+//
+// Resolves placeholders found in parameter values too, see ResolveOptions.Recursive
+func WithRecursion() Option {
+	return func(options *v1.ResolveOptions) {
+		options.Recursive = true
+	}
+}
+
+//
+// Escapes resolved values with the format named name, e.g. json, see ResolveOptions.Format
+func WithFormat(name string) Option {
+	return func(options *v1.ResolveOptions) {
+		options.Format = name
+	}
+}
+
+//
+// Serves parameters fetched before from cache, see ResolveOptions.Cache
+func WithCache(cache v1.Cache) Option {
+	return func(options *v1.ResolveOptions) {
+		options.Cache = cache
+	}
+}
+
+//
+// Uses syntax for the placeholders of the documents, see ResolveOptions.PlaceholderSyntax
+func WithPlaceholderSyntax(syntax v1.PlaceholderSyntax) Option {
+	return func(options *v1.ResolveOptions) {
+		options.PlaceholderSyntax = syntax
+	}
+}
+
+//
+// Reports values likely to be misread where they are substituted to warn, see ResolveOptions.Warn
+func WithWarnings(warn func(warning v1.ValueWarning)) Option {
+	return func(options *v1.ResolveOptions) {
+		options.Warn = warn
+	}
+}
+
+//
+// Handles parameters with an empty value according to policy, see ResolveOptions.OnEmptyValue
+func WithEmptyValuePolicy(policy v1.EmptyValuePolicy) Option {
+	return func(options *v1.ResolveOptions) {
+		options.OnEmptyValue = policy
+	}
+}
+
+//
+// Resolves the public parameters AWS publishes under /aws/service/, see ResolveOptions.AllowPublicParameters
+func WithPublicParameters() Option {
+	return func(options *v1.ResolveOptions) {
+		options.AllowPublicParameters = true
+	}
+}
+
+//
+// Keeps the previous content of the files resolved in place with suffix, e.g. .bak, see ResolveOptions.BackupSuffix
+func WithBackup(suffix string) Option {
+	return func(options *v1.ResolveOptions) {
+		options.BackupSuffix = suffix
+	}
+}
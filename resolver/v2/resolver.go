@@ -0,0 +1,152 @@
+//
+// Package resolver is the v2 API of the parameter resolver: a Resolver bound to a service and its options, set up
+// with Option builders, whose calls all take a context and fail with typed errors.
+// The free functions of github.com/parameterResolver/resolver keep working as before and are deprecated
+// in favour of the methods of Resolver, which call them. Services, caches and the other types of the options
+// are the v1 ones.
+package resolver
+
+import (
+	"context"
+	"io"
+	"time"
+
+	v1 "github.com/parameterResolver/resolver"
+)
+
+//
+// Resolver resolves parameter placeholders with its service according to its options.
+// It is safe for concurrent use when its service, cache and callbacks are.
+type Resolver struct {
+	service   v1.ISsmParameterService
+	options   v1.ResolveOptions
+	lifecycle *lifecycle
+}
+
+//
+// Creates a Resolver fetching parameters with service, e.g. one created by NewService of v1, with the options
+// set up by opts in order.
+func New(service v1.ISsmParameterService, opts ...Option) *Resolver {
+	resolver := &Resolver{service: service, lifecycle: &lifecycle{}}
+	for _, opt := range opts {
+		opt(&resolver.options)
+	}
+
+	return resolver
+}
+
+//
+// Returns a copy of the ResolveOptions of the Resolver.
+func (r *Resolver) Options() v1.ResolveOptions {
+	return r.options
+}
+
+//
+// Returns a Resolver with the same service whose options are these of r with opts applied.
+// It has a lifecycle of its own, with no background tasks.
+func (r *Resolver) With(opts ...Option) *Resolver {
+	resolver := &Resolver{service: r.service, options: r.options, lifecycle: &lifecycle{}}
+	for _, opt := range opts {
+		opt(&resolver.options)
+	}
+
+	return resolver
+}
+
+//
+// Returns a Resolver with the same options fetching parameters through a resolution session: the non-secure parameters
+// fetched by any of its calls are served again to the calls made within window, e.g. a few seconds, without a cache.
+// It has a lifecycle of its own, with no background tasks. See SessionService of v1.
+func (r *Resolver) WithSession(window time.Duration) *Resolver {
+	return &Resolver{service: v1.NewSessionService(r.service, window), options: r.options, lifecycle: &lifecycle{}}
+}
+
+//
+// Resolves the placeholders of the text document input.
+func (r *Resolver) ResolveText(ctx context.Context, input string) (string, error) {
+	defer r.begin()()
+
+	return v1.ResolveParametersInTextWithContext(ctx, r.service, input, r.options)
+}
+
+//
+// Resolves the placeholders of input leaving the ones of missing parameters, and returns the sorted references
+// of the missing parameters.
+func (r *Resolver) ResolveTextPartially(ctx context.Context, input string) (string, []string, error) {
+	defer r.begin()()
+
+	return v1.ResolveParametersInTextPartially(ctx, r.service, input, r.options)
+}
+
+//
+// Resolves the placeholders of the JSON document input, escaping values for JSON and validating the result.
+func (r *Resolver) ResolveJSON(ctx context.Context, input string) (string, error) {
+	defer r.begin()()
+
+	return v1.ResolveParametersInJSONWithContext(ctx, r.service, input, r.options)
+}
+
+//
+// Reads a document from input and writes it with its placeholders resolved to output.
+func (r *Resolver) Resolve(ctx context.Context, input io.Reader, output io.Writer) error {
+	defer r.begin()()
+
+	return v1.ResolveParametersWithContext(ctx, r.service, input, output, r.options)
+}
+
+//
+// Resolves input to output segment by segment, see ResolveParametersInStream of v1.
+func (r *Resolver) ResolveStream(ctx context.Context, input io.ReadSeeker, output io.Writer) error {
+	defer r.begin()()
+
+	return v1.ResolveParametersInStreamWithContext(ctx, r.service, input, output, r.options)
+}
+
+//
+// Resolves the placeholders of the file inputFileName and writes the result to outputFileName.
+func (r *Resolver) ResolveFile(ctx context.Context, inputFileName string, outputFileName string) error {
+	defer r.begin()()
+
+	return v1.ResolveParametersInFileWithContext(ctx, r.service, inputFileName, outputFileName, r.options)
+}
+
+//
+// Resolves the placeholders of the file fileName and replaces it with the result atomically, see WithBackup.
+func (r *Resolver) ResolveFileInPlace(ctx context.Context, fileName string) error {
+	defer r.begin()()
+
+	return v1.ResolveParametersInPlaceWithContext(ctx, r.service, fileName, r.options)
+}
+
+//
+// Resolves parameter references like ssm:/app/host to their parameters.
+func (r *Resolver) ResolveReferences(ctx context.Context, parameterReferences []string) (map[string]v1.SsmParameterInfo, error) {
+	defer r.begin()()
+
+	return v1.ResolveParameterReferenceListWithContext(ctx, r.service, parameterReferences, r.options)
+}
+
+//
+// Resolves the parameter references of refsByKey and returns their values by the same keys.
+func (r *Resolver) ResolveMap(ctx context.Context, refsByKey map[string]string) (map[string]string, error) {
+	defer r.begin()()
+
+	return v1.ResolveMapWithContext(ctx, r.service, refsByKey, r.options)
+}
+
+//
+// Resolves the parameters under path, below it too when recursive, and returns them by name.
+func (r *Resolver) ResolvePath(ctx context.Context, path string, recursive bool) (map[string]v1.SsmParameterInfo, error) {
+	defer r.begin()()
+
+	return v1.ResolveParametersByPathWithContext(ctx, r.service, path, recursive, r.options)
+}
+
+//
+// Checks that the parameters of the placeholders of input exist and have the type of their prefix, without
+// returning their values.
+func (r *Resolver) ValidateReferences(ctx context.Context, input string) (v1.ReferenceValidationReport, error) {
+	defer r.begin()()
+
+	return v1.ValidateParameterReferences(ctx, r.service, input, r.options)
+}
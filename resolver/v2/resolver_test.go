@@ -0,0 +1,102 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/parameterResolver/resolver"
+)
+
+const testSnapshot = `{
+  "Parameters": [
+    {"Name": "/app/host", "Type": "String", "Value": "example.com"},
+    {"Name": "/app/port", "Type": "String", "Value": "443"},
+    {"Name": "/app/password", "Type": "SecureString", "Value": "s3\"cr3t"}
+  ]
+}`
+
+func newTestResolver(t *testing.T, opts ...Option) *Resolver {
+	service, err := v1.NewSnapshotService(strings.NewReader(testSnapshot))
+	assert.Nil(t, err)
+
+	return New(service, opts...)
+}
+
+func TestResolver(t *testing.T) {
+	resolver := newTestResolver(t)
+	ctx := context.Background()
+
+	resolved, err := resolver.ResolveText(ctx, "{{ssm:/app/host}}:{{ssm:/app/port}}")
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com:443", resolved)
+
+	resolved, err = resolver.ResolveJSON(ctx, `{"password": "{{ssm-secure:/app/password}}", "port": {{ssm:/app/port}}}`)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"password": "s3\"cr3t", "port": 443}`, resolved)
+
+	var output bytes.Buffer
+	assert.Nil(t, resolver.Resolve(ctx, strings.NewReader("host={{ssm:/app/host}}"), &output))
+	assert.Equal(t, "host=example.com", output.String())
+
+	values, err := resolver.ResolveMap(ctx, map[string]string{"host": "ssm:/app/host"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"host": "example.com"}, values)
+
+	resolved, skipped, err := resolver.ResolveTextPartially(ctx, "{{ssm:/app/host}} {{ssm:/app/missing}}")
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com {{ssm:/app/missing}}", resolved)
+	assert.Equal(t, []string{"ssm:/app/missing"}, skipped)
+}
+
+func TestResolverOptions(t *testing.T) {
+	resolver := newTestResolver(t, WithOptions(v1.ResolveOptions{Format: "properties"}), WithoutSecureParameters())
+
+	assert.Equal(t, v1.ResolveOptions{Format: "properties", IgnoreSecureParameters: true}, resolver.Options())
+
+	resolved, err := resolver.ResolveText(context.Background(), "{{ssm:/app/host}} {{ssm-secure:/app/password}}")
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com {{ssm-secure:/app/password}}", resolved)
+
+	strict := resolver.With(WithStrictPlaceholders())
+	assert.False(t, resolver.Options().FailOnUnresolvedPlaceholders)
+
+	_, err = strict.ResolveText(context.Background(), "{{ssm:/app/host}} {{ssm:/app/db host}}")
+	var unresolvedPlaceholdersError *UnresolvedPlaceholdersError
+	assert.True(t, errors.As(err, &unresolvedPlaceholdersError))
+	assert.Equal(t, StatusParseError, StatusOf(err))
+}
+
+func TestResolverErrors(t *testing.T) {
+	resolver := newTestResolver(t)
+
+	_, err := resolver.ResolveText(context.Background(), "{{ssm:/app/missing}}")
+	var missingParametersError *MissingParametersError
+	assert.True(t, errors.As(err, &missingParametersError))
+	assert.Equal(t, StatusNotFound, StatusOf(err))
+
+	_, err = resolver.ResolveText(context.Background(), "{{ssm:/app/password}}")
+	var secureParametersNotAllowedError *SecureParametersNotAllowedError
+	assert.True(t, errors.As(err, &secureParametersNotAllowedError))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = resolver.ResolveText(ctx, "{{ssm:/app/host}}")
+	assert.NotNil(t, err)
+}
+
+func TestResolverWithSession(t *testing.T) {
+	resolver := newTestResolver(t, WithoutSecureParameters())
+	session := resolver.WithSession(time.Second)
+
+	assert.Equal(t, resolver.Options(), session.Options())
+
+	resolved, err := session.ResolveText(context.Background(), "{{ssm:/app/host}} {{ssm-secure:/app/password}}")
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com {{ssm-secure:/app/password}}", resolved)
+}
@@ -0,0 +1,137 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+)
+
+//
+// IParameterMetadataService is implemented by the services able to tell the type of parameters without fetching
+// their values, so that validating references needs no permission to decrypt them
+type IParameterMetadataService interface {
+	callGetParameterMetadata(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error)
+}
+
+//
+// Result of ValidateParameterReferences
+type ReferenceValidationReport struct {
+	// Parameter references of the placeholders of the document, sorted
+	References []string
+
+	// References of the parameters, environment variables and values of registered sources that do not exist
+	// or are not accessible, sorted
+	Missing []string
+
+	// References with the non-secure prefix ssm: of SecureString parameters, sorted
+	DisallowedSecure []string
+
+	// References with the secure prefix ssm-secure: of parameters that are not SecureString, sorted
+	NotSecure []string
+
+	// References of Secrets Manager secrets, whose existence is not checked, sorted
+	Unchecked []string
+}
+
+//
+// Reports whether every checked reference can be resolved.
+func (report ReferenceValidationReport) Passed() bool {
+	return len(report.Missing) == 0 && len(report.DisallowedSecure) == 0 && len(report.NotSecure) == 0
+}
+
+//
+// Dry run of the resolution of the document input for CI pipelines: lists the parameter references of its
+// placeholders and checks, like Preflight, that the parameters exist and have the type their prefix declares, without
+// returning their values. Every reference is checked against the source of its prefix: SSM Parameter Store, the
+// environment or a registered source. Services implementing IParameterMetadataService, e.g. Service, do not even
+// fetch SecureString values, the others fetch the values and drop them. Parameters whose values build the names of other parameters,
+// e.g. /app/active-color in {{ssm:/app/{{ssm:/app/active-color}}/endpoint}}, are resolved to find those names.
+// The returned error is set when the document cannot be parsed or the checks cannot be run, e.g. for AWS errors.
+func ValidateParameterReferences(
+	ctx context.Context,
+	service ISsmParameterService,
+	input string,
+	options ResolveOptions) (ReferenceValidationReport, error) {
+
+	report := ReferenceValidationReport{
+		References:       []string{},
+		Missing:          []string{},
+		DisallowedSecure: []string{},
+		NotSecure:        []string{},
+		Unchecked:        []string{},
+	}
+
+	text, err := prepareTemplate(ctx, service, input, options)
+	if err != nil {
+		return report, err
+	}
+
+	parameterReferences, err := parseAndValidatePlaceholders(text, withDefaultPlaceholderSyntax(options))
+	if err != nil {
+		return report, err
+	}
+	sort.Strings(parameterReferences)
+	report.References = append(report.References, parameterReferences...)
+
+	checkedReferences := []string{}
+	for _, ref := range parameterReferences {
+		if isSecretReference(ref) {
+			report.Unchecked = append(report.Unchecked, ref)
+		} else {
+			checkedReferences = append(checkedReferences, ref)
+		}
+	}
+
+	results, _, err := preflightReferences(ctx, service, checkedReferences, options, true)
+	if err != nil {
+		return report, err
+	}
+
+	for _, result := range results {
+		var missingParametersError *MissingParametersError
+		switch {
+		case result.Err == nil:
+		case errors.As(result.Err, &missingParametersError):
+			report.Missing = append(report.Missing, result.Reference)
+		case strings.HasPrefix(result.Reference, ssmSecurePrefix) && len(result.Type) > 0 && result.Type != secureStringType:
+			report.NotSecure = append(report.NotSecure, result.Reference)
+		case strings.HasPrefix(result.Reference, ssmNonSecurePrefix) && result.Type == secureStringType:
+			report.DisallowedSecure = append(report.DisallowedSecure, result.Reference)
+		default:
+			return report, result.Err
+		}
+	}
+
+	return report, nil
+}
+
+// service fetching the metadata of SSM parameters instead of their values, for ValidateParameterReferences
+type metadataOnlyService struct {
+	ISsmParameterService
+}
+
+func (s metadataOnlyService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	return getParameterMetadata(ctx, s.ISsmParameterService, parameterReferences)
+}
+
+// returns the parameters of parameterReferences without their values with service, which fetches the values
+// when it does not implement IParameterMetadataService. Services wrapping another service delegate their
+// callGetParameterMetadata to it with this function.
+func getParameterMetadata(
+	ctx context.Context,
+	service ISsmParameterService,
+	parameterReferences []string) (map[string]SsmParameterInfo, error) {
+
+	if metadataService, ok := service.(IParameterMetadataService); ok {
+		return metadataService.callGetParameterMetadata(ctx, parameterReferences)
+	}
+
+	parameters, err := service.callGetParameters(ctx, parameterReferences)
+	for ref, param := range parameters {
+		param.Value = ""
+		parameters[ref] = param
+	}
+
+	return parameters, err
+}
@@ -0,0 +1,90 @@
+package resolver
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//
+// Mocked service telling the metadata requests from the requests of parameter values
+type metadataServiceMock struct {
+	*missingParametersService
+	valuesRequested bool
+}
+
+func (m *metadataServiceMock) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	m.valuesRequested = true
+	return m.missingParametersService.callGetParameters(ctx, parameterReferences)
+}
+
+func (m *metadataServiceMock) callGetParameterMetadata(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	parameters, err := m.missingParametersService.callGetParameters(ctx, parameterReferences)
+	for ref, param := range parameters {
+		param.Value = ""
+		parameters[ref] = param
+	}
+
+	return parameters, err
+}
+
+func TestValidateParameterReferences(t *testing.T) {
+	serviceObject := &metadataServiceMock{missingParametersService: newMissingParametersService()}
+	serviceObject.records["ssm:/app/token"] = SsmParameterInfo{Name: "/app/token", Type: secureStringType, Value: "t0ken"}
+	serviceObject.records["ssm-secure:/app/host"] = SsmParameterInfo{Name: "/app/host", Type: stringType, Value: "example.com"}
+	os.Setenv("RESOLVER_TEST_REGION", "eu-west-1")
+	defer os.Unsetenv("RESOLVER_TEST_REGION")
+
+	input := "host={{ssm:/app/host}} port={{ssm:/app/port | type=int}} token={{ssm:/app/token}}\n" +
+		"secure={{ssm-secure:/app/token}} {{ssm-secure:/app/host}} timeout={{ssm:/app/timeout}}\n" +
+		"db={{secretsmanager:prod/db}} region={{env:RESOLVER_TEST_REGION}} zone={{env:RESOLVER_TEST_ZONE}}"
+	report, err := ValidateParameterReferences(context.Background(), serviceObject, input,
+		ResolveOptions{AllowedEnvironmentVariables: []string{"RESOLVER_TEST_REGION", "RESOLVER_TEST_ZONE"}})
+
+	assert.Nil(t, err)
+	assert.False(t, report.Passed())
+	assert.Equal(t, []string{"env:RESOLVER_TEST_REGION", "env:RESOLVER_TEST_ZONE", "secretsmanager:prod/db",
+		"ssm-secure:/app/host", "ssm-secure:/app/token", "ssm:/app/host", "ssm:/app/port", "ssm:/app/timeout",
+		"ssm:/app/token"}, report.References)
+	assert.Equal(t, []string{"env:RESOLVER_TEST_ZONE", "ssm:/app/timeout"}, report.Missing)
+	assert.Equal(t, []string{"ssm:/app/token"}, report.DisallowedSecure)
+	assert.Equal(t, []string{"ssm-secure:/app/host"}, report.NotSecure)
+	assert.Equal(t, []string{"secretsmanager:prod/db"}, report.Unchecked)
+	assert.False(t, serviceObject.valuesRequested)
+
+	report, err = ValidateParameterReferences(context.Background(), serviceObject,
+		"{{ssm:/app/host}}:{{ssm:/app/port}}", ResolveOptions{})
+	assert.Nil(t, err)
+	assert.True(t, report.Passed())
+}
+
+func TestValidateParameterReferencesFailsForInvalidTemplates(t *testing.T) {
+	serviceObject := newMissingParametersService()
+
+	_, err := ValidateParameterReferences(context.Background(), serviceObject, "{{ssm:/app/host | nosuchtransformer}}",
+		ResolveOptions{})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, StatusParseError, StatusOf(err))
+}
+
+func TestValidateParameterReferencesOfRegisteredSources(t *testing.T) {
+	fetch := func(ctx context.Context, name string) (string, error) {
+		if name != "secret/app#pw" {
+			return "", &MissingParametersError{Names: []string{name}}
+		}
+		return "s3cr3t", nil
+	}
+	assert.Nil(t, RegisterSource("testvalidate:", Source{Fetch: fetch, Secure: true}))
+	defer unregisterSource("testvalidate:")
+
+	serviceObject := &metadataServiceMock{missingParametersService: newMissingParametersService()}
+	report, err := ValidateParameterReferences(context.Background(), serviceObject,
+		"{{ssm:/app/host}} {{testvalidate:secret/app#pw}} {{testvalidate:secret/app#missing}}", ResolveOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"testvalidate:secret/app#missing"}, report.Missing)
+	assert.False(t, serviceObject.valuesRequested)
+}
@@ -0,0 +1,117 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+//
+// Checks the files written by ResolveParametersInFiles or ResolveParametersInVersionedDirectory, e.g. that a server
+// accepts its new configuration. ctx is the context of the render.
+type VerifyFunc func(ctx context.Context, outputFileNames []string) error
+
+//
+// Time a command run by VerifyCommand is given before it is killed
+const DefaultVerifyCommandTimeout = time.Minute
+
+//
+// Returns a VerifyFunc running a command, e.g. VerifyCommand("nginx", "-t"), that fails when the command
+// exits with a non-zero status. The output of the command is part of the error. The command is killed when
+// it runs longer than DefaultVerifyCommandTimeout or the context of the render is done.
+func VerifyCommand(name string, args ...string) VerifyFunc {
+	return VerifyCommandWithTimeout(DefaultVerifyCommandTimeout, name, args...)
+}
+
+//
+// Same as VerifyCommand, but the command is killed after timeout.
+func VerifyCommandWithTimeout(timeout time.Duration, name string, args ...string) VerifyFunc {
+	return func(ctx context.Context, outputFileNames []string) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s did not finish: %w", name, ctx.Err())
+		}
+		if err != nil {
+			return fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(string(output)))
+		}
+
+		return nil
+	}
+}
+
+// content of an output file before it is written, restored when the written files fail verification
+type previousOutputFile struct {
+	existed bool
+	content []byte
+	mode    os.FileMode
+}
+
+// reads the current content of every output file, the files that do not exist yet are removed on restore
+func savePreviousOutputFiles(outputFileNames []string) (map[string]previousOutputFile, error) {
+	previousFiles := map[string]previousOutputFile{}
+	for _, outputFileName := range outputFileNames {
+		info, err := os.Stat(outputFileName)
+		if errors.Is(err, os.ErrNotExist) {
+			previousFiles[outputFileName] = previousOutputFile{}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := ioutil.ReadFile(outputFileName)
+		if err != nil {
+			return nil, err
+		}
+		previousFiles[outputFileName] = previousOutputFile{existed: true, content: content, mode: info.Mode().Perm()}
+	}
+
+	return previousFiles, nil
+}
+
+// puts back the previous content of every output file atomically, so that an interrupted rollback never leaves
+// an output file truncated. All files are restored even when one fails, the first failure is returned.
+func restorePreviousOutputFiles(outputFileNames []string, previousFiles map[string]previousOutputFile, options ResolveOptions) error {
+	var firstErr error
+	for _, outputFileName := range outputFileNames {
+		previousFile := previousFiles[outputFileName]
+
+		var err error
+		if previousFile.existed {
+			err = retryTransientWrite(options, func() error {
+				return restorePreviousOutputFile(outputFileName, previousFile)
+			})
+		} else if err = os.Remove(outputFileName); errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("cannot restore %s: %w", outputFileName, err)
+		}
+	}
+
+	return firstErr
+}
+
+// replaces the content of the output file with its previous content, the permissions of the output file are set first
+// so that the previous content is never readable with the permissions of the written one
+func restorePreviousOutputFile(outputFileName string, previousFile previousOutputFile) error {
+	err := os.Chmod(outputFileName, previousFile.mode)
+	if errors.Is(err, os.ErrNotExist) {
+		// the write failed before the file was created, there is nothing to truncate
+		return writeToFileWithPermissions(string(previousFile.content), outputFileName, previousFile.mode)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeToFileAtomically(string(previousFile.content), outputFileName, "")
+}
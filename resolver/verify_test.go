@@ -0,0 +1,127 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersInFilesVerified(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	dir, err := ioutil.TempDir("", "verify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "input")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("{{ssm:/app/host}}"), 0644))
+	files := map[string]string{
+		filepath.Join(dir, "a.conf"): inputFileName,
+		filepath.Join(dir, "b.conf"): inputFileName,
+	}
+
+	var verified []string
+	err = ResolveParametersInFiles(&serviceObject, files, ResolveOptions{
+		Verify: func(ctx context.Context, outputFileNames []string) error {
+			verified = outputFileNames
+			output, err := ioutil.ReadFile(outputFileNames[1])
+			assert.Nil(t, err)
+			assert.Equal(t, "db.internal", string(output))
+			return nil
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "a.conf"), filepath.Join(dir, "b.conf")}, verified)
+}
+
+func TestResolveParametersInFilesRollback(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	dir, err := ioutil.TempDir("", "verify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "input")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("{{ssm:/app/host}}"), 0644))
+	existingFileName := filepath.Join(dir, "existing.conf")
+	assert.Nil(t, ioutil.WriteFile(existingFileName, []byte("previous"), 0600))
+	newFileName := filepath.Join(dir, "new.conf")
+
+	verifyErr := errors.New("configuration rejected")
+	err = ResolveParametersInFiles(&serviceObject, map[string]string{
+		existingFileName: inputFileName,
+		newFileName:      inputFileName,
+	}, ResolveOptions{
+		SyncPolicy: SyncPerFile,
+		Verify:     func(ctx context.Context, outputFileNames []string) error { return verifyErr },
+	})
+
+	assert.True(t, errors.Is(err, verifyErr))
+
+	output, err := ioutil.ReadFile(existingFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, "previous", string(output))
+
+	info, err := os.Stat(existingFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	_, err = os.Stat(newFileName)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestResolveParametersInFilesRollbackOnWriteFailure(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	dir, err := ioutil.TempDir("", "verify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "input")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("{{ssm:/app/host}}"), 0644))
+
+	verifyCalled := false
+	err = ResolveParametersInFiles(&serviceObject, map[string]string{
+		filepath.Join(dir, "missing", "a.conf"): inputFileName,
+		filepath.Join(dir, "b.conf"):            inputFileName,
+	}, ResolveOptions{
+		Verify: func(ctx context.Context, outputFileNames []string) error {
+			verifyCalled = true
+			return nil
+		},
+	})
+
+	assert.NotNil(t, err)
+	assert.False(t, verifyCalled)
+	_, err = os.Stat(filepath.Join(dir, "b.conf"))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestVerifyCommand(t *testing.T) {
+	assert.Nil(t, VerifyCommand("sh", "-c", "exit 0")(context.Background(), nil))
+
+	err := VerifyCommand("sh", "-c", "echo syntax error; exit 1")(context.Background(), nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "syntax error")
+}
+
+func TestVerifyCommandTimeout(t *testing.T) {
+	start := time.Now()
+	err := VerifyCommandWithTimeout(10*time.Millisecond, "sh", "-c", "exec sleep 5")(context.Background(), nil)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
@@ -0,0 +1,222 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+//
+// Name of the symbolic link pointing at the version of a versioned output directory that consumers read
+const CurrentVersionLink = "current"
+
+// prefix of the version directories, followed by the UTC time of the render
+const versionDirectoryPrefix = "v"
+
+const versionDirectoryTimeFormat = "20060102T150405.000000000Z"
+
+//
+// Takes a map of (output file name relative to the version directory) to (input file name), resolves SSM parameters
+// in all inputs like ResolveParametersInFiles and writes them into a new version directory under root.
+// Once the files are written, and verified with ResolveOptions.Verify when set, root/current is atomically switched
+// to the new version, so consumers reading through it see either all the previous files or all the new ones.
+// The version directory is removed when the render fails. Returns the name of the new version.
+func ResolveParametersInVersionedDirectory(
+	service ISsmParameterService,
+	root string,
+	files map[string]string,
+	options ResolveOptions) (string, error) {
+
+	return ResolveParametersInVersionedDirectoryWithContext(context.Background(), service, root, files, options)
+}
+
+//
+// Same as ResolveParametersInVersionedDirectory, but the SSM requests are canceled when ctx is done.
+func ResolveParametersInVersionedDirectoryWithContext(
+	ctx context.Context,
+	service ISsmParameterService,
+	root string,
+	files map[string]string,
+	options ResolveOptions) (string, error) {
+
+	if len(root) == 0 {
+		return "", errors.New("output directory is not provided")
+	}
+
+	version, err := createVersionDirectory(root)
+	if err != nil {
+		return "", err
+	}
+	versionDirectory := filepath.Join(root, version)
+
+	outputFiles := map[string]string{}
+	for outputFileName, inputFileName := range files {
+		if filepath.IsAbs(outputFileName) || !isWithinDirectory(outputFileName) {
+			os.RemoveAll(versionDirectory)
+			return "", errors.New("output file name " + outputFileName + " is not relative to the version directory")
+		}
+
+		outputPath := filepath.Join(versionDirectory, outputFileName)
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			os.RemoveAll(versionDirectory)
+			return "", err
+		}
+		outputFiles[outputPath] = inputFileName
+	}
+
+	// the files of a new version have no previous content, a failed version is removed as a whole
+	verify := options.Verify
+	options.Verify = nil
+
+	err = ResolveParametersInFilesWithContext(ctx, service, outputFiles, options)
+	if err == nil && verify != nil {
+		outputFileNames := make([]string, 0, len(outputFiles))
+		for outputPath := range outputFiles {
+			outputFileNames = append(outputFileNames, outputPath)
+		}
+		sort.Strings(outputFileNames)
+
+		err = verify(ctx, outputFileNames)
+		if err != nil {
+			err = fmt.Errorf("verification of version %s failed: %w", version, err)
+		}
+	}
+
+	if err == nil {
+		err = SwitchCurrentVersion(root, version)
+	}
+
+	if err != nil {
+		os.RemoveAll(versionDirectory)
+		return "", err
+	}
+
+	return version, nil
+}
+
+//
+// Atomically points root/current at version, e.g. to roll back to a previous version.
+func SwitchCurrentVersion(root string, version string) error {
+	info, err := os.Stat(filepath.Join(root, version))
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() || !isVersionName(version) {
+		return errors.New(version + " is not a version directory of " + root)
+	}
+
+	// the files of the version have to be on disk before consumers are pointed at them
+	if err := syncDirectoryTree(filepath.Join(root, version)); err != nil {
+		return err
+	}
+
+	// a symbolic link cannot be replaced in place: a new one is renamed over it
+	temporaryLink := filepath.Join(root, "."+CurrentVersionLink+"-"+version)
+	os.Remove(temporaryLink)
+	if err := os.Symlink(version, temporaryLink); err != nil {
+		return err
+	}
+
+	if err := os.Rename(temporaryLink, filepath.Join(root, CurrentVersionLink)); err != nil {
+		os.Remove(temporaryLink)
+		return err
+	}
+
+	// the switch itself is durable once root is flushed, which not every platform supports
+	_ = syncDirectory(root)
+	return nil
+}
+
+//
+// Returns the version root/current points at, empty when it does not exist.
+func CurrentVersion(root string) (string, error) {
+	version, err := os.Readlink(filepath.Join(root, CurrentVersionLink))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+
+	return version, err
+}
+
+//
+// Returns the versions under root, oldest first.
+func Versions(root string) ([]string, error) {
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() && isVersionName(entry.Name()) {
+			versions = append(versions, entry.Name())
+		}
+	}
+	sort.Strings(versions)
+
+	return versions, nil
+}
+
+// creates an empty version directory under root named after the current time
+func createVersionDirectory(root string) (string, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	for {
+		version := versionDirectoryPrefix + now.Format(versionDirectoryTimeFormat)
+		err := os.Mkdir(filepath.Join(root, version), 0755)
+		if err == nil {
+			return version, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", err
+		}
+
+		// another render got the same timestamp
+		now = now.Add(time.Nanosecond)
+	}
+}
+
+// reports whether name is the name of a version directory created by createVersionDirectory
+func isVersionName(name string) bool {
+	if !strings.HasPrefix(name, versionDirectoryPrefix) {
+		return false
+	}
+
+	_, err := time.Parse(versionDirectoryTimeFormat, strings.TrimPrefix(name, versionDirectoryPrefix))
+	return err == nil
+}
+
+// flushes the regular files under directory and the directories themselves to disk
+func syncDirectoryTree(directory string) error {
+	return filepath.Walk(directory, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			// not every platform supports flushing directories
+			_ = syncDirectory(name)
+			return nil
+		}
+		if info.Mode().IsRegular() {
+			return syncFile(name)
+		}
+
+		return nil
+	})
+}
+
+// reports whether the relative name stays inside the directory it is relative to
+func isWithinDirectory(name string) bool {
+	cleaned := filepath.Clean(name)
+	return cleaned != ".." && !strings.HasPrefix(cleaned, ".."+string(filepath.Separator))
+}
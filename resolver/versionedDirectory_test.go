@@ -0,0 +1,109 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersInVersionedDirectory(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	dir, err := ioutil.TempDir("", "versionedDirectory")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "input")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("host={{ssm:/app/host}}"), 0644))
+	root := filepath.Join(dir, "out")
+	files := map[string]string{"app.conf": inputFileName, filepath.Join("conf.d", "db.conf"): inputFileName}
+
+	first, err := ResolveParametersInVersionedDirectory(&serviceObject, root, files, ResolveOptions{})
+	assert.Nil(t, err)
+
+	output, err := ioutil.ReadFile(filepath.Join(root, CurrentVersionLink, "conf.d", "db.conf"))
+	assert.Nil(t, err)
+	assert.Equal(t, "host=db.internal", string(output))
+
+	second, err := ResolveParametersInVersionedDirectory(&serviceObject, root, files, ResolveOptions{})
+	assert.Nil(t, err)
+	assert.True(t, first < second)
+
+	current, err := CurrentVersion(root)
+	assert.Nil(t, err)
+	assert.Equal(t, second, current)
+
+	assert.Nil(t, SwitchCurrentVersion(root, first))
+	current, err = CurrentVersion(root)
+	assert.Nil(t, err)
+	assert.Equal(t, first, current)
+
+	versions, err := Versions(root)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{first, second}, versions)
+}
+
+func TestResolveParametersInVersionedDirectoryVerificationFailure(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	dir, err := ioutil.TempDir("", "versionedDirectory")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "input")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("host={{ssm:/app/host}}"), 0644))
+	root := filepath.Join(dir, "out")
+	files := map[string]string{"app.conf": inputFileName}
+
+	first, err := ResolveParametersInVersionedDirectory(&serviceObject, root, files, ResolveOptions{})
+	assert.Nil(t, err)
+
+	verifyErr := errors.New("configuration rejected")
+	_, err = ResolveParametersInVersionedDirectory(&serviceObject, root, files, ResolveOptions{
+		Verify: func(ctx context.Context, outputFileNames []string) error { return verifyErr },
+	})
+	assert.True(t, errors.Is(err, verifyErr))
+
+	current, err := CurrentVersion(root)
+	assert.Nil(t, err)
+	assert.Equal(t, first, current)
+
+	versions, err := Versions(root)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{first}, versions)
+
+	_, err = ResolveParametersInVersionedDirectory(&serviceObject, root, map[string]string{"../escape.conf": inputFileName}, ResolveOptions{})
+	assert.NotNil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "escape.conf"))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestVersionsIgnoreOtherDirectories(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{})
+
+	dir, err := ioutil.TempDir("", "versionedDirectory")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "input")
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte("static"), 0644))
+	root := filepath.Join(dir, "out")
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "vendor"), 0755))
+
+	version, err := ResolveParametersInVersionedDirectory(&serviceObject, root, map[string]string{"app.conf": inputFileName}, ResolveOptions{})
+	assert.Nil(t, err)
+
+	versions, err := Versions(root)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{version}, versions)
+	assert.NotNil(t, SwitchCurrentVersion(root, "vendor"))
+}
@@ -0,0 +1,66 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// Makes all the references to a parameter in resolvedParametersMap use the same version of it, references selecting
+// a version or a label (e.g. ssm:/app/key:3), secrets and environment variables are left alone. References to one
+// parameter fetched in different GetParameters batches can get different versions when the parameter is rotated in
+// between: they are fetched again together in one request, or fail with ResolveOptions.FailOnVersionChange.
+// It updates resolvedParametersMap in place and returns the references fetched again.
+func reconcileParameterVersions(
+	ctx context.Context,
+	service ISsmParameterService,
+	resolvedParametersMap map[string]SsmParameterInfo,
+	options ResolveOptions) ([]string, error) {
+
+	referencesByName := map[string][]string{}
+	for ref, param := range resolvedParametersMap {
+		name := extractParameterNameFromReference(ref)
+		if parameterName, _, _ := splitParameterSelector(name); parameterName != name || !isSsmReference(ref) {
+			continue
+		}
+		referencesByName[param.Name] = append(referencesByName[param.Name], ref)
+	}
+
+	refetchedReferences := []string{}
+	for name, references := range referencesByName {
+		if len(references) < 2 {
+			continue
+		}
+
+		sort.Slice(references, func(i, j int) bool {
+			return resolvedParametersMap[references[i]].Version < resolvedParametersMap[references[j]].Version
+		})
+		oldest := resolvedParametersMap[references[0]].Version
+		newest := resolvedParametersMap[references[len(references)-1]].Version
+		if oldest == newest {
+			continue
+		}
+
+		if failsOnVersionChange(options) {
+			return nil, errors.New("parameter " + name + " changed from version " + strconv.FormatInt(oldest, 10) +
+				" to " + strconv.FormatInt(newest, 10) + " while being resolved")
+		}
+
+		refetchedParameters, err := service.callGetParameters(ctx, append([]string{}, references...))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ref := range references {
+			param, found := refetchedParameters[ref]
+			if !found {
+				return nil, newMissingParametersError([]string{extractParameterNameFromReference(ref)})
+			}
+			resolvedParametersMap[ref] = param
+		}
+		refetchedReferences = append(refetchedReferences, references...)
+	}
+
+	return refetchedReferences, nil
+}
@@ -0,0 +1,78 @@
+package resolver
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//
+// Mocked service rotating /app/token on every GetParameters request
+type rotatingService struct {
+	ServiceMockedObjectWithRecords
+	calls int64
+}
+
+func (m *rotatingService) callGetParameters(ctx context.Context, parameterReferences []string) (map[string]SsmParameterInfo, error) {
+	m.calls++
+
+	parameters, err := m.ServiceMockedObjectWithRecords.callGetParameters(ctx, parameterReferences)
+	for ref, param := range parameters {
+		if param.Name == "/app/token" {
+			param.Version = m.calls
+			param.Value = "token-v" + strconv.FormatInt(m.calls, 10)
+			parameters[ref] = param
+		}
+	}
+
+	return parameters, err
+}
+
+func newRotatingService() *rotatingService {
+	records := map[string]SsmParameterInfo{}
+	// both references address /app/token, the way a parameter and its alias do
+	records["ssm:/app/token"] = SsmParameterInfo{Name: "/app/token", Type: stringType}
+	records["ssm:/app/token-alias"] = SsmParameterInfo{Name: "/app/token", Type: stringType}
+	return &rotatingService{ServiceMockedObjectWithRecords: NewServiceMockedObjectWithExtraRecords(records)}
+}
+
+func TestReconcileParameterVersionsRefetches(t *testing.T) {
+	serviceObject := newRotatingService()
+
+	resolvedParametersMap := map[string]SsmParameterInfo{}
+	for _, ref := range []string{"ssm:/app/token", "ssm:/app/token-alias"} {
+		parameters, err := serviceObject.callGetParameters(context.Background(), []string{ref})
+		assert.Nil(t, err)
+		resolvedParametersMap[ref] = parameters[ref]
+	}
+
+	refetched, err := reconcileParameterVersions(context.Background(), serviceObject, resolvedParametersMap, ResolveOptions{})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"ssm:/app/token", "ssm:/app/token-alias"}, refetched)
+	assert.Equal(t, "token-v3", resolvedParametersMap["ssm:/app/token"].Value)
+	assert.Equal(t, "token-v3", resolvedParametersMap["ssm:/app/token-alias"].Value)
+}
+
+func TestReconcileParameterVersionsFails(t *testing.T) {
+	serviceObject := newRotatingService()
+
+	resolvedParametersMap := map[string]SsmParameterInfo{}
+	for _, ref := range []string{"ssm:/app/token", "ssm:/app/token-alias"} {
+		parameters, err := serviceObject.callGetParameters(context.Background(), []string{ref})
+		assert.Nil(t, err)
+		resolvedParametersMap[ref] = parameters[ref]
+	}
+
+	_, err := reconcileParameterVersions(context.Background(), serviceObject, resolvedParametersMap, ResolveOptions{FailOnVersionChange: true})
+	assert.EqualError(t, err, "parameter /app/token changed from version 1 to 2 while being resolved")
+
+	sameVersion := map[string]SsmParameterInfo{
+		"ssm:/app/token":       {Name: "/app/token", Type: stringType, Value: "a", Version: 4},
+		"ssm:/app/token-alias": {Name: "/app/token", Type: stringType, Value: "a", Version: 4},
+	}
+	refetched, err := reconcileParameterVersions(context.Background(), serviceObject, sameVersion, ResolveOptions{FailOnVersionChange: true})
+	assert.Nil(t, err)
+	assert.Empty(t, refetched)
+}
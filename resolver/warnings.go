@@ -0,0 +1,150 @@
+package resolver
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+//
+// ValueWarning reports a resolved value that is likely to be misread where it is substituted, e.g. a password with
+// a trailing space or a YAML value holding ": ". Such values render fine but are invisible in the resolved document.
+// The value itself is left out of the warning, it may be a secret.
+type ValueWarning struct {
+	// Parameter reference of the placeholder, e.g. ssm:/app/db/password
+	Reference string
+
+	// Output file of the document, empty when resolving text
+	File string
+
+	// 1-based line of the placeholder in the template
+	Line int
+
+	Message string
+}
+
+//
+// Checks telling whether a value needs quoting in documents of a syntax, keyed by the name of the syntax.
+// Formats escaping the values themselves, e.g. properties or json, need none.
+var quotingChecks = map[string]func(value string) bool{
+	"yaml":  needsYamlQuoting,
+	"env":   needsQuotingFor(" \t#'\"$`\\"),
+	"shell": needsQuotingFor(" \t\n'\"$`\\;&|<>()*?[]#~"),
+}
+
+//
+// Syntaxes of quotingChecks by file name extension, for the documents without a Format
+var quotingExtensions = map[string]string{
+	".yaml": "yaml",
+	".yml":  "yaml",
+	".env":  "env",
+	".sh":   "shell",
+}
+
+// calls options.Warn for every placeholder of text whose value is empty under EmptyValueWarn, starts or ends with
+// whitespace, or needs quoting in the syntax of the document selected by the format or the extension of fileName.
+// Values of placeholders between quotes or passed through an escaping transformer are assumed to be quoted.
+func warnAboutResolvedValues(
+	text string,
+	fileName string,
+	resolvedParametersMap map[string]SsmParameterInfo,
+	options ResolveOptions) {
+
+	if options.Warn == nil {
+		return
+	}
+
+	formatsMutex.RLock()
+	syntax := selectFormatName(options.Format, fileName)
+	formatsMutex.RUnlock()
+	if len(syntax) == 0 {
+		syntax = quotingExtensions[strings.ToLower(filepath.Ext(fileName))]
+	}
+	needsQuoting := quotingChecks[syntax]
+
+	matches := [][]int{}
+	for _, placeholder := range parameterPlaceholders() {
+		matches = append(matches, placeholder.FindAllStringSubmatchIndex(text, -1)...)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i][0] < matches[j][0] })
+
+	for _, match := range matches {
+		ref := text[match[2]:match[3]]
+		param, resolved := resolvedParametersMap[ref]
+		if !resolved {
+			continue
+		}
+
+		warning := ValueWarning{
+			Reference: ref,
+			File:      fileName,
+			Line:      strings.Count(text[:match[0]], "\n") + 1,
+		}
+
+		if len(param.Value) == 0 {
+			if options.OnEmptyValue == EmptyValueWarn {
+				warning.Message = "value is empty"
+				options.Warn(warning)
+			}
+			continue
+		}
+
+		if strings.TrimFunc(param.Value, unicode.IsSpace) != param.Value {
+			warning.Message = "value starts or ends with whitespace"
+			options.Warn(warning)
+		}
+
+		if needsQuoting != nil && !isQuotedPlaceholder(text, match) && needsQuoting(param.Value) {
+			warning.Message = "value holds characters that need quoting in " + syntax
+			options.Warn(warning)
+		}
+	}
+}
+
+// checks if the placeholder of match is enclosed in quotes or its value is escaped by a transformer
+func isQuotedPlaceholder(text string, match []int) bool {
+	for _, name := range escapingTransformers {
+		if hasTransformer(text[match[4]:match[5]], name) {
+			return true
+		}
+	}
+
+	if match[0] == 0 || match[1] == len(text) {
+		return false
+	}
+
+	quote := text[match[0]-1]
+	return (quote == '"' || quote == '\'') && text[match[1]] == quote
+}
+
+// returns a check of values holding any of characters
+func needsQuotingFor(characters string) func(value string) bool {
+	return func(value string) bool {
+		return strings.ContainsAny(value, characters)
+	}
+}
+
+// checks if value would not be read back as the same string when written as a plain YAML scalar.
+// Multi-line values are laid out by the yaml format.
+func needsYamlQuoting(value string) bool {
+	if strings.Contains(value, "\n") {
+		return false
+	}
+
+	if strings.ContainsAny(value[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+
+	if strings.Contains(value, ": ") || strings.Contains(value, " #") || strings.HasSuffix(value, ":") {
+		return true
+	}
+
+	// plain scalars read as other types
+	switch strings.ToLower(value) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,101 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newWarningsServiceMock() ServiceMockedObjectWithRecords {
+	return NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/user":        {Name: "/app/user", Type: stringType, Value: "admin "},
+		"ssm:/app/url":         {Name: "/app/url", Type: stringType, Value: "see: docs"},
+		"ssm:/app/flag":        {Name: "/app/flag", Type: stringType, Value: "yes"},
+		"ssm:/app/host":        {Name: "/app/host", Type: stringType, Value: "db.internal"},
+		"ssm-secure:/app/pass": {Name: "/app/pass", Type: secureStringType, Value: "p@ss word"},
+	})
+}
+
+func TestWarnAboutWhitespaceInText(t *testing.T) {
+	serviceObject := newWarningsServiceMock()
+	warnings := []ValueWarning{}
+	options := ResolveOptions{Warn: func(warning ValueWarning) { warnings = append(warnings, warning) }}
+
+	resolved, err := ResolveParametersInText(&serviceObject, "host={{ssm:/app/host}}\nuser={{ssm:/app/user}}", options)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "host=db.internal\nuser=admin ", resolved)
+	assert.Equal(t, []ValueWarning{
+		{Reference: "ssm:/app/user", Line: 2, Message: "value starts or ends with whitespace"},
+	}, warnings)
+}
+
+func TestWarnAboutQuotingInYamlFile(t *testing.T) {
+	serviceObject := newWarningsServiceMock()
+	warnings := []ValueWarning{}
+	options := ResolveOptions{Warn: func(warning ValueWarning) { warnings = append(warnings, warning) }}
+
+	dir := t.TempDir()
+	inputFileName := filepath.Join(dir, "app.yaml.tmpl")
+	outputFileName := filepath.Join(dir, "app.yaml")
+	template := "url: {{ssm:/app/url}}\nquoted: \"{{ssm:/app/url}}\"\nflag: {{ssm:/app/flag}}\nhost: {{ssm:/app/host}}\n"
+	assert.Nil(t, os.WriteFile(inputFileName, []byte(template), 0600))
+
+	assert.Nil(t, ResolveParametersInFile(&serviceObject, inputFileName, outputFileName, options))
+	assert.Equal(t, []ValueWarning{
+		{Reference: "ssm:/app/url", File: outputFileName, Line: 1, Message: "value holds characters that need quoting in yaml"},
+		{Reference: "ssm:/app/flag", File: outputFileName, Line: 3, Message: "value holds characters that need quoting in yaml"},
+	}, warnings)
+}
+
+func TestWarnAboutQuotingInShellFile(t *testing.T) {
+	serviceObject := newWarningsServiceMock()
+	warnings := []ValueWarning{}
+	options := ResolveOptions{Warn: func(warning ValueWarning) { warnings = append(warnings, warning) }}
+
+	dir := t.TempDir()
+	inputFileName := filepath.Join(dir, "env.tmpl")
+	outputFileName := filepath.Join(dir, "env.sh")
+	template := "PASS={{ssm-secure:/app/pass}}\nQUOTED={{ssm-secure:/app/pass | shellquote}}\n"
+	assert.Nil(t, os.WriteFile(inputFileName, []byte(template), 0600))
+
+	assert.Nil(t, ResolveParametersInFile(&serviceObject, inputFileName, outputFileName, options))
+	assert.Equal(t, []ValueWarning{
+		{Reference: "ssm-secure:/app/pass", File: outputFileName, Line: 1, Message: "value holds characters that need quoting in shell"},
+	}, warnings)
+}
+
+func TestWarnAboutValuesInStream(t *testing.T) {
+	serviceObject := newWarningsServiceMock()
+	warnings := []ValueWarning{}
+	options := ResolveOptions{Warn: func(warning ValueWarning) { warnings = append(warnings, warning) }}
+
+	text := strings.Repeat("host={{ssm:/app/host}}\n", 5) + "user={{ssm:/app/user}}\n"
+	var output bytes.Buffer
+	err := resolveParametersInStream(context.Background(), &serviceObject, strings.NewReader(text), &output, options, 16)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []ValueWarning{
+		{Reference: "ssm:/app/user", Line: 6, Message: "value starts or ends with whitespace"},
+	}, warnings)
+}
+
+func TestNeedsYamlQuoting(t *testing.T) {
+	for value, expected := range map[string]bool{
+		"plain value":  false,
+		"db.internal":  false,
+		"a: b":         true,
+		"- item":       true,
+		"*alias":       true,
+		"value # note": true,
+		"No":           true,
+		"https://x/y":  false,
+	} {
+		assert.Equal(t, expected, needsYamlQuoting(value), value)
+	}
+}
@@ -0,0 +1,123 @@
+package resolver
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//
+// FormatComment turns a note into a comment of a format, e.g. "# note" for properties files
+type FormatComment func(note string) string
+
+//
+// Comment syntax of the formats, used to watermark the documents of the formats listed in ResolveOptions.WatermarkFormats
+var formatComments = map[string]FormatComment{
+	propertiesFormat: func(note string) string { return "# " + note },
+	iniFormat:        func(note string) string { return "; " + note },
+	tomlFormat:       func(note string) string { return "# " + note },
+	xmlFormat:        func(note string) string { return "<!-- " + strings.ReplaceAll(note, "--", "- -") + " -->" },
+}
+
+//
+// Tell whether a comment line can be inserted at the start of the line at offset of a template of the format, for
+// the formats whose comments are not allowed everywhere: inside an XML tag or a TOML multi-line string a comment
+// would break the document or change a value
+var watermarkContexts = map[string]func(template string, offset int) bool{
+	xmlFormat:  func(template string, offset int) bool { return xmlContextAt(template, offset) == xmlText },
+	tomlFormat: func(template string, offset int) bool { return tomlContextAt(template, offset) == tomlBare },
+}
+
+// comment syntax of a format, with where its comments are allowed
+type watermarkSyntax struct {
+	comment FormatComment
+
+	// nil when comments are allowed at the start of every line
+	commentableAt func(template string, offset int) bool
+}
+
+//
+// Registers the comment syntax of the format named name so that its documents can be watermarked.
+func RegisterFormatComment(name string, comment FormatComment) {
+	formatsMutex.Lock()
+	defer formatsMutex.Unlock()
+
+	formatComments[name] = comment
+}
+
+// returns the comment syntax of the format selected like lookupFormat does, or nil when the format
+// is not listed in options.WatermarkFormats or has no comment syntax
+func lookupWatermark(options ResolveOptions, fileName string) *watermarkSyntax {
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+
+	name := selectFormatName(options.Format, fileName)
+	if len(name) == 0 || !containsString(options.WatermarkFormats, name) {
+		return nil
+	}
+
+	comment, found := formatComments[name]
+	if !found {
+		return nil
+	}
+
+	return &watermarkSyntax{comment: comment, commentableAt: watermarkContexts[name]}
+}
+
+// comment line inserted at offset of the original text
+type watermark struct {
+	offset int
+	line   string
+}
+
+// returns the comment lines to insert before every line of text with a placeholder of a resolved parameter, ordered
+// by offset. A line continuing the previous one (ending with a backslash) is not watermarked, a comment would end it,
+// nor is a line starting where the format does not allow comments, e.g. inside an XML tag.
+func watermarkLines(
+	text string,
+	matches [][]int,
+	resolvedParametersMap map[string]SsmParameterInfo,
+	syntax *watermarkSyntax) []watermark {
+
+	newline := "\n"
+	if strings.Contains(text, "\r\n") {
+		newline = "\r\n"
+	}
+
+	notes := map[int][]string{}
+	for _, match := range matches {
+		param, resolved := resolvedParametersMap[text[match[2]:match[3]]]
+		if !resolved {
+			continue
+		}
+
+		lineStart := strings.LastIndexByte(text[:match[0]], '\n') + 1
+		if strings.HasSuffix(strings.TrimRight(text[:lineStart], "\r\n"), "\\") {
+			continue
+		}
+		if syntax.commentableAt != nil && !syntax.commentableAt(text, lineStart) {
+			continue
+		}
+
+		note := param.Name
+		if param.Version > 0 {
+			note += " (version " + strconv.FormatInt(param.Version, 10) + ")"
+		}
+		if !containsString(notes[lineStart], note) {
+			notes[lineStart] = append(notes[lineStart], note)
+		}
+	}
+
+	watermarks := []watermark{}
+	for lineStart, lineNotes := range notes {
+		line := text[lineStart:]
+		indentation := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		watermarks = append(watermarks, watermark{
+			offset: lineStart,
+			line:   indentation + syntax.comment("resolved from SSM parameter "+strings.Join(lineNotes, ", ")) + newline,
+		})
+	}
+	sort.Slice(watermarks, func(i, j int) bool { return watermarks[i].offset < watermarks[j].offset })
+
+	return watermarks
+}
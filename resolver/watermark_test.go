@@ -0,0 +1,79 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveParametersInTextWatermarks(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host":        {Name: "/app/host", Type: stringType, Value: "db.internal", Version: 3},
+		"ssm:/app/port":        {Name: "/app/port", Type: stringType, Value: "5432", Version: 1},
+		"ssm-secure:/app--pwd": {Name: "/app--pwd", Type: secureStringType, Value: "secret"},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject,
+		"[db]\n  url = {{ssm:/app/host}}:{{ssm:/app/port}}/{{ssm:/app/host}}\nname = app",
+		ResolveOptions{Format: iniFormat, WatermarkFormats: []string{iniFormat}})
+	assert.Nil(t, err)
+	assert.Equal(t, "[db]\n"+
+		"  ; resolved from SSM parameter /app/host (version 3), /app/port (version 1)\n"+
+		"  url = db.internal:5432/db.internal\n"+
+		"name = app", output)
+
+	output, err = ResolveParametersInText(&serviceObject,
+		"<db>\r\n<password>{{ssm-secure:/app--pwd}}</password>\r\n</db>",
+		ResolveOptions{Format: xmlFormat, WatermarkFormats: []string{xmlFormat}})
+	assert.Nil(t, err)
+	assert.Equal(t, "<db>\r\n"+
+		"<!-- resolved from SSM parameter /app- -pwd -->\r\n"+
+		"<password>secret</password>\r\n"+
+		"</db>", output)
+}
+
+func TestResolveParametersInTextWatermarksOnlyListedFormats(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal", Version: 3},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject, "host = \"{{ssm:/app/host}}\"",
+		ResolveOptions{Format: tomlFormat, WatermarkFormats: []string{propertiesFormat}})
+	assert.Nil(t, err)
+	assert.Equal(t, "host = \"db.internal\"", output)
+
+	output, err = ResolveParametersInText(&serviceObject, "key = a \\\n  {{ssm:/app/host}}",
+		ResolveOptions{Format: propertiesFormat, WatermarkFormats: []string{propertiesFormat}})
+	assert.Nil(t, err)
+	assert.Equal(t, "key = a \\\n  db.internal", output)
+}
+
+func TestRegisterFormatComment(t *testing.T) {
+	RegisterFormat("conf-test", func(template string, offset int, value string) (string, error) { return value, nil })
+	RegisterFormatComment("conf-test", func(note string) string { return "// " + note })
+
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/host": {Name: "/app/host", Type: stringType, Value: "db.internal"},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject, "host {{ssm:/app/host}};",
+		ResolveOptions{Format: "conf-test", WatermarkFormats: []string{"conf-test"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "// resolved from SSM parameter /app/host\nhost db.internal;", output)
+}
+
+func TestWatermarksSkipLinesWhereCommentsAreNotAllowed(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/a": {Name: "/a", Type: stringType, Value: "v"},
+	})
+
+	output, err := ResolveParametersInText(&serviceObject, "<a\n  b=\"{{ssm:/a}}\"/>\n<c>{{ssm:/a}}</c>",
+		ResolveOptions{Format: xmlFormat, WatermarkFormats: []string{xmlFormat}})
+	assert.Nil(t, err)
+	assert.Equal(t, "<a\n  b=\"v\"/>\n<!-- resolved from SSM parameter /a -->\n<c>v</c>", output)
+
+	output, err = ResolveParametersInText(&serviceObject, "k = \"\"\"\nline {{ssm:/a}}\n\"\"\"\nj = \"{{ssm:/a}}\"",
+		ResolveOptions{Format: tomlFormat, WatermarkFormats: []string{tomlFormat}})
+	assert.Nil(t, err)
+	assert.Equal(t, "k = \"\"\"\nline v\n\"\"\"\n# resolved from SSM parameter /a\nj = \"v\"", output)
+}
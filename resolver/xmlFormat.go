@@ -0,0 +1,136 @@
+package resolver
+
+import (
+	"errors"
+	"strings"
+)
+
+const xmlFormat = "xml"
+
+// syntactic context of a position in an XML document
+type xmlContext int
+
+const (
+	xmlText xmlContext = iota
+	xmlTag
+	xmlDoubleQuotedAttribute
+	xmlSingleQuotedAttribute
+	xmlComment
+	xmlCData
+	xmlProcessingInstruction
+)
+
+//
+// Escapes of values substituted into text nodes and attribute values
+var xmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+var xmlAttributeEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"\"", "&quot;",
+	"'", "&apos;",
+	"\t", "&#9;",
+	"\n", "&#10;",
+	"\r", "&#13;",
+)
+
+// escapes value with entities for the text node or attribute value around offset. CDATA sections are split around
+// ]]> in values. Values cannot be substituted into markup, e.g. element or attribute names.
+func escapeXmlValue(template string, offset int, value string) (string, error) {
+	for _, r := range value {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+			return "", errors.New("value contains characters XML 1.0 does not allow")
+		}
+	}
+
+	switch xmlContextAt(template, offset) {
+	case xmlText:
+		return xmlTextEscaper.Replace(value), nil
+
+	case xmlDoubleQuotedAttribute, xmlSingleQuotedAttribute:
+		return xmlAttributeEscaper.Replace(value), nil
+
+	case xmlCData:
+		return strings.Replace(value, "]]>", "]]]]><![CDATA[>", -1), nil
+
+	case xmlComment:
+		if strings.Contains(value, "--") || strings.HasSuffix(value, "-") {
+			return "", errors.New("value cannot be substituted into an XML comment, it contains --")
+		}
+		return value, nil
+
+	default:
+		return "", errors.New("placeholder is in XML markup, only text nodes and attribute values can be resolved")
+	}
+}
+
+// returns the context of offset scanning template from the start, skipping placeholders
+func xmlContextAt(template string, offset int) xmlContext {
+	context := xmlText
+	for i := 0; i < offset; i++ {
+		if strings.HasPrefix(template[i:], "{{") {
+			if end := strings.Index(template[i:], "}}"); end > 0 && i+end < offset {
+				i += end + 1
+				continue
+			}
+		}
+
+		rest := template[i:]
+		switch context {
+		case xmlText:
+			switch {
+			case strings.HasPrefix(rest, "<!--"):
+				context = xmlComment
+				i += 3
+			case strings.HasPrefix(rest, "<![CDATA["):
+				context = xmlCData
+				i += 8
+			case strings.HasPrefix(rest, "<?"):
+				context = xmlProcessingInstruction
+				i++
+			case rest[0] == '<':
+				context = xmlTag
+			}
+
+		case xmlTag:
+			switch rest[0] {
+			case '"':
+				context = xmlDoubleQuotedAttribute
+			case '\'':
+				context = xmlSingleQuotedAttribute
+			case '>':
+				context = xmlText
+			}
+
+		case xmlDoubleQuotedAttribute:
+			if rest[0] == '"' {
+				context = xmlTag
+			}
+
+		case xmlSingleQuotedAttribute:
+			if rest[0] == '\'' {
+				context = xmlTag
+			}
+
+		case xmlComment:
+			if strings.HasPrefix(rest, "-->") {
+				context = xmlText
+				i += 2
+			}
+
+		case xmlCData:
+			if strings.HasPrefix(rest, "]]>") {
+				context = xmlText
+				i += 2
+			}
+
+		case xmlProcessingInstruction:
+			if strings.HasPrefix(rest, "?>") {
+				context = xmlText
+				i++
+			}
+		}
+	}
+
+	return context
+}
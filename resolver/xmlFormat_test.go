@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeXmlValue(t *testing.T) {
+	testCases := []struct {
+		template string
+		value    string
+		expected string
+		valid    bool
+	}{
+		{`<password>{{ssm:x}}`, `a<b&c>"d'`, `a&lt;b&amp;c&gt;"d'`, true},
+		{`<datasource url="{{ssm:x}}`, `jdbc:x?a=1&b="2"`, `jdbc:x?a=1&amp;b=&quot;2&quot;`, true},
+		{`<datasource url='{{ssm:x}}`, "it's\n", `it&apos;s&#10;`, true},
+		{`<script><![CDATA[{{ssm:x}}`, `if (a]]>b) {}`, `if (a]]]]><![CDATA[>b) {}`, true},
+		{`<!-- owner {{ssm:x}}`, `team`, `team`, true},
+		{`<!-- owner {{ssm:x}}`, `a--b`, ``, false},
+		{`<{{ssm:x}}`, `element`, ``, false},
+		{`<a x="{{ssm:y <!-- " -->}}" b="1">{{ssm:x}}`, `<`, `&lt;`, true},
+		{`<?xml version="1.0"?><a>{{ssm:x}}`, `&`, `&amp;`, true},
+		{`<a>{{ssm:x}}`, "bell\a", ``, false},
+	}
+
+	for _, testCase := range testCases {
+		offset := strings.LastIndex(testCase.template, "{{ssm:x}}")
+		escaped, err := escapeXmlValue(testCase.template, offset, testCase.value)
+		assert.Equal(t, testCase.valid, err == nil, testCase.template)
+		assert.Equal(t, testCase.expected, escaped, testCase.template)
+	}
+}
+
+func TestResolveParametersInTextXmlFormat(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/url":             {Name: "/app/url", Type: stringType, Value: "jdbc:postgresql://db/app?ssl=true&user=app"},
+		"ssm-secure:/app/password": {Name: "/app/password", Type: secureStringType, Value: "<s3cr3t>"},
+	})
+
+	template := `<Resource url="{{ssm:/app/url}}"><password>{{ssm-secure:/app/password}}</password></Resource>`
+	output, err := ResolveParametersInText(&serviceObject, template, ResolveOptions{Format: xmlFormat})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `<Resource url="jdbc:postgresql://db/app?ssl=true&amp;user=app"><password>&lt;s3cr3t&gt;</password></Resource>`, output)
+}
+
+func TestResolveParametersInXmlFileWithEscapingTransformers(t *testing.T) {
+	serviceObject := NewServiceMockedObjectWithExtraRecords(map[string]SsmParameterInfo{
+		"ssm:/app/value": {Name: "/app/value", Type: stringType, Value: `a<b & "c"`},
+	})
+
+	dir, err := ioutil.TempDir("", "xmlFormat")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	inputFileName := filepath.Join(dir, "input")
+	outputFileName := filepath.Join(dir, "app.xml")
+	template := `<a x="{{ssm:/app/value | xmlescape}}">{{ssm:/app/value | htmlescape}}</a><b>{{ssm:/app/value}}</b>`
+	assert.Nil(t, ioutil.WriteFile(inputFileName, []byte(template), 0644))
+
+	assert.Nil(t, ResolveParametersInFile(&serviceObject, inputFileName, outputFileName, ResolveOptions{}))
+
+	output, err := ioutil.ReadFile(outputFileName)
+	assert.Nil(t, err)
+	assert.Equal(t, `<a x="a&lt;b &amp; &quot;c&quot;">a&lt;b &amp; &#34;c&#34;</a><b>a&lt;b &amp; "c"</b>`, string(output))
+}
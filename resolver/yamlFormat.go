@@ -0,0 +1,106 @@
+package resolver
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const yamlFormat = "yaml"
+
+//
+// Text of a line before a placeholder that is the value of a mapping key, e.g. "  name: "
+var yamlKeyPrefix = regexp.MustCompile("^[^\\s#{}\\[\\],][^#{}\\[\\]]*:\\s+$")
+
+// lays out multi-line values, e.g. private keys, so that they do not break the YAML document around offset:
+// the value of a key or a sequence entry becomes a literal block scalar indented under it, a value already on a line
+// of its own, e.g. in a block scalar of the template, gets every line indented like the first one, and a value
+// between double quotes is escaped. Single-line values are substituted as they are.
+func escapeYamlValue(template string, offset int, value string) (string, error) {
+	if !strings.Contains(value, "\n") {
+		return value, nil
+	}
+
+	lineStart := strings.LastIndex(template[:offset], "\n") + 1
+	linePrefix := template[lineStart:offset]
+
+	suffix := template[offset:]
+	if end := strings.Index(suffix, "}}"); end >= 0 {
+		suffix = suffix[end+len("}}"):]
+	}
+	if lineEnd := strings.Index(suffix, "\n"); lineEnd >= 0 {
+		suffix = suffix[:lineEnd]
+	}
+
+	if len(strings.TrimLeft(linePrefix, " \t")) == 0 {
+		return indentYamlLines(value, linePrefix), nil
+	}
+
+	if strings.HasSuffix(linePrefix, "\"") && strings.HasPrefix(suffix, "\"") {
+		quoted := strconv.Quote(value)
+		return quoted[1 : len(quoted)-1], nil
+	}
+
+	indentation := yamlBlockIndentation(linePrefix)
+	if indentation < 0 {
+		return "", errors.New("multi-line value can only be substituted as the value of a YAML key or sequence entry, " +
+			"on a line of its own or between double quotes")
+	}
+
+	// text after the placeholder would end up in the block scalar, and leading spaces would be taken for indentation
+	if len(suffix) > 0 || strings.HasPrefix(strings.TrimLeft(value, "\n"), " ") {
+		return strconv.Quote(value), nil
+	}
+
+	header := "|-"
+	body := value
+	if strings.HasSuffix(value, "\n\n") {
+		header = "|+"
+		body = strings.TrimSuffix(value, "\n")
+	} else if strings.HasSuffix(value, "\n") {
+		header = "|"
+		body = strings.TrimSuffix(value, "\n")
+	}
+
+	return header + "\n" + indentYamlLines(strings.Repeat(" ", indentation)+body, strings.Repeat(" ", indentation)), nil
+}
+
+// returns the column the lines of a block scalar are indented to when it is the value of the key or sequence entry
+// of linePrefix, e.g. 4 for "  key: " or "  - ", or -1 when linePrefix is neither
+func yamlBlockIndentation(linePrefix string) int {
+	column := 0
+	rest := linePrefix
+	for {
+		trimmed := strings.TrimLeft(rest, " ")
+		column += len(rest) - len(trimmed)
+		rest = trimmed
+
+		if !strings.HasPrefix(rest, "- ") {
+			break
+		}
+		if len(strings.TrimSpace(rest[1:])) == 0 {
+			return column + 2
+		}
+		column++
+		rest = rest[1:]
+	}
+
+	if !yamlKeyPrefix.MatchString(rest) {
+		return -1
+	}
+
+	return column + 2
+}
+
+// prefixes every non-empty line of value but the first with indentation
+func indentYamlLines(value string, indentation string) string {
+	lines := strings.Split(value, "\n")
+	for i := 1; i < len(lines); i++ {
+		if len(lines[i]) > 0 {
+			lines[i] = indentation + lines[i]
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}